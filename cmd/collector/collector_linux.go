@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/intel/svr-info/internal/commandfile"
 	"github.com/intel/svr-info/internal/target"
 )
 
@@ -31,7 +32,12 @@ func getUserPath() string {
 	return strings.Join(verifiedPaths, ":")
 }
 
-func runCommand(command string, superuser bool, superuserPassword string, binPath string, timeout int) (stdout string, stderr string, exitCode int, err error) {
+// addCommandVariables defines the profile arguments as shell variables for use by the command
+func addCommandVariables(command string, args commandfile.Arguments) string {
+	return fmt.Sprintf("PROFILE_DURATION=%d\nPROFILE_SAMPLES=%d\n%s", args.ProfileDuration, args.ProfileSamples, command)
+}
+
+func runCommand(command string, superuser bool, runAs string, superuserPassword string, binPath string, timeout int) (stdout string, stderr string, exitCode int, err error) {
 	// explicitly set PATH by pre-pending to command
 	cmdWithPath := command
 	if binPath != "" {
@@ -39,6 +45,9 @@ func runCommand(command string, superuser bool, superuserPassword string, binPat
 		newPath := fmt.Sprintf("%s%c%s", binPath, os.PathListSeparator, path)
 		cmdWithPath = fmt.Sprintf("PATH=\"%s\"\n%s", newPath, command)
 	}
+	if runAs != "" {
+		return runAsUserCommand(cmdWithPath, runAs, superuserPassword, timeout)
+	}
 	if superuser {
 		return runSuperUserCommand(cmdWithPath, superuserPassword, timeout)
 	}
@@ -72,7 +81,34 @@ func runSuperUserCommand(command string, sudoPassword string, timeout int) (stdo
 		return target.RunLocalCommandWithTimeout(cmd, timeout)
 	}
 	// no other options, fail
-	err = fmt.Errorf("no option available to run command as super-user using sudo")
+	err = fmt.Errorf("%w, as super-user", errPrivilegesRequired)
+	return
+}
+
+// runAsUserCommand runs the command as the specified user using sudo
+func runAsUserCommand(command string, user string, sudoPassword string, timeout int) (stdout string, stderr string, exitCode int, err error) {
+	log.Printf("runAsUserCommand Start: %s, user: %s", command, user)
+	defer log.Printf("runAsUserCommand Finish: %s, user: %s", command, user)
+	// root doesn't need a password to run as another user
+	if os.Geteuid() == 0 {
+		cmd := exec.Command("sudo", "-u", user, "bash", "-c", command)
+		return target.RunLocalCommandWithTimeout(cmd, timeout)
+	}
+	// if sudo password was provided, send it to sudo via stdin
+	if sudoPassword != "" {
+		cmd := exec.Command("sudo", "-kS", "-u", user, "bash", "-c", command)
+		pwdNewline := fmt.Sprintf("%s\n", sudoPassword)
+		return target.RunLocalCommandWithInputWithTimeout(cmd, pwdNewline, timeout)
+	}
+	// if password is not required for sudo, simply prepend 'sudo -u <user>'
+	cmd := exec.Command("sudo", "-kn", "-u", user, "true")
+	_, _, _, err = target.RunLocalCommandWithTimeout(cmd, timeout)
+	if err == nil {
+		cmd := exec.Command("sudo", "-u", user, "bash", "-c", command)
+		return target.RunLocalCommandWithTimeout(cmd, timeout)
+	}
+	// no other options, fail
+	err = fmt.Errorf("%w, as user %s", errPrivilegesRequired, user)
 	return
 }
 
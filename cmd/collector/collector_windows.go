@@ -9,10 +9,19 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/intel/svr-info/internal/commandfile"
 	"github.com/intel/svr-info/internal/target"
 )
 
-func runCommand(command string, superuser bool, sudoPassword string, binPath string, timeout int) (stdout string, stderr string, exitCode int, err error) {
+// addCommandVariables returns the command unchanged, commands aren't run by a shell on Windows
+func addCommandVariables(command string, args commandfile.Arguments) string {
+	return command
+}
+
+func runCommand(command string, superuser bool, runAs string, sudoPassword string, binPath string, timeout int) (stdout string, stderr string, exitCode int, err error) {
+	if runAs != "" {
+		log.Printf("run_as is not supported on Windows, running as current user: %s", command)
+	}
 	if superuser {
 		return runSuperUserCommand(command, sudoPassword, timeout)
 	}
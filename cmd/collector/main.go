@@ -6,13 +6,17 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/intel/svr-info/internal/commandfile"
 	"github.com/intel/svr-info/internal/util"
@@ -24,11 +28,22 @@ var (
 	gVersion string = "dev" // build overrides this, see makefile
 )
 
+// ndjsonVersion identifies the format of the NDJSON output, the reporter uses it to
+// recognize NDJSON input
+const ndjsonVersion = 1
+
 type ResultType map[string]string
 
+// errPrivilegesRequired is returned when a command requires elevated privileges, i.e., superuser
+// or run_as, and the collector can't elevate its privileges
+var errPrivilegesRequired = errors.New("no option available to run command using sudo")
+
 type RunConfiguration struct {
-	cmdFile commandfile.CommandFile
-	sudo    string
+	cmdFile         commandfile.CommandFile
+	sudo            string
+	ndjson          bool
+	progressPath    string // see writeProgress, not written when empty
+	redfishInsecure bool   // don't verify the BMC's certificate and allow http, see newRedfishClient
 }
 
 func newRunConfiguration(yamlData []byte) (config *RunConfiguration, err error) {
@@ -43,6 +58,9 @@ func showUsage() {
 	fmt.Println("Usage:")
 	fmt.Println("  [SUDO_PASSWORD=*********] collector < file[.yaml]")
 	fmt.Println("  [SUDO_PASSWORD=*********] collector [OPTION...] file[.yaml]")
+	fmt.Println("  collector -dry-run file[.yaml]")
+	fmt.Println("  collector -ndjson file[.yaml]")
+	fmt.Println("  collector -skip \"ipmitool sel elist,dmidecode\" file[.yaml]")
 	fmt.Println("Options:")
 	flag.PrintDefaults()
 	fmt.Println(
@@ -54,6 +72,13 @@ func showUsage() {
     name - a string that will be the primary key of the output
   Optional arguments
       bin_path - a string containing the path to executables
+      command_timeout - maximum number of seconds to wait for each command
+      redfish_host - BMC host name or address, if set BMC data is collected via Redfish over https,
+                     see -redfish-insecure for self-signed certificates or http
+      redfish_user - BMC user name
+      redfish_pass - BMC password
+      profile_duration - seconds, available to the commands as PROFILE_DURATION (default: 60)
+      profile_samples - number of samples, available to the commands as PROFILE_SAMPLES (default: 30)
   Commands are list items. Command names label the command output.
   Required command attributes:
       command - will be executed by bash:
@@ -61,7 +86,9 @@ func showUsage() {
       superuser: bool indicates need for elevated privilege (default: false)
       run: bool indicates if command will be run (default: false)
       modprobe: comma separated list of kernel modules required to run command
-      parallel: bool indicates if command can be run in parallel with other commands (default: false)`)
+      parallel: bool indicates if command can be run in parallel with other commands (default: false)
+      run_as: name of the user to run the command as, using sudo (default: current user)
+      max_output_bytes: stdout beyond this many bytes is dropped and replaced by a [truncated N bytes] line (default: 0, no limit)`)
 	fmt.Println(
 		`YAML Example:
     arguments:
@@ -76,6 +103,14 @@ func showUsage() {
         command: cpuid -1 | grep family
         modprobe: cpuid
         parallel: true`)
+	fmt.Println(
+		`NDJSON Output (-ndjson):
+  The first line is a header, e.g., {"name":"myhost","ndjson_version":1}
+  Each following line is the result of one command, written when the command completes.`)
+	fmt.Println(
+		`Progress:
+  While commands run, collector.progress, in the working directory, contains the number of
+  completed commands and the total number of commands, e.g., 12/97. It is removed when done.`)
 }
 
 func printResult(out io.Writer, result ResultType, firstCommand bool) error {
@@ -91,6 +126,43 @@ func printResult(out io.Writer, result ResultType, firstCommand bool) error {
 	return nil
 }
 
+// printResultLine prints the result as a single line of JSON, i.e., one NDJSON record
+func printResultLine(out io.Writer, result ResultType) error {
+	b, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s\n", string(b))
+	return nil
+}
+
+// printNDJSONHeader prints the first line of NDJSON output
+func printNDJSONHeader(out io.Writer, name string) error {
+	b, err := json.Marshal(map[string]interface{}{"ndjson_version": ndjsonVersion, "name": name})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s\n", string(b))
+	return nil
+}
+
+// truncateOutput keeps, at most, the first maxBytes of output, ending on a UTF-8 character
+// boundary, and appends a line with the number of bytes removed
+func truncateOutput(output string, maxBytes int) string {
+	if len(output) <= maxBytes {
+		return output
+	}
+	end := maxBytes
+	for end > 0 && !utf8.RuneStart(output[end]) {
+		end--
+	}
+	kept := output[:end]
+	if !strings.HasSuffix(kept, "\n") {
+		kept += "\n"
+	}
+	return fmt.Sprintf("%s[truncated %d bytes]\n", kept, len(output)-end)
+}
+
 func runConfigCommand(cmd commandfile.Command, args commandfile.Arguments, sudo string, ch chan ResultType) {
 	result := make(ResultType)
 	result["label"] = cmd.Label
@@ -100,39 +172,87 @@ func runConfigCommand(cmd commandfile.Command, args commandfile.Arguments, sudo
 	} else {
 		result["superuser"] = "false"
 	}
-	stdout, stderr, exitCode, err := runCommand(cmd.Command, cmd.Superuser, sudo, args.Binpath, args.Timeout)
+	if cmd.RunAs != "" {
+		result["run_as"] = cmd.RunAs
+	}
+	start := time.Now()
+	stdout, stderr, exitCode, err := runCommand(addCommandVariables(cmd.Command, args), cmd.Superuser, cmd.RunAs, sudo, args.Binpath, args.Timeout)
+	duration := time.Since(start)
 	if err != nil {
 		log.Printf("Error: %v Stderr: %s, Exit Code: %d", err, stderr, exitCode)
+		// tell the reporter why the command has no output
+		if errors.Is(err, errPrivilegesRequired) {
+			result["skipped"] = "privileges"
+		}
+	}
+	if cmd.MaxOutputBytes > 0 && len(stdout) > cmd.MaxOutputBytes {
+		log.Printf("Truncating output of %s from %d to %d bytes", cmd.Label, len(stdout), cmd.MaxOutputBytes)
+		stdout = truncateOutput(stdout, cmd.MaxOutputBytes)
 	}
 	result["stdout"] = stdout
 	result["stderr"] = stderr
 	result["exitstatus"] = fmt.Sprint(exitCode)
+	result["duration_ms"] = fmt.Sprint(duration.Milliseconds()) // wall-clock time, used to find slow commands
 	ch <- result
 }
 
-func runConfigCommands(config *RunConfiguration, out io.Writer) error {
-	// build a unique list of loadable kernel modules that must be installed
+// getRequiredMods returns a unique list of the loadable kernel modules required by the
+// commands that will be run
+func getRequiredMods(commands []commandfile.Command) (mods []string) {
 	install := make(map[string]int)
-	for _, cmd := range config.cmdFile.Commands {
+	for _, cmd := range commands {
 		if cmd.Run && cmd.Modprobe != "" {
 			modList := strings.Split(cmd.Modprobe, ",")
 			for _, mod := range modList {
-				install[mod] = 1
+				if _, ok := install[mod]; !ok {
+					install[mod] = 1
+					mods = append(mods, mod)
+				}
 			}
 		}
 	}
-	// install all loadable kernel modules
-	mods := make([]string, 0, len(install))
-	for mod := range install {
-		mods = append(mods, mod)
+	return
+}
+
+// filterCommands overrides the commands' run attribute by label, commands in the only list
+// are run and all others are not, commands in the skip list are not run
+func filterCommands(commands []commandfile.Command, only []string, skip []string) (err error) {
+	labels := make(map[string]bool)
+	for _, cmd := range commands {
+		labels[cmd.Label] = true
 	}
-	modList := strings.Join(mods, ",")
-	installedMods := installMods(modList, config.sudo)
-	defer uninstallMods(installedMods, config.sudo)
-	// separate commands into parallel (those that can run in parallel) and serial
-	var parallelCommands []commandfile.Command
-	var serialCommands []commandfile.Command
-	for _, cmd := range config.cmdFile.Commands {
+	for _, label := range append(append([]string{}, only...), skip...) {
+		if !labels[label] {
+			err = fmt.Errorf("command not found: %s", label)
+			return
+		}
+	}
+	for i := range commands {
+		if len(only) > 0 {
+			commands[i].Run = slices.Contains(only, commands[i].Label)
+		}
+		if slices.Contains(skip, commands[i].Label) {
+			commands[i].Run = false
+		}
+	}
+	return
+}
+
+// splitLabels splits a comma separated list of command labels
+func splitLabels(list string) (labels []string) {
+	for _, label := range strings.Split(list, ",") {
+		label = strings.TrimSpace(label)
+		if label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return
+}
+
+// separateCommands splits the commands that will be run into those that can run in parallel
+// and those that must run serially
+func separateCommands(commands []commandfile.Command) (parallelCommands []commandfile.Command, serialCommands []commandfile.Command) {
+	for _, cmd := range commands {
 		if cmd.Run {
 			if cmd.Parallel {
 				parallelCommands = append(parallelCommands, cmd)
@@ -141,39 +261,191 @@ func runConfigCommands(config *RunConfiguration, out io.Writer) error {
 			}
 		}
 	}
+	return
+}
+
+// writeProgress writes the number of completed commands and the total number of commands,
+// e.g., 12/97, to the progress file. The orchestrator reads the file while the collector is
+// running to show how far along the collection is. The file is replaced, not rewritten, so
+// that readers never see a partial line.
+func writeProgress(path string, completed int, total int) {
+	if path == "" {
+		return
+	}
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, []byte(fmt.Sprintf("%d/%d\n", completed, total)), 0644); err != nil {
+		log.Printf("Error: failed to write progress: %v", err)
+		return
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		log.Printf("Error: failed to write progress: %v", err)
+	}
+}
+
+func runConfigCommands(config *RunConfiguration, out io.Writer) error {
+	// install all loadable kernel modules
+	modList := strings.Join(getRequiredMods(config.cmdFile.Commands), ",")
+	installedMods := installMods(modList, config.sudo)
+	defer uninstallMods(installedMods, config.sudo)
+	// separate commands into parallel (those that can run in parallel) and serial
+	parallelCommands, serialCommands := separateCommands(config.cmdFile.Commands)
+	totalCommands := len(serialCommands) + len(parallelCommands)
+	if config.cmdFile.Args.RedfishHost != "" {
+		totalCommands++
+	}
+	completedCommands := 0
+	writeProgress(config.progressPath, completedCommands, totalCommands)
+	printCommandResult := func(result ResultType, firstCommand bool) error {
+		if config.ndjson {
+			return printResultLine(out, result)
+		}
+		return printResult(out, result, firstCommand)
+	}
 	// run serial commands one at a time
 	// we run these first because they, typically, are more time sensitive...especially for profiling
 	ch := make(chan ResultType)
 	for idx, cmd := range serialCommands {
 		go runConfigCommand(cmd, config.cmdFile.Args, config.sudo, ch)
 		result := <-ch
-		err := printResult(out, result, idx == 0)
+		err := printCommandResult(result, idx == 0)
 		if err != nil {
 			log.Printf("Error: %v", err)
 			return err
 		}
+		completedCommands++
+		writeProgress(config.progressPath, completedCommands, totalCommands)
 	}
 	// run parallel commands in parallel goroutines
 	for _, cmd := range parallelCommands {
 		go runConfigCommand(cmd, config.cmdFile.Args, config.sudo, ch)
 	}
-	for idx := range parallelCommands {
+	numParallel := len(parallelCommands)
+	// query the BMC, if configured, in parallel with the other parallel commands
+	if config.cmdFile.Args.RedfishHost != "" {
+		go runRedfishCommand(config.cmdFile.Args, config.redfishInsecure, ch)
+		numParallel++
+	}
+	for idx := 0; idx < numParallel; idx++ {
 		result := <-ch
-		err := printResult(out, result, (idx+len(serialCommands)) == 0)
+		err := printCommandResult(result, (idx+len(serialCommands)) == 0)
 		if err != nil {
 			log.Printf("Error: %v", err)
 			return err
 		}
+		completedCommands++
+		writeProgress(config.progressPath, completedCommands, totalCommands)
+	}
+	return nil
+}
+
+// PlanCommand describes how a single command would be run
+type PlanCommand struct {
+	Label          string   `json:"label"`
+	Command        string   `json:"command"`
+	Phase          string   `json:"phase"`
+	Superuser      bool     `json:"superuser"`
+	RunAs          string   `json:"run_as,omitempty"`
+	Modprobe       []string `json:"modprobe"`
+	MaxOutputBytes int      `json:"max_output_bytes,omitempty"` // 0 for no limit
+}
+
+// PlanProfile describes the profile arguments available to the commands
+type PlanProfile struct {
+	Duration int `json:"duration"`
+	Samples  int `json:"samples"`
+}
+
+// RunPlan describes the commands that would be run, and how, without running them
+type RunPlan struct {
+	Name     string        `json:"name"`
+	BinPath  string        `json:"bin_path"`
+	Timeout  int           `json:"command_timeout"`
+	Profile  PlanProfile   `json:"profile"`
+	Modules  []string      `json:"modules"`
+	Commands []PlanCommand `json:"commands"`
+	Skipped  []string      `json:"skipped"`
+}
+
+func newPlanCommand(cmd commandfile.Command, phase string) (planCmd PlanCommand) {
+	planCmd = PlanCommand{
+		Label:          cmd.Label,
+		Command:        cmd.Command,
+		Phase:          phase,
+		Superuser:      cmd.Superuser,
+		RunAs:          cmd.RunAs,
+		Modprobe:       []string{},
+		MaxOutputBytes: cmd.MaxOutputBytes,
+	}
+	if cmd.Modprobe != "" {
+		planCmd.Modprobe = strings.Split(cmd.Modprobe, ",")
+	}
+	return
+}
+
+// getRunPlan resolves the commands that would be run in the order and phase
+// they would be run by runConfigCommands
+func getRunPlan(config *RunConfiguration) (plan RunPlan) {
+	plan = RunPlan{
+		Name:     config.cmdFile.Args.Name,
+		BinPath:  config.cmdFile.Args.Binpath,
+		Timeout:  config.cmdFile.Args.Timeout,
+		Profile:  PlanProfile{Duration: config.cmdFile.Args.ProfileDuration, Samples: config.cmdFile.Args.ProfileSamples},
+		Modules:  getRequiredMods(config.cmdFile.Commands),
+		Commands: []PlanCommand{},
+		Skipped:  []string{},
+	}
+	if plan.Modules == nil {
+		plan.Modules = []string{}
+	}
+	parallelCommands, serialCommands := separateCommands(config.cmdFile.Commands)
+	for _, cmd := range serialCommands {
+		plan.Commands = append(plan.Commands, newPlanCommand(cmd, "serial"))
+	}
+	for _, cmd := range parallelCommands {
+		plan.Commands = append(plan.Commands, newPlanCommand(cmd, "parallel"))
+	}
+	if config.cmdFile.Args.RedfishHost != "" {
+		plan.Commands = append(plan.Commands, PlanCommand{
+			Label:    redfishLabel,
+			Command:  getRedfishCommand(config.cmdFile.Args),
+			Phase:    "parallel",
+			Modprobe: []string{},
+		})
+	}
+	for _, cmd := range config.cmdFile.Commands {
+		if !cmd.Run {
+			plan.Skipped = append(plan.Skipped, cmd.Label)
+		}
+	}
+	return
+}
+
+func printRunPlan(out io.Writer, config *RunConfiguration) error {
+	b, err := json.MarshalIndent(getRunPlan(config), "", "  ")
+	if err != nil {
+		return err
 	}
+	fmt.Fprintf(out, "%s\n", string(b))
 	return nil
 }
 
 func mainReturnWithCode() int {
 	var showHelp bool
 	var showVersion bool
+	var dryRun bool
+	var ndjson bool
+	var only string
+	var skip string
+	var redfishInsecure bool
 	flag.Usage = func() { showUsage() } // override default usage output
 	flag.BoolVar(&showHelp, "h", false, "Print this usage message.")
 	flag.BoolVar(&showVersion, "v", false, "Print program version.")
+	flag.BoolVar(&dryRun, "n", false, "Print the commands that would be run, as JSON, without running them.")
+	flag.BoolVar(&dryRun, "dry-run", false, "Print the commands that would be run, as JSON, without running them.")
+	flag.BoolVar(&ndjson, "ndjson", false, "Print each command's result as a separate line of JSON (NDJSON) as it completes.")
+	flag.StringVar(&only, "only", "", "Comma separated list of command labels to run, all other commands are not run. Overrides the commands' run attribute.")
+	flag.StringVar(&skip, "skip", "", "Comma separated list of command labels to not run. Overrides the commands' run attribute.")
+	flag.BoolVar(&redfishInsecure, "redfish-insecure", false, "Don't verify the BMC's TLS certificate, e.g., when self-signed, and allow http:// redfish_host URLs, which send the BMC credentials in cleartext.")
 	flag.Parse()
 	if showHelp {
 		showUsage()
@@ -246,10 +518,43 @@ func mainReturnWithCode() int {
 		log.Printf("Error: %v", err)
 		return 1
 	}
+	if only != "" || skip != "" {
+		err = filterCommands(runConfig.cmdFile.Commands, splitLabels(only), splitLabels(skip))
+		if err != nil {
+			log.Printf("Error: %v", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
 	runConfig.sudo = os.Getenv("SUDO_PASSWORD")
+	runConfig.ndjson = ndjson
+	runConfig.redfishInsecure = redfishInsecure
+
+	// print the plan instead of running the commands
+	if dryRun {
+		err = printRunPlan(os.Stdout, runConfig)
+		if err != nil {
+			log.Printf("Error: %v", err)
+			return 1
+		}
+		log.Print("All done (dry run).")
+		return 0
+	}
+
+	// write progress to file, next to the pid file, removed when done
+	runConfig.progressPath = filepath.Base(os.Args[0]) + ".progress"
+	defer os.Remove(runConfig.progressPath)
 
 	// start json
-	fmt.Printf("{\n\"%s\": [\n", runConfig.cmdFile.Args.Name)
+	if ndjson {
+		err = printNDJSONHeader(os.Stdout, runConfig.cmdFile.Args.Name)
+		if err != nil {
+			log.Printf("Error: %v", err)
+			return 1
+		}
+	} else {
+		fmt.Printf("{\n\"%s\": [\n", runConfig.cmdFile.Args.Name)
+	}
 
 	// run commands - prints json formatted output for each command
 	err = runConfigCommands(runConfig, os.Stdout)
@@ -258,7 +563,9 @@ func mainReturnWithCode() int {
 	}
 
 	// end json
-	fmt.Printf("]\n}\n")
+	if !ndjson {
+		fmt.Printf("]\n}\n")
+	}
 
 	log.Print("All done.")
 
@@ -0,0 +1,192 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/intel/svr-info/internal/commandfile"
+)
+
+const redfishLabel = "redfish"
+
+// RedfishManager is a BMC and its network interfaces
+type RedfishManager struct {
+	Manager            json.RawMessage   `json:"Manager"`
+	EthernetInterfaces []json.RawMessage `json:"EthernetInterfaces"`
+}
+
+// RedfishData is written to stdout of the redfish command result
+type RedfishData struct {
+	Systems  []json.RawMessage `json:"Systems"`
+	Managers []RedfishManager  `json:"Managers"`
+}
+
+type redfishClient struct {
+	client   *http.Client
+	baseURL  string
+	user     string
+	password string
+}
+
+// getRedfishBaseURL returns the BMC's URL, https unless the host specifies the scheme
+func getRedfishBaseURL(host string) string {
+	baseURL := host
+	if !strings.HasPrefix(baseURL, "https://") && !strings.HasPrefix(baseURL, "http://") {
+		baseURL = "https://" + baseURL
+	}
+	return strings.TrimSuffix(baseURL, "/")
+}
+
+// newRedfishClient returns a client that verifies the BMC's certificate. When insecure, e.g., for
+// BMCs with self-signed certificates, the certificate isn't verified and http URLs are accepted,
+// i.e., the credentials may be sent in cleartext.
+func newRedfishClient(args commandfile.Arguments, insecure bool) (rc *redfishClient, err error) {
+	baseURL := getRedfishBaseURL(args.RedfishHost)
+	if strings.HasPrefix(baseURL, "http://") && !insecure {
+		err = fmt.Errorf("redfish_host %s : http sends the BMC credentials in cleartext, use https or -redfish-insecure", args.RedfishHost)
+		return
+	}
+	rc = &redfishClient{
+		client: &http.Client{
+			Timeout: time.Duration(args.Timeout) * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure}, // #nosec G402
+			},
+		},
+		baseURL:  baseURL,
+		user:     args.RedfishUser,
+		password: args.RedfishPass,
+	}
+	return
+}
+
+// get returns the body of the resource at the given path, e.g., /redfish/v1/Systems
+func (rc *redfishClient) get(path string) (body []byte, err error) {
+	req, err := http.NewRequest(http.MethodGet, rc.baseURL+path, nil)
+	if err != nil {
+		return
+	}
+	req.SetBasicAuth(rc.user, rc.password)
+	req.Header.Set("Accept", "application/json")
+	resp, err := rc.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	return
+}
+
+// getMembers returns the resources that are members of the collection at the given path
+func (rc *redfishClient) getMembers(path string) (members []json.RawMessage, err error) {
+	body, err := rc.get(path)
+	if err != nil {
+		return
+	}
+	var collection struct {
+		Members []struct {
+			ID string `json:"@odata.id"`
+		} `json:"Members"`
+	}
+	err = json.Unmarshal(body, &collection)
+	if err != nil {
+		err = fmt.Errorf("failed to parse %s: %v", path, err)
+		return
+	}
+	for _, member := range collection.Members {
+		var memberBody []byte
+		memberBody, err = rc.get(member.ID)
+		if err != nil {
+			return
+		}
+		members = append(members, json.RawMessage(memberBody))
+	}
+	return
+}
+
+func (rc *redfishClient) getData() (data RedfishData, err error) {
+	data.Systems, err = rc.getMembers("/redfish/v1/Systems")
+	if err != nil {
+		return
+	}
+	var managers []json.RawMessage
+	managers, err = rc.getMembers("/redfish/v1/Managers")
+	if err != nil {
+		return
+	}
+	for _, manager := range managers {
+		redfishManager := RedfishManager{Manager: manager}
+		var links struct {
+			EthernetInterfaces struct {
+				ID string `json:"@odata.id"`
+			} `json:"EthernetInterfaces"`
+		}
+		err = json.Unmarshal(manager, &links)
+		if err != nil {
+			return
+		}
+		if links.EthernetInterfaces.ID != "" {
+			redfishManager.EthernetInterfaces, err = rc.getMembers(links.EthernetInterfaces.ID)
+			if err != nil {
+				return
+			}
+		}
+		data.Managers = append(data.Managers, redfishManager)
+	}
+	return
+}
+
+// getRedfishCommand describes the redfish query for the command field of the result
+func getRedfishCommand(args commandfile.Arguments) string {
+	baseURL := getRedfishBaseURL(args.RedfishHost)
+	return fmt.Sprintf("GET %s/redfish/v1/Systems %s/redfish/v1/Managers", baseURL, baseURL)
+}
+
+// runRedfishCommand queries the BMC's Redfish service and reports the result as if it were a command
+func runRedfishCommand(args commandfile.Arguments, insecure bool, ch chan ResultType) {
+	result := make(ResultType)
+	result["label"] = redfishLabel
+	result["command"] = getRedfishCommand(args)
+	result["superuser"] = "false"
+	result["stdout"] = ""
+	result["stderr"] = ""
+	result["exitstatus"] = "0"
+	var data RedfishData
+	rc, err := newRedfishClient(args, insecure)
+	if err == nil {
+		data, err = rc.getData()
+	}
+	if err != nil {
+		log.Printf("Error: %v", err)
+		result["stderr"] = err.Error()
+		result["exitstatus"] = "1"
+		ch <- result
+		return
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Error: %v", err)
+		result["stderr"] = err.Error()
+		result["exitstatus"] = "1"
+		ch <- result
+		return
+	}
+	result["stdout"] = string(b)
+	ch <- result
+}
@@ -20,6 +20,7 @@ type CmdLineArgs struct {
 	version    bool
 	processor  int
 	iterations int
+	bitrange   string
 	msrs       []uint64
 }
 
@@ -33,6 +34,7 @@ func showUsage() {
 	appName := filepath.Base(os.Args[0])
 	fmt.Fprintf(os.Stderr, "Usage: %s <args> msr1 msr2 msr3\n", appName)
 	fmt.Fprintf(os.Stderr, "Example: %s -i 6 -p 0 0x123 0x234\n", appName)
+	fmt.Fprintf(os.Stderr, "Example: %s -f 22:22 0x123\n", appName)
 	flag.PrintDefaults()
 }
 
@@ -47,6 +49,7 @@ func init() {
 	flag.BoolVar(&gCmdLineArgs.version, "v", false, "Print program version.")
 	flag.IntVar(&gCmdLineArgs.iterations, "i", 6, "Number of iterations.")
 	flag.IntVar(&gCmdLineArgs.processor, "p", 0, "Select processor number.")
+	flag.StringVar(&gCmdLineArgs.bitrange, "f", "", "Compare bits [h:l] only")
 	flag.Parse()
 	if gCmdLineArgs.help || gCmdLineArgs.version {
 		return
@@ -69,6 +72,15 @@ func init() {
 			gCmdLineArgs.msrs = append(gCmdLineArgs.msrs, uint64(msr))
 		}
 	}
+	// validate input flag arguments
+	if gCmdLineArgs.bitrange != "" {
+		_, _, err := msr.ParseBitRange(gCmdLineArgs.bitrange)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			showUsage()
+			os.Exit(1)
+		}
+	}
 }
 
 type msrVals struct {
@@ -123,6 +135,16 @@ func mainReturnWithCode() int {
 		fmt.Fprintf(os.Stderr, "%v", err)
 		return 1
 	}
+	// only the bits in the range are considered when comparing values, set before
+	// reading in parallel since the reader is shared
+	if gCmdLineArgs.bitrange != "" {
+		highBit, lowBit, _ := msr.ParseBitRange(gCmdLineArgs.bitrange)
+		err = msrReader.SetBitRange(highBit, lowBit)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
 	// run in parallel
 	ch := make(chan msrVals)
 	for i, msr := range gCmdLineArgs.msrs {
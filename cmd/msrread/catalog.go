@@ -0,0 +1,105 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/intel/svr-info/internal/msr"
+	"gopkg.in/yaml.v2"
+)
+
+// CatalogField is a named range of bits within an MSR
+type CatalogField struct {
+	Name   string            `yaml:"name"`
+	Bits   string            `yaml:"bits"`   // "h:l" or, for a single bit, "n"
+	Values map[uint64]string `yaml:"values"` // optional meaning of each field value
+}
+
+// CatalogEntry is one MSR in the catalog
+type CatalogEntry struct {
+	Name    string         `yaml:"name"`
+	Address uint64         `yaml:"address"`
+	Bits    []CatalogField `yaml:"bits"`
+}
+
+// loadCatalog reads and validates the MSR catalog YAML file
+func loadCatalog(path string) (catalog []CatalogEntry, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	if err = yaml.UnmarshalStrict(content, &catalog); err != nil {
+		err = fmt.Errorf("failed to parse catalog %s: %v", path, err)
+		return
+	}
+	if len(catalog) == 0 {
+		err = fmt.Errorf("catalog %s has no entries", path)
+		return
+	}
+	for _, entry := range catalog {
+		if entry.Name == "" {
+			err = fmt.Errorf("catalog entry at address 0x%x has no name", entry.Address)
+			return
+		}
+		for _, field := range entry.Bits {
+			if _, _, err = parseFieldBits(field.Bits); err != nil {
+				err = fmt.Errorf("%s, %s: %v", entry.Name, field.Name, err)
+				return
+			}
+		}
+	}
+	return
+}
+
+// parseFieldBits parses a catalog field's bit range, e.g., "15:8" or "22"
+func parseFieldBits(bits string) (highBit, lowBit int, err error) {
+	bits = strings.TrimSpace(bits)
+	if !strings.Contains(bits, ":") {
+		bits = bits + ":" + bits
+	}
+	return msr.ParseBitRange(bits)
+}
+
+// fieldValue returns the value of the bits from highBit to lowBit, inclusive, in the MSR value
+func fieldValue(raw uint64, highBit, lowBit int) (val uint64) {
+	val = raw >> uint64(lowBit)
+	if bits := highBit - lowBit + 1; bits < 64 {
+		val &= (uint64(1) << bits) - 1
+	}
+	return
+}
+
+// printCatalog reads each MSR in the catalog on the processor and writes a table of the raw
+// values followed by the value of each of the MSR's fields. Each MSR is read once, its fields
+// are decoded from that value. MSRs that can't be read, e.g., they're not supported by the
+// processor, are reported and skipped.
+func printCatalog(w io.Writer, msrReader *msr.MSR, catalog []CatalogEntry, processor int) (err error) {
+	if err = msrReader.SetBitRange(63, 0); err != nil {
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MSR\tAddress\tBits\tValue\tMeaning")
+	for _, entry := range catalog {
+		var raw uint64
+		if raw, err = msrReader.ReadOne(entry.Address, processor); err != nil {
+			fmt.Fprintf(tw, "%s\t0x%x\t63:0\tread failed\t%v\n", entry.Name, entry.Address, err)
+			err = nil
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t0x%x\t63:0\t0x%016x\t\n", entry.Name, entry.Address, raw)
+		for _, field := range entry.Bits {
+			highBit, lowBit, _ := parseFieldBits(field.Bits) // validated in loadCatalog
+			val := fieldValue(raw, highBit, lowBit)
+			fmt.Fprintf(tw, "  %s\t\t%s\t0x%x\t%s\n", field.Name, field.Bits, val, field.Values[val])
+		}
+	}
+	err = tw.Flush()
+	return
+}
@@ -10,7 +10,6 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
-	"strings"
 
 	"github.com/intel/svr-info/internal/msr"
 )
@@ -22,6 +21,7 @@ type CmdLineArgs struct {
 	processor int
 	socket    bool
 	bitrange  string
+	catalog   string
 	msr       uint64
 }
 
@@ -35,6 +35,7 @@ func showUsage() {
 	appName := filepath.Base(os.Args[0])
 	fmt.Fprintf(os.Stderr, "Usage: %s <args> msr\n", appName)
 	fmt.Fprintf(os.Stderr, "Example: %s -p 1 0x123\n", appName)
+	fmt.Fprintf(os.Stderr, "Example: %s -p 1 -catalog msrs.yaml\n", appName)
 	flag.PrintDefaults()
 }
 
@@ -42,26 +43,6 @@ func showVersion() {
 	fmt.Println(gVersion)
 }
 
-func parseBitrangeArg() (highBit, lowBit int, err error) {
-	bitrangeOK := false
-	fields := strings.Split(gCmdLineArgs.bitrange, ":")
-	if len(fields) == 2 {
-		highBit, err = strconv.Atoi(fields[0])
-		if err == nil && highBit > 0 && highBit <= 63 {
-			lowBit, err = strconv.Atoi(fields[1])
-			if err == nil && lowBit >= 0 && lowBit < 63 {
-				if highBit > lowBit {
-					bitrangeOK = true
-				}
-			}
-		}
-	}
-	if !bitrangeOK {
-		err = fmt.Errorf("failed to parse bit range: %s", gCmdLineArgs.bitrange)
-	}
-	return
-}
-
 func init() {
 	// init command line flags
 	flag.Usage = func() { showUsage() } // override default usage output
@@ -71,10 +52,20 @@ func init() {
 	flag.IntVar(&gCmdLineArgs.processor, "p", 0, "Select processor number.")
 	flag.BoolVar(&gCmdLineArgs.socket, "s", false, "Read for one processor on each socket (package/CPU).")
 	flag.StringVar(&gCmdLineArgs.bitrange, "f", "", "Output bits [h:l] only")
+	flag.StringVar(&gCmdLineArgs.catalog, "catalog", "", "Read and decode the MSRs listed in this YAML catalog file for the selected processor.")
 	flag.Parse()
 	if gCmdLineArgs.help || gCmdLineArgs.version {
 		return
 	}
+	// the catalog replaces the positional arg
+	if gCmdLineArgs.catalog != "" {
+		if flag.NArg() > 0 || gCmdLineArgs.all || gCmdLineArgs.socket || gCmdLineArgs.bitrange != "" {
+			fmt.Fprintln(os.Stderr, "-catalog can't be combined with an msr, -a, -s, or -f")
+			showUsage()
+			os.Exit(1)
+		}
+		return
+	}
 	// positional arg
 	if flag.NArg() < 1 {
 		flag.Usage()
@@ -94,7 +85,7 @@ func init() {
 	}
 	// validate input flag arguments
 	if gCmdLineArgs.bitrange != "" {
-		_, _, err := parseBitrangeArg()
+		_, _, err := msr.ParseBitRange(gCmdLineArgs.bitrange)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			showUsage()
@@ -112,13 +103,30 @@ func mainReturnWithCode() int {
 		showVersion()
 		return 0
 	}
+	var catalog []CatalogEntry
+	if gCmdLineArgs.catalog != "" {
+		var err error
+		catalog, err = loadCatalog(gCmdLineArgs.catalog)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
 	msrReader, err := msr.NewMSR()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return 1
 	}
+	if gCmdLineArgs.catalog != "" {
+		err = printCatalog(os.Stdout, msrReader, catalog, gCmdLineArgs.processor)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
 	if gCmdLineArgs.bitrange != "" {
-		highBit, lowBit, _ := parseBitrangeArg()
+		highBit, lowBit, _ := msr.ParseBitRange(gCmdLineArgs.bitrange)
 		err = msrReader.SetBitRange(highBit, lowBit)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
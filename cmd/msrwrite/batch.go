@@ -0,0 +1,146 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/intel/svr-info/internal/msr"
+	"github.com/intel/svr-info/internal/util"
+)
+
+// BatchEntry is one line of a batch file, i.e., "<msr> <value> [cpulist]"
+type BatchEntry struct {
+	LineNo  int
+	CPUList string // empty when the line doesn't include a cpulist
+	MSR     uint64
+	Value   uint64
+	CPUs    []int
+}
+
+// loadBatch reads and parses the batch file. Blank lines and lines starting with '#' are
+// ignored. All lines are parsed, and their CPUs checked against validCPUs, before any are
+// applied so that a typo doesn't leave the system partially configured. CPUs aren't checked
+// when validCPUs is nil, i.e., a dry run without access to the MSR files.
+func loadBatch(path string, validCPUs []int) (entries []BatchEntry, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || len(fields) > 3 {
+			err = fmt.Errorf("%s line %d: expected <msr> <value> [cpulist], found \"%s\"", path, lineNo, line)
+			return
+		}
+		entry := BatchEntry{LineNo: lineNo}
+		if entry.MSR, err = parseHex(fields[0]); err != nil {
+			err = fmt.Errorf("%s line %d: could not parse msr address: %v", path, lineNo, err)
+			return
+		}
+		if entry.Value, err = parseHex(fields[1]); err != nil {
+			err = fmt.Errorf("%s line %d: could not parse msr value: %v", path, lineNo, err)
+			return
+		}
+		if len(fields) == 3 {
+			entry.CPUList = fields[2]
+			if entry.CPUs, err = util.ParseCPUList(entry.CPUList); err != nil {
+				err = fmt.Errorf("%s line %d: could not parse cpulist: %v", path, lineNo, err)
+				return
+			}
+			if validCPUs != nil {
+				for _, cpu := range entry.CPUs {
+					if !slices.Contains(validCPUs, cpu) {
+						err = fmt.Errorf("%s line %d: CPU %d not found", path, lineNo, cpu)
+						return
+					}
+				}
+			}
+		} else if validCPUs != nil && !gCmdLineArgs.all && !slices.Contains(validCPUs, gCmdLineArgs.processor) {
+			err = fmt.Errorf("%s line %d: CPU %d, selected by -p, not found", path, lineNo, gCmdLineArgs.processor)
+			return
+		}
+		entries = append(entries, entry)
+	}
+	if err = scanner.Err(); err != nil {
+		return
+	}
+	if len(entries) == 0 {
+		err = fmt.Errorf("batch file %s has no entries", path)
+	}
+	return
+}
+
+// parseHex parses a hexadecimal number with or without the 0x prefix
+func parseHex(s string) (val uint64, err error) {
+	return strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 64)
+}
+
+// runBatch applies the batch entries in order and prints the result of each line. Lines without
+// a cpulist are written to the processor(s) selected by -p or -a. When dryRun is set, nothing is
+// written and msrWriter may be nil.
+func runBatch(msrWriter *msr.MSR, entries []BatchEntry, dryRun bool, continueOnError bool) int {
+	failed := 0
+	applied := 0
+	for _, entry := range entries {
+		target := entry.CPUList
+		if target == "" {
+			if gCmdLineArgs.all {
+				target = "all"
+			} else {
+				target = strconv.Itoa(gCmdLineArgs.processor)
+			}
+		}
+		if dryRun {
+			fmt.Printf("line %d: would write %#x to msr %#x on CPU(s) %s\n", entry.LineNo, entry.Value, entry.MSR, target)
+			continue
+		}
+		if err := writeBatchEntry(msrWriter, entry); err != nil {
+			fmt.Printf("line %d: msr %#x = %#x on CPU(s) %s: FAIL (%v)\n", entry.LineNo, entry.MSR, entry.Value, target, err)
+			failed++
+			if !continueOnError {
+				break
+			}
+			continue
+		}
+		fmt.Printf("line %d: msr %#x = %#x on CPU(s) %s: OK\n", entry.LineNo, entry.MSR, entry.Value, target)
+		applied++
+	}
+	if dryRun {
+		fmt.Printf("%d line(s) would be written\n", len(entries))
+		return 0
+	}
+	fmt.Printf("%d of %d line(s) applied, %d failed, %d skipped\n", applied, len(entries), failed, len(entries)-applied-failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func writeBatchEntry(msrWriter *msr.MSR, entry BatchEntry) (err error) {
+	if entry.CPUs == nil {
+		if gCmdLineArgs.all {
+			return msrWriter.WriteAll(entry.MSR, entry.Value)
+		}
+		return msrWriter.WriteOne(entry.MSR, gCmdLineArgs.processor, entry.Value)
+	}
+	for _, cpu := range entry.CPUs {
+		if err = msrWriter.WriteOne(entry.MSR, cpu, entry.Value); err != nil {
+			return fmt.Errorf("CPU %d: %v", cpu, err)
+		}
+	}
+	return
+}
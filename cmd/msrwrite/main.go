@@ -15,12 +15,16 @@ import (
 )
 
 type CmdLineArgs struct {
-	help      bool
-	version   bool
-	all       bool
-	processor int
-	msr       uint64
-	val       uint64
+	help            bool
+	version         bool
+	all             bool
+	verify          bool
+	processor       int
+	batch           string
+	dryRun          bool
+	continueOnError bool
+	msr             uint64
+	val             uint64
 }
 
 // globals
@@ -33,6 +37,8 @@ func showUsage() {
 	appName := filepath.Base(os.Args[0])
 	fmt.Fprintf(os.Stderr, "Usage: %s <args> msr value\n", appName)
 	fmt.Fprintf(os.Stderr, "Example: %s -p 1 0x123 0xabc\n", appName)
+	fmt.Fprintf(os.Stderr, "Example: %s -a -verify 0x123 0xabc\n", appName)
+	fmt.Fprintf(os.Stderr, "Example: %s -batch tuning.txt -continue-on-error\n", appName)
 	flag.PrintDefaults()
 }
 
@@ -47,10 +53,28 @@ func init() {
 	flag.BoolVar(&gCmdLineArgs.version, "v", false, "Print program version.")
 	flag.BoolVar(&gCmdLineArgs.all, "a", false, "Write for all processors.")
 	flag.IntVar(&gCmdLineArgs.processor, "p", 0, "Select processor number. Default 0.")
+	flag.BoolVar(&gCmdLineArgs.verify, "verify", false, "Read the MSR back after writing and confirm the value on each processor. Prints a per-processor summary.")
+	flag.StringVar(&gCmdLineArgs.batch, "batch", "", "Apply the writes listed in this file, one \"<msr> <value> [cpulist]\" per line, in order. Lines without a cpulist use -p or -a.")
+	flag.BoolVar(&gCmdLineArgs.dryRun, "dry-run", false, "Print the writes in the -batch file without applying them.")
+	flag.BoolVar(&gCmdLineArgs.continueOnError, "continue-on-error", false, "Continue with the next line of the -batch file after a failed write. Default is to stop.")
 	flag.Parse()
 	if gCmdLineArgs.help || gCmdLineArgs.version {
 		return
 	}
+	// the batch file replaces the positional args
+	if gCmdLineArgs.batch != "" {
+		if flag.NArg() > 0 || gCmdLineArgs.verify {
+			fmt.Fprintln(os.Stderr, "-batch can't be combined with an msr and value, or -verify")
+			showUsage()
+			os.Exit(1)
+		}
+		return
+	}
+	if gCmdLineArgs.dryRun || gCmdLineArgs.continueOnError {
+		fmt.Fprintln(os.Stderr, "-dry-run and -continue-on-error require -batch")
+		showUsage()
+		os.Exit(1)
+	}
 	// positional arg
 	if flag.NArg() < 2 {
 		flag.Usage()
@@ -91,11 +115,35 @@ func mainReturnWithCode() int {
 		showVersion()
 		return 0
 	}
+	if gCmdLineArgs.batch != "" {
+		// a dry run doesn't require access to the MSR files, but uses them to check the CPUs
+		var validCPUs []int
+		msrWriter, err := msr.NewMSR()
+		if err == nil {
+			validCPUs, err = msrWriter.GetCPUs()
+		}
+		if err != nil {
+			if !gCmdLineArgs.dryRun {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			msrWriter, validCPUs = nil, nil
+		}
+		entries, err := loadBatch(gCmdLineArgs.batch, validCPUs)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return runBatch(msrWriter, entries, gCmdLineArgs.dryRun, gCmdLineArgs.continueOnError)
+	}
 	msrWriter, err := msr.NewMSR()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return 1
 	}
+	if gCmdLineArgs.verify {
+		return writeAndVerify(msrWriter)
+	}
 	if gCmdLineArgs.all {
 		err = msrWriter.WriteAll(gCmdLineArgs.msr, gCmdLineArgs.val)
 		if err != nil {
@@ -112,4 +160,42 @@ func mainReturnWithCode() int {
 	return 0
 }
 
+// writeAndVerify writes the value to each selected processor, then reads it back. Writes continue
+// after a failure so that the summary shows the state of every processor.
+func writeAndVerify(msrWriter *msr.MSR) int {
+	cpus := []int{gCmdLineArgs.processor}
+	if gCmdLineArgs.all {
+		var err error
+		if cpus, err = msrWriter.GetCPUs(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	failed := 0
+	for _, cpu := range cpus {
+		if err := msrWriter.WriteOne(gCmdLineArgs.msr, cpu, gCmdLineArgs.val); err != nil {
+			fmt.Printf("CPU %d: FAIL (write: %v)\n", cpu, err)
+			failed++
+			continue
+		}
+		val, err := msrWriter.ReadOne(gCmdLineArgs.msr, cpu)
+		if err != nil {
+			fmt.Printf("CPU %d: FAIL (read: %v)\n", cpu, err)
+			failed++
+			continue
+		}
+		if val != gCmdLineArgs.val {
+			fmt.Printf("CPU %d: FAIL (wrote %#x, read %#x)\n", cpu, gCmdLineArgs.val, val)
+			failed++
+			continue
+		}
+		fmt.Printf("CPU %d: PASS\n", cpu)
+	}
+	fmt.Printf("%d of %d processors verified\n", len(cpus)-failed, len(cpus))
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
 func main() { os.Exit(mainReturnWithCode()) }
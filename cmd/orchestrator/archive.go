@@ -0,0 +1,155 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+// splits the output archive into numbered volumes, see -max-archive-size
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// archiveMember is a file in the archive, end is the offset in the compressed archive where the
+// file's data ends
+type archiveMember struct {
+	name string
+	end  int64
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (n int, err error) {
+	n, err = c.w.Write(p)
+	c.n += int64(n)
+	return
+}
+
+// parseSize parses a size in bytes with an optional K, M, or G (1024 based) suffix, e.g., 500M
+func parseSize(size string) (bytes int64, err error) {
+	multiplier := int64(1)
+	number := strings.ToUpper(strings.TrimSpace(size))
+	for i, suffix := range []string{"K", "M", "G"} {
+		if strings.HasSuffix(number, suffix) {
+			number = strings.TrimSuffix(number, suffix)
+			multiplier = int64(1) << (10 * (i + 1))
+			break
+		}
+	}
+	bytes, err = strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		err = fmt.Errorf("invalid size: %s", size)
+		return
+	}
+	bytes *= multiplier
+	return
+}
+
+// getVolumeEnds returns the offset where each volume ends. Volumes end at the last file boundary
+// that fits within maxSize, or at maxSize when a file doesn't fit in a volume by itself.
+func getVolumeEnds(archiveSize int64, members []archiveMember, maxSize int64) (ends []int64) {
+	start := int64(0)
+	for start < archiveSize {
+		end := start + maxSize
+		if end >= archiveSize {
+			end = archiveSize
+		} else {
+			boundary := int64(0)
+			for _, member := range members {
+				if member.end > start && member.end <= end {
+					boundary = member.end
+				}
+			}
+			if boundary > 0 {
+				end = boundary
+			}
+		}
+		ends = append(ends, end)
+		start = end
+	}
+	return
+}
+
+// splitArchive replaces the archive with numbered volumes, e.g., name.tgz.001, name.tgz.002,
+// when it is larger than maxSize bytes. A manifest, name.tgz.manifest, describes how to
+// reassemble the archive and lists the files that end in each volume. Returns true when the
+// archive was split.
+func splitArchive(tarFilePath string, members []archiveMember, maxSize int64) (split bool, err error) {
+	info, err := os.Stat(tarFilePath)
+	if err != nil {
+		return
+	}
+	if info.Size() <= maxSize {
+		return
+	}
+	ends := getVolumeEnds(info.Size(), members, maxSize)
+	in, err := os.Open(tarFilePath)
+	if err != nil {
+		return
+	}
+	archiveHash := sha256.New()
+	source := io.TeeReader(in, archiveHash)
+	archiveName := filepath.Base(tarFilePath)
+	var volumeNames []string
+	var volumeLines []string
+	start := int64(0)
+	for i, end := range ends {
+		volumeName := fmt.Sprintf("%s.%03d", archiveName, i+1)
+		var volumeHash []byte
+		if volumeHash, err = writeVolume(filepath.Join(filepath.Dir(tarFilePath), volumeName), source, end-start); err != nil {
+			in.Close()
+			return
+		}
+		volumeNames = append(volumeNames, volumeName)
+		volumeLines = append(volumeLines, fmt.Sprintf("%x  %s  %d bytes", volumeHash, volumeName, end-start))
+		for _, member := range members {
+			if member.end > start && member.end <= end {
+				volumeLines = append(volumeLines, "    "+member.name)
+			}
+		}
+		start = end
+	}
+	in.Close()
+	manifest := []string{
+		fmt.Sprintf("%s is split into %d volumes of at most %d bytes each. Reassemble and extract it with:", archiveName, len(ends), maxSize),
+		fmt.Sprintf("    cat %s > %s", strings.Join(volumeNames, " "), archiveName),
+		fmt.Sprintf("    tar -xzf %s", archiveName),
+		"",
+		fmt.Sprintf("%x  %s", archiveHash.Sum(nil), archiveName),
+		"",
+		"Volumes, sha256 and size, and the files that end in each volume:",
+	}
+	manifest = append(manifest, volumeLines...)
+	err = os.WriteFile(tarFilePath+".manifest", []byte(strings.Join(manifest, "\n")+"\n"), 0644)
+	if err != nil {
+		return
+	}
+	err = os.Remove(tarFilePath)
+	split = err == nil
+	return
+}
+
+// writeVolume copies size bytes from the source to a new volume file, returns the volume's sha256
+func writeVolume(volumePath string, source io.Reader, size int64) (hash []byte, err error) {
+	out, err := os.Create(volumePath)
+	if err != nil {
+		return
+	}
+	defer out.Close()
+	volumeHash := sha256.New()
+	if _, err = io.CopyN(io.MultiWriter(out, volumeHash), source, size); err != nil {
+		return
+	}
+	hash = volumeHash.Sum(nil)
+	return
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseSize(t *testing.T) {
+	for _, tc := range []struct {
+		in       string
+		expected int64
+		err      bool
+	}{
+		{"1048576", 1048576, false},
+		{"500M", 500 * 1024 * 1024, false},
+		{"2g", 2 * 1024 * 1024 * 1024, false},
+		{"64K", 64 * 1024, false},
+		{"2T", 0, true},
+		{"M", 0, true},
+	} {
+		bytes, err := parseSize(tc.in)
+		if tc.err != (err != nil) || bytes != tc.expected {
+			t.Errorf("%s: expected %d (error: %t), got %d (%v)", tc.in, tc.expected, tc.err, bytes, err)
+		}
+	}
+}
+
+func TestGetVolumeEnds(t *testing.T) {
+	members := []archiveMember{{"a", 40}, {"b", 90}, {"c", 300}, {"d", 320}}
+	// a and b fit in the first volume, c is split, the last volume has the end of c, d, and the trailers
+	ends := getVolumeEnds(330, members, 100)
+	if !slices.Equal(ends, []int64{90, 190, 290, 330}) {
+		t.Fatalf("unexpected volume ends: %v", ends)
+	}
+	if ends = getVolumeEnds(330, members, 400); !slices.Equal(ends, []int64{330}) {
+		t.Fatalf("unexpected volume ends: %v", ends)
+	}
+}
+
+func TestSplitArchive(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "out.tgz")
+	data := make([]byte, 1000)
+	rand.Read(data)
+	if err := os.WriteFile(archivePath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if split, err := splitArchive(archivePath, []archiveMember{{"out/a", 300}, {"out/b", 700}}, 400); err != nil || !split {
+		t.Fatal("expected the archive to be split", err)
+	}
+	if _, err := os.Stat(archivePath); err == nil {
+		t.Fatal("expected the archive to be replaced by volumes")
+	}
+	var joined []byte
+	for _, volume := range []string{"out.tgz.001", "out.tgz.002", "out.tgz.003"} {
+		volumeData, err := os.ReadFile(filepath.Join(dir, volume))
+		if err != nil {
+			t.Fatal(err)
+		}
+		joined = append(joined, volumeData...)
+	}
+	if !bytes.Equal(joined, data) {
+		t.Fatal("reassembled volumes don't match the archive")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "out.tgz.manifest")); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -12,10 +12,13 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/intel/svr-info/internal/commandfile"
+	"github.com/intel/svr-info/internal/progress"
 	"github.com/intel/svr-info/internal/target"
 	"github.com/intel/svr-info/internal/util"
 	"gopkg.in/yaml.v2"
@@ -30,6 +33,7 @@ type Collection struct {
 	stdout         string
 	stderr         string
 	ok             bool
+	err            error
 }
 
 func newCollection(target target.Target, cmdLineArgs *CmdLineArgs, outputDir string, tempDir string) *Collection {
@@ -61,6 +65,10 @@ func stringInList(s string, l []string) bool {
 	return false
 }
 
+// profileTimeoutMargin is the number of seconds, beyond the profile duration, allowed for the
+// profile command to finish, e.g., to write its output
+const profileTimeoutMargin = 300
+
 func customizeCommandYAML(cmdTemplate []byte, cmdLineArgs *CmdLineArgs, targetBinDir string, targetHostName string) (customized []byte, err error) {
 	var cf commandfile.CommandFile
 	err = yaml.Unmarshal(cmdTemplate, &cf)
@@ -70,13 +78,21 @@ func customizeCommandYAML(cmdTemplate []byte, cmdLineArgs *CmdLineArgs, targetBi
 	cf.Args.Name = targetHostName
 	cf.Args.Binpath = targetBinDir
 	cf.Args.Timeout = cmdLineArgs.cmdTimeout
+	cf.Args.ProfileDuration = cmdLineArgs.profileDuration
+	cf.Args.ProfileSamples = max(1, cmdLineArgs.profileDuration/cmdLineArgs.profileInterval)
+	// long profiles, e.g., soak tests, must not be stopped by the command timeout
+	if cmdLineArgs.profile != "" && cmdLineArgs.profileDuration+profileTimeoutMargin > cf.Args.Timeout {
+		cf.Args.Timeout = cmdLineArgs.profileDuration + profileTimeoutMargin
+	}
 	for idx := range cf.Commands {
 		cmd := &cf.Commands[idx]
 		// set path to the lspci data file
 		if cmd.Label == "lspci -vmm" {
 			cmd.Command = fmt.Sprintf("lspci -i %s -vmm", filepath.Join(targetBinDir, "pci.ids.gz"))
+		} else if cmd.Label == "lspci -vvv" {
+			cmd.Command = fmt.Sprintf("lspci -i %s -vvv", filepath.Join(targetBinDir, "pci.ids.gz"))
 		}
-		optionalCommands := []string{"Memory MLC Bandwidth", "Memory MLC Loaded Latency Test", "stress-ng cpu methods", "avx-turbo", "CPU Turbo Test", "CPU Idle", "fio", "profile", "analyze"}
+		optionalCommands := []string{"Memory MLC Bandwidth", "Memory MLC Loaded Latency Test", "stress-ng cpu methods", "avx-turbo", "CPU Turbo Test", "CPU Idle", "fio", "profile", "analyze", "perf c2c"}
 		if !stringInList(cmd.Label, optionalCommands) {
 			if !cmdLineArgs.noConfig {
 				cmd.Run = true
@@ -116,8 +132,6 @@ func customizeCommandYAML(cmdTemplate []byte, cmdLineArgs *CmdLineArgs, targetBi
 					tmpl := template.Must(template.New("profileCommand").Parse(cmd.Command))
 					buf := new(bytes.Buffer)
 					err = tmpl.Execute(buf, struct {
-						Duration       int
-						Interval       int
 						ProfileCPU     bool
 						ProfileStorage bool
 						ProfileMemory  bool
@@ -125,8 +139,6 @@ func customizeCommandYAML(cmdTemplate []byte, cmdLineArgs *CmdLineArgs, targetBi
 						ProfilePMU     bool
 						ProfilePower   bool
 					}{
-						Duration:       cmdLineArgs.profileDuration,
-						Interval:       cmdLineArgs.profileInterval,
 						ProfileCPU:     strings.Contains(cmdLineArgs.profile, "cpu") || strings.Contains(cmdLineArgs.profile, "all"),
 						ProfileStorage: strings.Contains(cmdLineArgs.profile, "storage") || strings.Contains(cmdLineArgs.profile, "all"),
 						ProfileMemory:  strings.Contains(cmdLineArgs.profile, "memory") || strings.Contains(cmdLineArgs.profile, "all"),
@@ -139,6 +151,22 @@ func customizeCommandYAML(cmdTemplate []byte, cmdLineArgs *CmdLineArgs, targetBi
 					}
 					cmd.Command = buf.String()
 				}
+			} else if cmd.Label == "perf c2c" {
+				cmd.Run = cmdLineArgs.c2c
+				if cmd.Run {
+					// the recorded data goes in the collector's temporary directory
+					tmpl := template.Must(template.New("c2cCommand").Parse(cmd.Command))
+					buf := new(bytes.Buffer)
+					err = tmpl.Execute(buf, struct {
+						DataDir string
+					}{
+						DataDir: targetBinDir,
+					})
+					if err != nil {
+						return
+					}
+					cmd.Command = buf.String()
+				}
 			} else if cmd.Label == "analyze" {
 				cmd.Run = cmdLineArgs.analyze != ""
 				if cmd.Run {
@@ -311,8 +339,83 @@ func (c *Collection) runCollector(collectorFilePath string, yamlFilePath string,
 	return
 }
 
-func (c *Collection) Collect() (err error) {
+// progressInterval is how often the collector's progress file is read while it runs
+const progressInterval = 5 * time.Second
+
+// progressReadTimeout, in seconds, is less than progressInterval so that a hung read doesn't
+// delay the next read or stopping the monitor
+const progressReadTimeout = 3
+
+// parseCollectorProgress returns the percentage of commands completed from the collector's
+// progress file content, e.g., 12/97
+func parseCollectorProgress(content string) (percent int, err error) {
+	completedField, totalField, found := strings.Cut(strings.TrimSpace(content), "/")
+	if !found {
+		err = fmt.Errorf("unexpected progress format: %s", content)
+		return
+	}
+	completed, err := strconv.Atoi(completedField)
+	if err != nil {
+		return
+	}
+	total, err := strconv.Atoi(totalField)
+	if err != nil {
+		return
+	}
+	if total <= 0 || completed < 0 || completed > total {
+		err = fmt.Errorf("unexpected progress values: %s", content)
+		return
+	}
+	percent = completed * 100 / total
+	return
+}
+
+// monitorProgress reads the collector's progress file, in the working directory, while the
+// collector runs and appends the percentage of commands completed to the status. Call the
+// returned function, when the collector is done, to stop monitoring.
+func (c *Collection) monitorProgress(workingDirectory string, status string, statusUpdate progress.MultiSpinnerUpdateFunc) (stop func()) {
+	if statusUpdate == nil {
+		return func() {}
+	}
+	done := make(chan bool)
+	stopped := make(chan bool)
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		lastPercent := -1
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				cmd := exec.Command("cat", filepath.Join(workingDirectory, "collector.progress"))
+				stdout, _, _, err := c.target.RunCommandWithTimeout(cmd, progressReadTimeout)
+				if err != nil {
+					continue // not written yet, already removed, or timed out
+				}
+				if percent, err := parseCollectorProgress(stdout); err == nil && percent != lastPercent {
+					statusUpdate(c.target.GetName(), fmt.Sprintf("%s (%d%%)", status, percent))
+					lastPercent = percent
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func (c *Collection) Collect(statusUpdate progress.MultiSpinnerUpdateFunc) (err error) {
 	log.Printf("collection starting for target: %s", c.target.GetName())
+	if remoteTarget, ok := c.target.(*target.RemoteTarget); ok {
+		if err = remoteTarget.CheckJumpHost(); err != nil {
+			err = fmt.Errorf("failed to connect to target: %s, %v", c.target.GetName(), err)
+			log.Print(err)
+			return
+		}
+	}
 	if !c.target.CanConnect() {
 		err = fmt.Errorf("failed to connect to target: %s", c.target.GetName())
 		log.Print(err)
@@ -405,11 +508,13 @@ func (c *Collection) Collect() (err error) {
 	} else {
 		log.Printf("Optional directory of extra collection files (%s) not found.", extrasDir)
 	}
+	stopProgress := c.monitorProgress(tempDir, "collecting data", statusUpdate)
 	c.stdout, c.stderr, err = c.runCollector(
 		filepath.Join(tempDir, "collector"),
 		filepath.Join(tempDir, filepath.Base(commandFilePath)),
 		tempDir,
 	)
+	stopProgress()
 	if err != nil {
 		log.Printf("failed to run collector on %s, stderr: [%s]. "+
 			"Override the temporary directory used by svr-info with the "+
@@ -448,11 +553,13 @@ func (c *Collection) Collect() (err error) {
 			return
 		}
 		// run collector in the megadata directory so output from commands will land in that directory
+		stopProgress := c.monitorProgress(megaPath, "collecting megadata", statusUpdate)
 		_, _, err = c.runCollector(
 			filepath.Join(tempDir, "collector"),
 			filepath.Join(tempDir, filepath.Base(commandFilePath)),
 			megaPath,
 		)
+		stopProgress()
 		if err != nil {
 			log.Printf("failed to run megadata collector on %s, stderr: [%s]",
 				c.target.GetName(), c.stderr)
@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCollectorProgress(t *testing.T) {
+	tests := []struct {
+		content string
+		percent int
+		valid   bool
+	}{
+		{"0/97\n", 0, true},
+		{"12/97\n", 12, true},
+		{"97/97", 100, true},
+		{"", 0, false},
+		{"12", 0, false},
+		{"12/0", 0, false},
+		{"98/97", 0, false},
+		{"a/97", 0, false},
+	}
+	for _, test := range tests {
+		percent, err := parseCollectorProgress(test.content)
+		if test.valid && (err != nil || percent != test.percent) {
+			t.Errorf("%q: expected %d, got %d, %v", test.content, test.percent, percent, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%q: expected error", test.content)
+		}
+	}
+}
+
+func TestCustomizeCommandYAMLC2C(t *testing.T) {
+	cmdTemplate := []byte(`arguments:
+  name:
+commands:
+- label: perf c2c
+  command: |-
+    perf c2c record -a -o {{.DataDir}}/perf_c2c.data -- sleep 10
+    perf c2c report -i {{.DataDir}}/perf_c2c.data --stdio
+`)
+	customized, err := customizeCommandYAML(cmdTemplate, &CmdLineArgs{c2c: true, cmdTimeout: 300, profileInterval: 2}, "/tmp/svr-info.1234", "host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(customized), "-o /tmp/svr-info.1234/perf_c2c.data") || !strings.Contains(string(customized), "-i /tmp/svr-info.1234/perf_c2c.data") {
+		t.Errorf("expected c2c data in the temporary directory: %s", string(customized))
+	}
+}
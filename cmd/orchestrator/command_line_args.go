@@ -34,15 +34,30 @@ type CmdLineArgs struct {
 	user             string
 	key              string
 	targets          string
+	k8sSelector      string
+	k8sKubeconfig    string
+	k8sContext       string
+	jump             string
+	credentials      string
+	credentialsKey   string
+	sshMultiplex     bool
 	megadata         bool
+	c2c              bool
 	output           string
+	append           bool
 	targetTemp       string
 	temp             string
 	printConfig      bool
 	noConfig         bool
 	cmdTimeout       int
+	reportTimeout    int
 	reporter         string
 	collector        string
+	summaryJSON      string
+	maxArchiveSize   string
+	maxArchiveBytes  int64 // parsed from maxArchiveSize by validate
+	postHook         string
+	quiet            bool
 	debug            bool
 }
 
@@ -56,9 +71,12 @@ func showUsage() {
 	fmt.Fprintf(os.Stderr, "                [-benchmark SELECT] [-storage_dir DIR]\n")
 	fmt.Fprintf(os.Stderr, "                [-profile SELECT] [-profile_duration SECONDS] [-profile_interval N]\n")
 	fmt.Fprintf(os.Stderr, "                [-analyze SELECT] [-analyze_duration SECONDS] [-analyze_frequency N]\n")
-	fmt.Fprintf(os.Stderr, "                [-megadata]\n")
+	fmt.Fprintf(os.Stderr, "                [-megadata] [-c2c]\n")
 	fmt.Fprintf(os.Stderr, "                [-ip IP] [-port PORT] [-user USER] [-key KEY] [-targets TARGETS]\n")
-	fmt.Fprintf(os.Stderr, "                [-output OUTPUT] [-temp TEMP] [-targettemp TEMP] [-printconfig] [-noconfig] [-cmd_timeout]\n")
+	fmt.Fprintf(os.Stderr, "                [-k8s-selector SELECTOR] [-k8s-kubeconfig FILE] [-k8s-context CONTEXT]\n")
+	fmt.Fprintf(os.Stderr, "                [-jump JUMP] [-ssh-multiplex] [-credentials FILE] [-credentials-key KEY]\n")
+	fmt.Fprintf(os.Stderr, "                [-output OUTPUT] [-append] [-temp TEMP] [-targettemp TEMP] [-printconfig] [-noconfig] [-cmd_timeout]\n")
+	fmt.Fprintf(os.Stderr, "                [-report-timeout SECONDS] [-summary-json PATH] [-max-archive-size SIZE] [-post-hook SCRIPT] [-quiet]\n")
 	fmt.Fprintf(os.Stderr, "                [-reporter \"args\"] [-collector \"args\"] [-debug]\n")
 
 	longHelp := `
@@ -91,6 +109,8 @@ analyze arguments:
 
 additional data collection arguments:
   -megadata             collect additional data in megadata directory (default: False)
+  -c2c                  record cache line contention (false sharing) with perf c2c for 10 seconds and
+                        report the most contended cache lines. Requires root or sudo. (default: False)
 
 remote target arguments:
   -ip IP                ip address or hostname (default: Nil)
@@ -99,17 +119,65 @@ remote target arguments:
   -key KEY              local path to ssh private key file (default: Nil)
   -targets TARGETS      path to targets file, one line per target.
                         Line format: 
-                           '<label:>ip_address:ssh_port:user_name:private_key_path:ssh_password:sudo_password'
+                           '<label:>ip_address:ssh_port:user_name:private_key_path:ssh_password:sudo_password<:duration><:jump><:cred_ref>'
                               - Provide private_key_path or ssh_password.
+                              - Optional duration (seconds) overrides -profile_duration and -analyze_duration
+                                for the target. Label field is required, but may be empty, when duration is provided.
+                              - Optional jump overrides -jump for the target. Label and duration fields are
+                                required, but may be empty, when jump is provided.
+                              - Optional cred_ref names the entry in the -credentials file that provides the
+                                ssh_password and sudo_password, which must then be empty. Label, duration, and
+                                jump fields are required, but may be empty, when cred_ref is provided.
+                        Use '-targets -' to read the targets from stdin.
                         If provided, overrides single target arguments. (default: Nil)
+  -k8s-selector SELECTOR
+                        Kubernetes node label selector, e.g., node-role.kubernetes.io/worker=.
+                        Collects from the internal IP address of each matching node using
+                        -user, -key, and -port. Requires kubectl and access to the cluster
+                        through kubeconfig or an in-cluster service account. (default: Nil)
+  -k8s-kubeconfig FILE  kubeconfig file of the -k8s-selector cluster, instead of KUBECONFIG or
+                        ~/.kube/config. (default: Nil)
+  -k8s-context CONTEXT  kubeconfig context of the -k8s-selector cluster, instead of the current
+                        context. (default: Nil)
+  -jump JUMP            connect to the remote target(s) through an ssh jump host (bastion), e.g.,
+                        -jump user@bastion or -jump user@bastion:2222. The jump host is authenticated
+                        by the local ssh configuration, e.g., ssh-agent, not by -key or ssh_password. (default: Nil)
+  -credentials FILE     path to an age or GPG encrypted YAML file of target passwords, referenced by the
+                        cred_ref field in the targets file, e.g., 'db1: {ssh_password: ..., sudo_password: ...}'.
+                        Encrypt the file with a passphrase ('age -p' or 'gpg --symmetric') or to an age
+                        recipient. The decrypted passwords are never written to the log. (default: Nil)
+  -credentials-key KEY  passphrase or age identity (AGE-SECRET-KEY-...) that decrypts the -credentials file.
+                        Prefer setting the SVR_INFO_CREDENTIALS_KEY environment variable, command line
+                        arguments are visible to other users of this machine. (default: Nil)
+  -ssh-multiplex        reuse one ssh connection (ControlMaster) for all of the commands and file transfers
+                        to each remote target. Use -ssh-multiplex=false when the local ssh client doesn't
+                        support connection sharing, e.g., on Windows. (default: True)
 
 advanced arguments:
   -output DIR           path to output directory. Directory must exist. (default: $PWD/orchestrator_timestamp)
+  -append               add to the reports in the -output directory, e.g., for incremental scans of a fleet.
+                        The raw data (*.raw.json) is kept in the directory and the raw data from previous
+                        -append runs is included in the reports, e.g., all_hosts.html. A target's new data
+                        replaces its previous data. (default: False)
   -temp DIR             path to temporary directory on localhost. Directory must exist. (default: system default)
   -targettemp DIR       path to temporary directory on target. Directory must exist. (default: system default)
   -printconfig          print the collector configuration file and exit (default: False)
   -noconfig             do not collect system configuration data. (default: False)
   -cmd_timeout          the maximum number of seconds to wait for each data collection command (default: 1500)
+  -report-timeout N     the maximum number of seconds to wait for the reports to be generated (default: 600)
+  -summary-json PATH    write a JSON summary of the per-target results to PATH. Directory must exist. (default: Nil)
+  -max-archive-size SIZE
+                        split the output archive (*.tgz) into numbered volumes (*.tgz.001, *.tgz.002, ...)
+                        of at most SIZE bytes, e.g., 500M or 2G, when it is larger. Volumes end between
+                        files where possible. The *.tgz.manifest file describes how to reassemble the
+                        archive. (default: Nil)
+  -post-hook SCRIPT     run SCRIPT after the output is archived, e.g., to upload the archive. The script's
+                        arguments are the path to the archive (*.tgz, or *.tgz.manifest when split) and the
+                        output directory, also in the SVR_INFO_ARCHIVE and SVR_INFO_OUTPUT_DIR environment
+                        variables. The script must be executable. A failing script is reported as a
+                        warning. (default: Nil)
+  -quiet                don't show progress spinners, write a status line to stderr as each target's status
+                        changes instead, e.g., for CI logs (default: False)
   -reporter             run the the reporter sub-component with args
                         e.g., -reporter "-input /home/rex -output /home/rex -format html" (default: Nil)
   -collector            run the the collector sub-component with args
@@ -123,6 +191,8 @@ $ ./%[1]s -benchmark all
     Collect configuration and benchmark data on local machine.
 $ ./%[1]s -profile all -targets ./targets
     Collect configuration and profile data on remote machines defined in targets file.
+$ ./generate_targets | ./%[1]s -targets -
+    Collect configuration data on remote machines defined in targets read from stdin.
 $ ./%[1]s -format all
     Collect configuration data on local machine. Generate all report formats.
 $ ./%[1]s -ip 198.51.100.255 -port 22 -user user83767 -key ~/.ssh/id_rsa
@@ -146,11 +216,13 @@ func (cmdLineArgs *CmdLineArgs) parse(name string, arguments []string) (err erro
 	flagSet.BoolVar(&cmdLineArgs.help, "h", false, "")
 	flagSet.BoolVar(&cmdLineArgs.version, "v", false, "")
 	flagSet.StringVar(&cmdLineArgs.output, "output", "", "")
+	flagSet.BoolVar(&cmdLineArgs.append, "append", false, "")
 	flagSet.StringVar(&cmdLineArgs.temp, "temp", "", "")
 	flagSet.StringVar(&cmdLineArgs.targetTemp, "targettemp", "", "")
 	flagSet.BoolVar(&cmdLineArgs.printConfig, "printconfig", false, "")
 	flagSet.BoolVar(&cmdLineArgs.noConfig, "noconfig", false, "")
 	flagSet.IntVar(&cmdLineArgs.cmdTimeout, "cmd_timeout", 1500, "")
+	flagSet.IntVar(&cmdLineArgs.reportTimeout, "report-timeout", 600, "")
 	flagSet.StringVar(&cmdLineArgs.format, "format", "html,xlsx,json", "")
 	flagSet.StringVar(&cmdLineArgs.benchmark, "benchmark", "", "")
 	flagSet.StringVar(&cmdLineArgs.profile, "profile", "", "")
@@ -162,14 +234,26 @@ func (cmdLineArgs *CmdLineArgs) parse(name string, arguments []string) (err erro
 	flagSet.StringVar(&cmdLineArgs.user, "user", "", "")
 	flagSet.StringVar(&cmdLineArgs.key, "key", "", "")
 	flagSet.StringVar(&cmdLineArgs.targets, "targets", "", "")
+	flagSet.StringVar(&cmdLineArgs.k8sSelector, "k8s-selector", "", "")
+	flagSet.StringVar(&cmdLineArgs.k8sKubeconfig, "k8s-kubeconfig", "", "")
+	flagSet.StringVar(&cmdLineArgs.k8sContext, "k8s-context", "", "")
+	flagSet.StringVar(&cmdLineArgs.jump, "jump", "", "")
+	flagSet.BoolVar(&cmdLineArgs.sshMultiplex, "ssh-multiplex", true, "")
+	flagSet.StringVar(&cmdLineArgs.credentials, "credentials", "", "")
+	flagSet.StringVar(&cmdLineArgs.credentialsKey, "credentials-key", "", "")
 	flagSet.BoolVar(&cmdLineArgs.debug, "debug", false, "")
 	flagSet.BoolVar(&cmdLineArgs.megadata, "megadata", false, "")
+	flagSet.BoolVar(&cmdLineArgs.c2c, "c2c", false, "")
 	flagSet.IntVar(&cmdLineArgs.profileDuration, "profile_duration", 60, "")
 	flagSet.IntVar(&cmdLineArgs.analyzeDuration, "analyze_duration", 60, "")
 	flagSet.IntVar(&cmdLineArgs.profileInterval, "profile_interval", 2, "")
 	flagSet.IntVar(&cmdLineArgs.analyzeFrequency, "analyze_frequency", 11, "")
 	flagSet.StringVar(&cmdLineArgs.reporter, "reporter", "", "")
 	flagSet.StringVar(&cmdLineArgs.collector, "collector", "", "")
+	flagSet.StringVar(&cmdLineArgs.summaryJSON, "summary-json", "", "")
+	flagSet.StringVar(&cmdLineArgs.maxArchiveSize, "max-archive-size", "", "")
+	flagSet.StringVar(&cmdLineArgs.postHook, "post-hook", "", "")
+	flagSet.BoolVar(&cmdLineArgs.quiet, "quiet", false, "")
 	err = flagSet.Parse(arguments)
 	if err != nil {
 		return
@@ -229,6 +313,11 @@ func (cmdLineArgs *CmdLineArgs) validate() (err error) {
 			return
 		}
 	}
+	// -append
+	if cmdLineArgs.append && cmdLineArgs.output == "" {
+		err = fmt.Errorf("-append : output directory required when append provided")
+		return
+	}
 	// -format
 	if cmdLineArgs.format != "" {
 		if !isValidType(core.ReportTypes, cmdLineArgs.format) {
@@ -258,7 +347,7 @@ func (cmdLineArgs *CmdLineArgs) validate() (err error) {
 			return
 		}
 		numSamples := cmdLineArgs.profileDuration / cmdLineArgs.profileInterval
-		maxSamples := (5 * 60) / 2 // 5 minutes at default interval
+		maxSamples := (60 * 60) / 2 // 1 hour at default interval, e.g., for soak tests
 		if numSamples > maxSamples {
 			err = fmt.Errorf("-profile_duration %d -profile_interval %d may result in too much data. Please reduce total samples (duration/interval) to %d or less", cmdLineArgs.profileDuration, cmdLineArgs.profileInterval, maxSamples)
 			return
@@ -298,7 +387,7 @@ func (cmdLineArgs *CmdLineArgs) validate() (err error) {
 		err = fmt.Errorf("-user <blank> : user required when -ip %s provided", cmdLineArgs.ipAddress)
 		return
 	}
-	if cmdLineArgs.ipAddress == "" && cmdLineArgs.user != "" {
+	if cmdLineArgs.ipAddress == "" && cmdLineArgs.k8sSelector == "" && cmdLineArgs.user != "" {
 		// if user is provided, ip is required
 		err = fmt.Errorf("-ip <blank> : ip required when -user %s provided", cmdLineArgs.user)
 		return
@@ -308,7 +397,7 @@ func (cmdLineArgs *CmdLineArgs) validate() (err error) {
 		err = fmt.Errorf("-port %d : port must be a positive integer", cmdLineArgs.port)
 		return
 	}
-	if cmdLineArgs.port != 22 && (cmdLineArgs.ipAddress == "" || cmdLineArgs.user == "") {
+	if cmdLineArgs.port != 22 && ((cmdLineArgs.ipAddress == "" && cmdLineArgs.k8sSelector == "") || cmdLineArgs.user == "") {
 		err = fmt.Errorf("-port %d : user and ip required when port provided", cmdLineArgs.port)
 		return
 	}
@@ -329,13 +418,13 @@ func (cmdLineArgs *CmdLineArgs) validate() (err error) {
 			err = fmt.Errorf("-key %s : file does not exist", path)
 			return
 		}
-		if cmdLineArgs.ipAddress == "" || cmdLineArgs.user == "" {
+		if (cmdLineArgs.ipAddress == "" && cmdLineArgs.k8sSelector == "") || cmdLineArgs.user == "" {
 			err = fmt.Errorf("-key %s : user and ip required when key provided", cmdLineArgs.key)
 			return
 		}
 	}
 	// -targets
-	if cmdLineArgs.targets != "" {
+	if cmdLineArgs.targets != "" && cmdLineArgs.targets != stdinTargetsPath {
 		var path string
 		path, err = util.AbsPath(cmdLineArgs.targets)
 		if err != nil {
@@ -352,6 +441,109 @@ func (cmdLineArgs *CmdLineArgs) validate() (err error) {
 			return
 		}
 	}
+	// -credentials
+	if cmdLineArgs.credentials != "" {
+		if cmdLineArgs.targets == "" {
+			err = fmt.Errorf("-credentials %s : targets required when credentials provided", cmdLineArgs.credentials)
+			return
+		}
+		cmdLineArgs.credentials, err = util.AbsPath(cmdLineArgs.credentials)
+		if err != nil {
+			return
+		}
+		var exists bool
+		exists, err = util.FileExists(cmdLineArgs.credentials)
+		if err != nil {
+			err = fmt.Errorf("-credentials %s : %s", cmdLineArgs.credentials, err.Error())
+			return
+		}
+		if !exists {
+			err = fmt.Errorf("-credentials %s : file does not exist", cmdLineArgs.credentials)
+			return
+		}
+	}
+	// -credentials-key
+	if cmdLineArgs.credentialsKey != "" && cmdLineArgs.credentials == "" {
+		err = fmt.Errorf("-credentials-key : credentials required when credentials-key provided")
+		return
+	}
+	// -k8s-selector
+	if cmdLineArgs.k8sSelector != "" {
+		if cmdLineArgs.ipAddress != "" || cmdLineArgs.targets != "" {
+			err = fmt.Errorf("-k8s-selector %s : -ip and -targets cannot be used with -k8s-selector", cmdLineArgs.k8sSelector)
+			return
+		}
+		if cmdLineArgs.user == "" {
+			err = fmt.Errorf("-k8s-selector %s : user required when k8s-selector provided", cmdLineArgs.k8sSelector)
+			return
+		}
+	}
+	// -k8s-kubeconfig
+	if cmdLineArgs.k8sKubeconfig != "" {
+		if cmdLineArgs.k8sSelector == "" {
+			err = fmt.Errorf("-k8s-kubeconfig %s : k8s-selector required when k8s-kubeconfig provided", cmdLineArgs.k8sKubeconfig)
+			return
+		}
+		if _, err = os.Stat(cmdLineArgs.k8sKubeconfig); err != nil {
+			err = fmt.Errorf("-k8s-kubeconfig %s : %v", cmdLineArgs.k8sKubeconfig, err)
+			return
+		}
+	}
+	// -k8s-context
+	if cmdLineArgs.k8sContext != "" && cmdLineArgs.k8sSelector == "" {
+		err = fmt.Errorf("-k8s-context %s : k8s-selector required when k8s-context provided", cmdLineArgs.k8sContext)
+		return
+	}
+	// -jump
+	if cmdLineArgs.jump != "" {
+		if cmdLineArgs.ipAddress == "" && cmdLineArgs.targets == "" && cmdLineArgs.k8sSelector == "" {
+			err = fmt.Errorf("-jump %s : ip, targets, or k8s-selector required when jump provided", cmdLineArgs.jump)
+			return
+		}
+		if strings.ContainsAny(cmdLineArgs.jump, " \t") {
+			err = fmt.Errorf("-jump %s : must not contain whitespace", cmdLineArgs.jump)
+			return
+		}
+	}
+	// -report-timeout
+	if cmdLineArgs.reportTimeout <= 0 {
+		err = fmt.Errorf("-report-timeout %d : must be a positive integer", cmdLineArgs.reportTimeout)
+		return
+	}
+	// -summary-json
+	if cmdLineArgs.summaryJSON != "" {
+		// the file will be created, but its directory must exist
+		err = argDirExists(filepath.Dir(cmdLineArgs.summaryJSON), "summary-json")
+		if err != nil {
+			return
+		}
+	}
+	// -max-archive-size
+	if cmdLineArgs.maxArchiveSize != "" {
+		cmdLineArgs.maxArchiveBytes, err = parseSize(cmdLineArgs.maxArchiveSize)
+		if err != nil || cmdLineArgs.maxArchiveBytes < 1024*1024 {
+			err = fmt.Errorf("-max-archive-size %s : must be at least 1M", cmdLineArgs.maxArchiveSize)
+			return
+		}
+	}
+	// -post-hook
+	if cmdLineArgs.postHook != "" {
+		// the script is run by path, e.g., upload.sh is not looked up in PATH
+		cmdLineArgs.postHook, err = util.AbsPath(cmdLineArgs.postHook)
+		if err != nil {
+			return
+		}
+		var fileInfo fs.FileInfo
+		fileInfo, err = os.Stat(cmdLineArgs.postHook)
+		if err != nil || !fileInfo.Mode().IsRegular() {
+			err = fmt.Errorf("-post-hook %s : file does not exist", cmdLineArgs.postHook)
+			return
+		}
+		if fileInfo.Mode().Perm()&0111 == 0 {
+			err = fmt.Errorf("-post-hook %s : file is not executable", cmdLineArgs.postHook)
+			return
+		}
+	}
 	// -collector and -reporter are mutually exclusive
 	if cmdLineArgs.collector != "" && cmdLineArgs.reporter != "" {
 		err = fmt.Errorf("-collector and -reporter are mutually exclusive options")
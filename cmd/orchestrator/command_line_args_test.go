@@ -6,6 +6,8 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -49,10 +51,14 @@ func TestTooMuchAnalysis(t *testing.T) {
 }
 
 func TestTooMuchProfiling(t *testing.T) {
-	if isValid([]string{"-profile", "all", "-profile_duration", "302"}) {
+	if isValid([]string{"-profile", "all", "-profile_duration", "3602"}) {
 		t.Fail()
 	}
-	if isValid([]string{"-profile", "all", "-profile_interval", "1", "-profile_duration", "200"}) {
+	if isValid([]string{"-profile", "all", "-profile_interval", "1", "-profile_duration", "2000"}) {
+		t.Fail()
+	}
+	// 30 minute soak test
+	if !isValid([]string{"-profile", "all", "-profile_duration", "1800"}) {
 		t.Fail()
 	}
 }
@@ -154,3 +160,137 @@ func TestPortNoIpUser(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestSummaryJSON(t *testing.T) {
+	if !isValid([]string{"-summary-json", "/tmp/summary.json"}) {
+		t.Fail()
+	}
+	if isValid([]string{"-summary-json", "/foo/bar/summary.json"}) {
+		t.Fail()
+	}
+}
+
+func TestJump(t *testing.T) {
+	if !isValid([]string{"-ip", "192.168.1.1", "-user", "foo", "-jump", "admin@bastion"}) {
+		t.Fail()
+	}
+	if isValid([]string{"-jump", "admin@bastion"}) {
+		t.Fail()
+	}
+}
+
+func TestSSHMultiplex(t *testing.T) {
+	args := newCmdLineArgs()
+	if err := args.parse("tester", []string{"-ip", "192.168.1.1", "-user", "foo"}); err != nil || !args.sshMultiplex {
+		t.Fatal("expected ssh multiplexing by default")
+	}
+	args = newCmdLineArgs()
+	if err := args.parse("tester", []string{"-ip", "192.168.1.1", "-user", "foo", "-ssh-multiplex=false"}); err != nil || args.sshMultiplex {
+		t.Fatal("expected ssh multiplexing to be disabled")
+	}
+}
+
+func TestAppend(t *testing.T) {
+	if !isValid([]string{"-output", "/tmp", "-append"}) {
+		t.Fatal("expected -append to be valid with -output")
+	}
+	if isValid([]string{"-append"}) {
+		t.Fatal("expected -append to require -output")
+	}
+}
+
+func TestTargetsStdin(t *testing.T) {
+	if !isValid(([]string{"-targets", "-"})) {
+		t.Fail()
+	}
+}
+
+func TestReportTimeout(t *testing.T) {
+	if !isValid([]string{"-report-timeout", "60"}) {
+		t.Fail()
+	}
+	if isValid([]string{"-report-timeout", "0"}) {
+		t.Fail()
+	}
+}
+
+func TestK8sSelector(t *testing.T) {
+	if !isValid([]string{"-k8s-selector", "node-role.kubernetes.io/worker=", "-user", "foo"}) {
+		t.Fail()
+	}
+	if !isValid([]string{"-k8s-selector", "env=perf", "-user", "foo", "-port", "2222"}) {
+		t.Fail()
+	}
+	// user is required
+	if isValid([]string{"-k8s-selector", "env=perf"}) {
+		t.Fail()
+	}
+	// mutually exclusive with -ip and -targets
+	if isValid([]string{"-k8s-selector", "env=perf", "-user", "foo", "-ip", "192.168.1.1"}) {
+		t.Fail()
+	}
+	if isValid([]string{"-k8s-selector", "env=perf", "-user", "foo", "-targets", "targets.example"}) {
+		t.Fail()
+	}
+	// kubeconfig and context
+	if !isValid([]string{"-k8s-selector", "env=perf", "-user", "foo", "-k8s-kubeconfig", "targets.example", "-k8s-context", "perf"}) {
+		t.Fail()
+	}
+	if isValid([]string{"-k8s-selector", "env=perf", "-user", "foo", "-k8s-kubeconfig", "doesnotexist"}) {
+		t.Fail()
+	}
+	if isValid([]string{"-ip", "192.168.1.1", "-user", "foo", "-k8s-context", "perf"}) {
+		t.Fail()
+	}
+}
+
+func TestMaxArchiveSize(t *testing.T) {
+	if !isValid([]string{"-max-archive-size", "500M"}) {
+		t.Fail()
+	}
+	if isValid([]string{"-max-archive-size", "100K"}) {
+		t.Fail()
+	}
+	if isValid([]string{"-max-archive-size", "big"}) {
+		t.Fail()
+	}
+}
+
+func TestPostHook(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "upload.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if !isValid([]string{"-post-hook", script}) {
+		t.Fail()
+	}
+	notExecutable := filepath.Join(dir, "upload.txt")
+	if err := os.WriteFile(notExecutable, []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if isValid([]string{"-post-hook", notExecutable}) {
+		t.Fail()
+	}
+	if isValid([]string{"-post-hook", dir}) {
+		t.Fail()
+	}
+	if isValid([]string{"-post-hook", "no_such_script.sh"}) {
+		t.Fail()
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	if !isValid([]string{"-targets", "targets.example", "-credentials", "targets.example"}) { // any file will do
+		t.Fail()
+	}
+	if isValid([]string{"-credentials", "targets.example"}) {
+		t.Fail()
+	}
+	if isValid([]string{"-targets", "targets.example", "-credentials", "/foo/bar/credentials"}) {
+		t.Fail()
+	}
+	if isValid([]string{"-targets", "targets.example", "-credentials-key", "secret"}) {
+		t.Fail()
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+// target passwords from an encrypted credentials file, see -credentials
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	agearmor "filippo.io/age/armor"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"gopkg.in/yaml.v2"
+)
+
+// credentialsKeyEnv is the environment variable that provides the credentials file's key when
+// -credentials-key isn't provided
+const credentialsKeyEnv = "SVR_INFO_CREDENTIALS_KEY"
+
+// credential is an entry in the credentials file, referenced by a target's cred_ref
+type credential struct {
+	Password string `yaml:"ssh_password"`
+	Sudo     string `yaml:"sudo_password"`
+}
+
+// loadCredentials decrypts the age or GPG encrypted credentials file with the key and returns
+// its entries by name
+func loadCredentials(path string, key string) (credentials map[string]credential, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	plaintext, err := decryptCredentials(content, key)
+	if err != nil {
+		err = fmt.Errorf("failed to decrypt credentials file %s: %v", path, err)
+		return
+	}
+	if err = yaml.Unmarshal(plaintext, &credentials); err != nil {
+		// don't include yaml's error, it may quote the decrypted content
+		err = fmt.Errorf("credentials file %s : format error, expected 'name: {ssh_password: ..., sudo_password: ...}' entries", path)
+	}
+	return
+}
+
+// decryptCredentials decrypts age (binary or armored) or OpenPGP (binary or armored) content.
+// The key is an age identity (AGE-SECRET-KEY-...) or the passphrase used to encrypt the file,
+// e.g., with 'age -p' or 'gpg --symmetric'.
+func decryptCredentials(content []byte, key string) (plaintext []byte, err error) {
+	if key == "" {
+		err = fmt.Errorf("key required, use -credentials-key or %s", credentialsKeyEnv)
+		return
+	}
+	var reader io.Reader
+	if bytes.HasPrefix(content, []byte(agearmor.Header)) || bytes.HasPrefix(content, []byte("age-encryption.org/")) {
+		var source io.Reader = bytes.NewReader(content)
+		if bytes.HasPrefix(content, []byte(agearmor.Header)) {
+			source = agearmor.NewReader(source)
+		}
+		var identity age.Identity
+		if strings.HasPrefix(key, "AGE-SECRET-KEY-") {
+			identity, err = age.ParseX25519Identity(key)
+		} else {
+			identity, err = age.NewScryptIdentity(key)
+		}
+		if err != nil {
+			return
+		}
+		if reader, err = age.Decrypt(source, identity); err != nil {
+			return
+		}
+	} else {
+		var source io.Reader = bytes.NewReader(content)
+		if bytes.HasPrefix(content, []byte("-----BEGIN PGP MESSAGE-----")) {
+			var block *armor.Block
+			if block, err = armor.Decode(source); err != nil {
+				return
+			}
+			source = block.Body
+		}
+		prompted := false
+		prompt := func(keys []openpgp.Key, symmetric bool) ([]byte, error) {
+			if prompted || !symmetric {
+				return nil, fmt.Errorf("incorrect key or file not encrypted with a passphrase")
+			}
+			prompted = true
+			return []byte(key), nil
+		}
+		var message *openpgp.MessageDetails
+		if message, err = openpgp.ReadMessage(source, openpgp.EntityList{}, prompt, nil); err != nil {
+			return
+		}
+		reader = message.UnverifiedBody
+	}
+	plaintext, err = io.ReadAll(reader)
+	return
+}
+
+// applyCredentials sets the passwords of the targets that have a cred_ref from the credentials file
+func applyCredentials(targets []targetFromFile, credentialsPath string, key string) (err error) {
+	var credentials map[string]credential
+	for i := range targets {
+		if targets[i].credRef == "" {
+			continue
+		}
+		if credentialsPath == "" {
+			err = fmt.Errorf("-targets : cred_ref %s on line %d requires -credentials", targets[i].credRef, targets[i].lineNo)
+			return
+		}
+		if credentials == nil {
+			if credentials, err = loadCredentials(credentialsPath, key); err != nil {
+				return
+			}
+		}
+		cred, ok := credentials[targets[i].credRef]
+		if !ok {
+			err = fmt.Errorf("-credentials %s : cred_ref %s (line %d of targets file) not found", credentialsPath, targets[i].credRef, targets[i].lineNo)
+			return
+		}
+		targets[i].pwd = cred.Password
+		targets[i].sudo = strings.ReplaceAll(cred.Sudo, "$", "\\$") // escape $ in sudo password, as in the targets file
+	}
+	return
+}
+
+// maskCredentialsKey returns a copy of the program arguments with the -credentials-key value
+// replaced, so that the key isn't logged
+func maskCredentialsKey(args []string) (masked []string) {
+	masked = append(masked, args...)
+	for i, arg := range masked {
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "credentials-key" {
+			continue
+		}
+		if hasValue {
+			masked[i] = arg[:strings.Index(arg, "=")+1] + "********"
+		} else if i+1 < len(masked) {
+			masked[i+1] = "********"
+		}
+	}
+	return
+}
+
+// readCredentialsKey returns the -credentials-key, or, when not provided, the key from the
+// environment
+func readCredentialsKey(key string) string {
+	if key != "" {
+		return key
+	}
+	return os.Getenv(credentialsKeyEnv)
+}
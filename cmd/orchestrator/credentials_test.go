@@ -0,0 +1,122 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	agearmor "filippo.io/age/armor"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+const testCredentials = "db1: {ssh_password: logmein, sudo_password: elevate$me}\n"
+
+func encryptAge(t *testing.T, recipient age.Recipient) []byte {
+	var buf bytes.Buffer
+	armored := agearmor.NewWriter(&buf)
+	w, err := age.Encrypt(armored, recipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write([]byte(testCredentials)); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	armored.Close()
+	return buf.Bytes()
+}
+
+func encryptGPG(t *testing.T, passphrase string) []byte {
+	var buf bytes.Buffer
+	armored, err := armor.Encode(&buf, "PGP MESSAGE", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := openpgp.SymmetricallyEncrypt(armored, []byte(passphrase), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write([]byte(testCredentials)); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	armored.Close()
+	return buf.Bytes()
+}
+
+func TestDecryptCredentials(t *testing.T) {
+	scryptRecipient, err := age.NewScryptRecipient("passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scryptRecipient.SetWorkFactor(10) // fast, for testing
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		content []byte
+		key     string
+	}{
+		{"age passphrase", encryptAge(t, scryptRecipient), "passphrase"},
+		{"age identity", encryptAge(t, identity.Recipient()), identity.String()},
+		{"gpg passphrase", encryptGPG(t, "passphrase"), "passphrase"},
+	}
+	for _, test := range tests {
+		plaintext, err := decryptCredentials(test.content, test.key)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if string(plaintext) != testCredentials {
+			t.Errorf("%s: unexpected plaintext", test.name)
+		}
+		if _, err = decryptCredentials(test.content, "wrong"); err == nil {
+			t.Errorf("%s: expected error for wrong key", test.name)
+		}
+	}
+}
+
+func TestApplyCredentials(t *testing.T) {
+	credentialsPath := filepath.Join(t.TempDir(), "credentials.gpg")
+	if err := os.WriteFile(credentialsPath, encryptGPG(t, "passphrase"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	targets := []targetFromFile{{ip: "ip", credRef: "db1"}, {ip: "ip2", pwd: "other"}}
+	if err := applyCredentials(targets, credentialsPath, "passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	if targets[0].pwd != "logmein" || targets[0].sudo != "elevate\\$me" {
+		t.Error("credentials not applied")
+	}
+	if targets[1].pwd != "other" {
+		t.Error("target without cred_ref changed")
+	}
+	targets = []targetFromFile{{ip: "ip", credRef: "db2"}}
+	if err := applyCredentials(targets, credentialsPath, "passphrase"); err == nil {
+		t.Error("expected error for unknown cred_ref")
+	}
+	if err := applyCredentials(targets, "", "passphrase"); err == nil {
+		t.Error("expected error for cred_ref without credentials file")
+	}
+}
+
+func TestMaskCredentialsKey(t *testing.T) {
+	args := []string{"svr-info", "-credentials-key", "secret1", "--credentials-key=secret2", "-targets", "targets"}
+	masked := strings.Join(maskCredentialsKey(args), " ")
+	if strings.Contains(masked, "secret") {
+		t.Errorf("key not masked: %s", masked)
+	}
+	if args[2] != "secret1" {
+		t.Error("arguments modified")
+	}
+}
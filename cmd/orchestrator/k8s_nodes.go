@@ -0,0 +1,88 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+
+	"github.com/intel/svr-info/internal/target"
+)
+
+type k8sNode struct {
+	name string
+	ip   string
+}
+
+// kubectl get nodes -o json, only the fields we need
+type k8sNodeList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Status struct {
+			Addresses []struct {
+				Type    string `json:"type"`
+				Address string `json:"address"`
+			} `json:"addresses"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// getK8sNodes returns the name and internal IP address of the Kubernetes nodes that match the
+// label selector. The cluster is the kubeconfig's context, when provided, otherwise kubectl's
+// current context from KUBECONFIG or ~/.kube/config or, when running in a pod, the in-cluster
+// service account.
+func getK8sNodes(selector string, kubeconfig string, context string) (nodes []k8sNode, err error) {
+	if _, err = exec.LookPath("kubectl"); err != nil {
+		err = fmt.Errorf("-k8s-selector requires kubectl, not found in PATH: %v", err)
+		return
+	}
+	cmd := exec.Command("kubectl", getKubectlArgs(selector, kubeconfig, context)...)
+	stdout, stderr, exitCode, err := target.RunLocalCommand(cmd)
+	if err != nil {
+		err = fmt.Errorf("failed to get Kubernetes nodes (exit code %d): %v, %s", exitCode, err, stderr)
+		return
+	}
+	return parseK8sNodes([]byte(stdout))
+}
+
+// getKubectlArgs returns the arguments of the kubectl command that lists the selected nodes
+func getKubectlArgs(selector string, kubeconfig string, context string) (args []string) {
+	if kubeconfig != "" {
+		args = append(args, "--kubeconfig", kubeconfig)
+	}
+	if context != "" {
+		args = append(args, "--context", context)
+	}
+	args = append(args, "get", "nodes", "--selector", selector, "--output", "json")
+	return
+}
+
+func parseK8sNodes(content []byte) (nodes []k8sNode, err error) {
+	var nodeList k8sNodeList
+	err = json.Unmarshal(content, &nodeList)
+	if err != nil {
+		err = fmt.Errorf("failed to parse Kubernetes node list: %v", err)
+		return
+	}
+	for _, item := range nodeList.Items {
+		var ip string
+		for _, address := range item.Status.Addresses {
+			if address.Type == "InternalIP" {
+				ip = address.Address
+				break
+			}
+		}
+		if ip == "" {
+			log.Printf("Kubernetes node %s has no internal IP address, skipping", item.Metadata.Name)
+			continue
+		}
+		nodes = append(nodes, k8sNode{name: item.Metadata.Name, ip: ip})
+	}
+	return
+}
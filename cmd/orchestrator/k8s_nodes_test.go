@@ -0,0 +1,73 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseK8sNodes(t *testing.T) {
+	content := `{
+	"apiVersion": "v1",
+	"items": [
+		{
+			"metadata": {"name": "worker-1", "labels": {"node-role.kubernetes.io/worker": ""}},
+			"status": {"addresses": [
+				{"type": "Hostname", "address": "worker-1"},
+				{"type": "InternalIP", "address": "10.0.0.11"}
+			]}
+		},
+		{
+			"metadata": {"name": "worker-2"},
+			"status": {"addresses": [
+				{"type": "ExternalIP", "address": "203.0.113.12"},
+				{"type": "InternalIP", "address": "10.0.0.12"}
+			]}
+		},
+		{
+			"metadata": {"name": "no-ip"},
+			"status": {"addresses": [{"type": "Hostname", "address": "no-ip"}]}
+		}
+	],
+	"kind": "List"
+}`
+	nodes, err := parseK8sNodes([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %d", len(nodes))
+	}
+	if nodes[0].name != "worker-1" || nodes[0].ip != "10.0.0.11" {
+		t.Fail()
+	}
+	if nodes[1].name != "worker-2" || nodes[1].ip != "10.0.0.12" {
+		t.Fail()
+	}
+}
+
+func TestParseK8sNodesInvalid(t *testing.T) {
+	_, err := parseK8sNodes([]byte("error: the server doesn't have a resource type \"nodes\""))
+	if err == nil {
+		t.Fail()
+	}
+}
+
+func TestGetKubectlArgs(t *testing.T) {
+	for _, tc := range []struct {
+		kubeconfig string
+		context    string
+		expected   string
+	}{
+		{"", "", "get nodes --selector env=perf --output json"},
+		{"/tmp/kubeconfig", "", "--kubeconfig /tmp/kubeconfig get nodes --selector env=perf --output json"},
+		{"/tmp/kubeconfig", "perf", "--kubeconfig /tmp/kubeconfig --context perf get nodes --selector env=perf --output json"},
+	} {
+		if args := strings.Join(getKubectlArgs("env=perf", tc.kubeconfig, tc.context), " "); args != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, args)
+		}
+	}
+}
@@ -7,6 +7,8 @@ package main
 import (
 	"archive/tar"
 	"compress/gzip"
+	"context"
+	"crypto/sha256"
 	"embed"
 	"encoding/binary"
 	"fmt"
@@ -35,21 +37,37 @@ var (
 )
 
 type App struct {
-	outputDir string
-	tempDir   string
-	args      *CmdLineArgs
+	outputDir                   string
+	tempDir                     string
+	args                        *CmdLineArgs
+	targetDurations             map[string]int // target name -> duration override from targets file
+	previousCollectionFilePaths []string       // raw data collected into outputDir by previous runs, see -append
 }
 
 func newApp(args *CmdLineArgs, outputDir string, tempDir string) *App {
 	app := App{
-		outputDir: outputDir,
-		tempDir:   tempDir,
-		args:      args,
+		outputDir:       outputDir,
+		tempDir:         tempDir,
+		args:            args,
+		targetDurations: make(map[string]int),
 	}
 	return &app
 }
 
 func (app *App) getTargets() (targets []target.Target, err error) {
+	// if selecting Kubernetes nodes
+	if app.args.k8sSelector != "" {
+		var nodes []k8sNode
+		nodes, err = getK8sNodes(app.args.k8sSelector, app.args.k8sKubeconfig, app.args.k8sContext)
+		if err != nil {
+			return
+		}
+		for _, node := range nodes {
+			targets = append(targets, target.NewRemoteTarget(node.name, node.ip, fmt.Sprintf("%d", app.args.port), app.args.user, app.args.key, "", "", app.args.jump, app.args.sshMultiplex))
+		}
+		log.Printf("Found %d Kubernetes node(s) matching selector %s", len(nodes), app.args.k8sSelector)
+		return
+	}
 	// if we have a targets file
 	if app.args.targets != "" {
 		targetsFile := newTargetsFile(app.args.targets)
@@ -58,6 +76,10 @@ func (app *App) getTargets() (targets []target.Target, err error) {
 		if err != nil {
 			return
 		}
+		err = applyCredentials(targetsFromFile, app.args.credentials, readCredentialsKey(app.args.credentialsKey))
+		if err != nil {
+			return
+		}
 		for _, t := range targetsFromFile {
 			if t.ip == "localhost" { // special case, "localhost" in targets file
 				var hostname string
@@ -76,7 +98,14 @@ func (app *App) getTargets() (targets []target.Target, err error) {
 				}
 				targets = append(targets, localTarget)
 			} else {
-				targets = append(targets, target.NewRemoteTarget(t.label, t.ip, t.port, t.user, t.key, t.pwd, filepath.Join(app.tempDir, "sshpass"), t.sudo))
+				jump := app.args.jump
+				if t.jump != "" {
+					jump = t.jump
+				}
+				targets = append(targets, target.NewRemoteTarget(t.label, t.ip, t.port, t.user, t.key, t.pwd, t.sudo, jump, app.args.sshMultiplex))
+			}
+			if t.duration > 0 {
+				app.targetDurations[targets[len(targets)-1].GetName()] = t.duration
 			}
 		}
 	} else {
@@ -114,19 +143,26 @@ func (app *App) getTargets() (targets []target.Target, err error) {
 			}
 			targets = append(targets, localTarget)
 		} else {
-			targets = append(targets, target.NewRemoteTarget(app.args.ipAddress, app.args.ipAddress, fmt.Sprintf("%d", app.args.port), app.args.user, app.args.key, "", "", ""))
+			targets = append(targets, target.NewRemoteTarget(app.args.ipAddress, app.args.ipAddress, fmt.Sprintf("%d", app.args.port), app.args.user, app.args.key, "", "", app.args.jump, app.args.sshMultiplex))
 		}
 	}
 	return
 }
 
+// logStatus writes plain status lines, in place of the spinner, when -quiet is specified
+func logStatus(label string, status string) (err error) {
+	_, err = fmt.Fprintf(os.Stderr, "%s: %s\n", label, status)
+	return
+}
+
 // go routine
 func doCollection(collection *Collection, ch chan *Collection, statusUpdate progress.MultiSpinnerUpdateFunc) {
 	if statusUpdate != nil {
 		statusUpdate(collection.target.GetName(), "collecting data")
 	}
-	err := collection.Collect()
+	err := collection.Collect(statusUpdate)
 	if err != nil {
+		collection.err = err
 		log.Printf("Error: %v", err)
 		if statusUpdate != nil {
 			statusUpdate(collection.target.GetName(), "error collecting data")
@@ -143,7 +179,15 @@ func (app *App) getCollections(targets []target.Target, statusUpdate progress.Mu
 	// run collections in parallel
 	ch := make(chan *Collection)
 	for _, target := range targets {
-		collection := newCollection(target, app.args, app.outputDir, app.tempDir)
+		args := app.args
+		// targets file may override the profile and analyze durations for this target
+		if duration, ok := app.targetDurations[target.GetName()]; ok {
+			targetArgs := *app.args
+			targetArgs.profileDuration = duration
+			targetArgs.analyzeDuration = duration
+			args = &targetArgs
+		}
+		collection := newCollection(target, args, app.outputDir, app.tempDir)
 		go doCollection(collection, ch, statusUpdate)
 	}
 	// wait for all collections to complete collecting
@@ -179,9 +223,22 @@ func (app *App) getReports(collections []*Collection, statusUpdate progress.Mult
 	for _, collection := range okCollections {
 		collectionFilePaths = append(collectionFilePaths, collection.outputFilePath)
 	}
-	cmd := exec.Command(filepath.Join(app.tempDir, "reporter"), "-input", strings.Join(collectionFilePaths, ","), "-output", app.outputDir, "-format", app.args.format)
+	if app.args.append {
+		app.previousCollectionFilePaths, err = getPreviousCollectionFilePaths(app.outputDir, collections)
+		if err != nil {
+			return
+		}
+		collectionFilePaths = append(collectionFilePaths, app.previousCollectionFilePaths...)
+	}
+	// kill the reporter if it doesn't finish in time
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(app.args.reportTimeout)*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, filepath.Join(app.tempDir, "reporter"), "-input", strings.Join(collectionFilePaths, ","), "-output", app.outputDir, "-format", app.args.format)
 	log.Printf("run: %s", strings.Join(cmd.Args, " "))
 	stdout, _, _, err := target.RunLocalCommand(cmd)
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("reporter did not finish within %d seconds (see -report-timeout)", app.args.reportTimeout)
+	}
 	if err != nil {
 		for _, collection := range collections {
 			if statusUpdate != nil {
@@ -202,14 +259,58 @@ func (app *App) getReports(collections []*Collection, statusUpdate progress.Mult
 	return
 }
 
-func archiveOutputDir(outputDir string, collections []*Collection, reportFilePaths []string) (err error) {
+// getPreviousCollectionFilePaths returns the paths to the raw data files in outputDir that
+// weren't written by the collections, i.e., those collected by previous runs
+func getPreviousCollectionFilePaths(outputDir string, collections []*Collection) (filePaths []string, err error) {
+	rawFilePaths, err := filepath.Glob(filepath.Join(outputDir, "*.raw.json"))
+	if err != nil {
+		return
+	}
+	var collected []string
+	for _, collection := range collections {
+		collected = append(collected, collection.target.GetName()+".raw.json")
+	}
+	for _, rawFilePath := range rawFilePaths {
+		if !slices.Contains(collected, filepath.Base(rawFilePath)) {
+			filePaths = append(filePaths, rawFilePath)
+		}
+	}
+	return
+}
+
+// archiveOutputDir archives the output files, the archive is split into numbered volumes when it
+// is larger than maxArchiveSize bytes, 0 for no limit. Returns the path to the archive, or to its
+// manifest when it was split.
+func archiveOutputDir(outputDir string, collections []*Collection, reportFilePaths []string, previousCollectionFilePaths []string, maxArchiveSize int64) (archivePath string, err error) {
 	tarFilePath := filepath.Join(outputDir, filepath.Base(outputDir)+".tgz")
+	members, err := writeArchive(tarFilePath, outputDir, collections, reportFilePaths, previousCollectionFilePaths, maxArchiveSize > 0)
+	if err != nil {
+		return
+	}
+	archivePath = tarFilePath
+	if maxArchiveSize > 0 {
+		var split bool
+		if split, err = splitArchive(tarFilePath, members, maxArchiveSize); err != nil {
+			return
+		}
+		if split {
+			archivePath = tarFilePath + ".manifest"
+		}
+	}
+	return
+}
+
+// writeArchive writes the output files to the tarFilePath archive. When flushMembers is set, the
+// compressed stream is flushed after each file so that the archive can be split between files,
+// the offsets of the file boundaries are returned.
+func writeArchive(tarFilePath string, outputDir string, collections []*Collection, reportFilePaths []string, previousCollectionFilePaths []string, flushMembers bool) (members []archiveMember, err error) {
 	out, err := os.Create(tarFilePath)
 	if err != nil {
 		return
 	}
 	defer out.Close()
-	gw := gzip.NewWriter(out)
+	cw := &countingWriter{w: out}
+	gw := gzip.NewWriter(cw)
 	defer gw.Close()
 	tw := tar.NewWriter(gw)
 	defer tw.Close()
@@ -237,7 +338,12 @@ func archiveOutputDir(outputDir string, collections []*Collection, reportFilePat
 	for _, reportFilePath := range reportFilePaths {
 		filesToArchive = append(filesToArchive, filepath.Base(reportFilePath))
 	}
+	for _, previousCollectionFilePath := range previousCollectionFilePaths {
+		filesToArchive = append(filesToArchive, filepath.Base(previousCollectionFilePath))
+	}
 	filesToArchive = append(filesToArchive, "reporter.log")
+	// checksums of archived files, in sha256sum format, i.e., can be verified with 'sha256sum -c'
+	var checksums []string
 	err = filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -264,19 +370,53 @@ func archiveOutputDir(outputDir string, collections []*Collection, reportFilePat
 				if err != nil {
 					return err
 				}
-				_, err = io.Copy(tw, file)
+				hash := sha256.New()
+				_, err = io.Copy(io.MultiWriter(tw, hash), file)
 				file.Close()
 				if err != nil {
 					return err
 				}
+				checksums = append(checksums, fmt.Sprintf("%x  %s", hash.Sum(nil), path))
+				if flushMembers {
+					if err = tw.Flush(); err != nil {
+						return err
+					}
+					if err = gw.Flush(); err != nil {
+						return err
+					}
+					members = append(members, archiveMember{name: header.Name, end: cw.n})
+				}
 			}
 		}
 		return nil
 	})
+	if err != nil {
+		return
+	}
+	err = archiveChecksums(tw, filepath.Base(outputDir), checksums)
+	return
+}
+
+// archiveChecksums writes a checksums.txt manifest into the archive
+func archiveChecksums(tw *tar.Writer, archiveDir string, checksums []string) (err error) {
+	manifest := []byte(strings.Join(checksums, "\n") + "\n")
+	header := &tar.Header{
+		Name:    filepath.Join(archiveDir, "checksums.txt"),
+		Mode:    0644,
+		Size:    int64(len(manifest)),
+		ModTime: time.Now(),
+	}
+	err = tw.WriteHeader(header)
+	if err != nil {
+		return
+	}
+	_, err = tw.Write(manifest)
 	return
 }
 
-func cleanupOutputDir(outputDir string, collections []*Collection) (err error) {
+// cleanupOutputDir removes the intermediate files from outputDir, the raw data files are kept when
+// keepRawData is set so that a later run can -append to the reports
+func cleanupOutputDir(outputDir string, collections []*Collection, keepRawData bool) (err error) {
 	var filesToRemove []string
 	for _, collection := range collections {
 		hostname := collection.target.GetName()
@@ -287,7 +427,9 @@ func cleanupOutputDir(outputDir string, collections []*Collection) (err error) {
 		filesToRemove = append(filesToRemove, filepath.Join(outputDir, hostname+"_megadata_collector.log"))
 		filesToRemove = append(filesToRemove, filepath.Join(outputDir, hostname+"_megadata", "collector.log"))
 		filesToRemove = append(filesToRemove, filepath.Join(outputDir, hostname+"_megadata", "collector.pid"))
-		filesToRemove = append(filesToRemove, filepath.Join(outputDir, hostname+".raw.json"))
+		if !keepRawData {
+			filesToRemove = append(filesToRemove, filepath.Join(outputDir, hostname+".raw.json"))
+		}
 	}
 	filesToRemove = append(filesToRemove, filepath.Join(outputDir, "reporter.log"))
 	for _, file := range filesToRemove {
@@ -296,6 +438,22 @@ func cleanupOutputDir(outputDir string, collections []*Collection) (err error) {
 	return
 }
 
+// runPostHook runs the -post-hook script with the archive path and the output directory as its
+// arguments, and in the SVR_INFO_ARCHIVE and SVR_INFO_OUTPUT_DIR environment variables. The
+// collection has already succeeded, so a failing script is reported as a warning.
+func runPostHook(script string, archivePath string, outputDir string) {
+	cmd := exec.Command(script, archivePath, outputDir)
+	cmd.Env = append(os.Environ(), "SVR_INFO_ARCHIVE="+archivePath, "SVR_INFO_OUTPUT_DIR="+outputDir)
+	log.Printf("running post-hook: %s", strings.Join(cmd.Args, " "))
+	stdout, stderr, exitCode, err := target.RunLocalCommand(cmd)
+	log.Printf("post-hook stdout: %s", stdout)
+	log.Printf("post-hook stderr: %s", stderr)
+	if err != nil {
+		log.Printf("post-hook failed (exit code %d): %v", exitCode, err)
+		fmt.Fprintf(os.Stderr, "WARNING: post-hook %s failed (exit code %d): %v\n", script, exitCode, err)
+	}
+}
+
 func (app *App) doWork() (err error) {
 	if app.args.printConfig {
 		var bytes []byte
@@ -318,32 +476,50 @@ func (app *App) doWork() (err error) {
 	if len(targets) == 0 {
 		return fmt.Errorf("no targets provided")
 	}
-	multiSpinner := progress.NewMultiSpinner()
-	for _, t := range targets {
-		multiSpinner.AddSpinner(t.GetName())
+	var multiSpinner *progress.MultiSpinner
+	var statusUpdate progress.MultiSpinnerUpdateFunc = logStatus
+	if !app.args.quiet {
+		multiSpinner = progress.NewMultiSpinner()
+		for _, t := range targets {
+			multiSpinner.AddSpinner(t.GetName())
+		}
+		multiSpinner.Start()
+		defer multiSpinner.Finish()
+		statusUpdate = multiSpinner.Status
 	}
-	multiSpinner.Start()
-	defer multiSpinner.Finish()
-	collections, err := app.getCollections(targets, multiSpinner.Status)
+	collections, err := app.getCollections(targets, statusUpdate)
 	if err != nil {
 		return err
 	}
 	var reportFilePaths []string
-	reportFilePaths, err = app.getReports(collections, multiSpinner.Status)
+	reportFilePaths, err = app.getReports(collections, statusUpdate)
+	if app.args.summaryJSON != "" {
+		// write the summary even when reports could not be generated
+		summaryErr := writeSummary(app.args.summaryJSON, newSummary(collections, reportFilePaths, err))
+		if summaryErr != nil {
+			log.Printf("Error: %v", summaryErr)
+			fmt.Fprintf(os.Stderr, "Error: failed to write summary: %v\n", summaryErr)
+		}
+	}
 	if err != nil {
 		return err
 	}
-	err = archiveOutputDir(app.outputDir, collections, reportFilePaths)
+	archivePath, err := archiveOutputDir(app.outputDir, collections, reportFilePaths, app.previousCollectionFilePaths, app.args.maxArchiveBytes)
 	if err != nil {
 		return err
 	}
 	if !app.args.debug {
-		err = cleanupOutputDir(app.outputDir, collections)
+		err = cleanupOutputDir(app.outputDir, collections, app.args.append)
 		if err != nil {
 			return err
 		}
 	}
-	multiSpinner.Finish()
+	if app.args.postHook != "" {
+		runPostHook(app.args.postHook, archivePath, app.outputDir)
+	}
+	if multiSpinner != nil {
+		multiSpinner.Finish()
+	}
 	fmt.Print("Reports:\n")
 	for _, reportFilePath := range reportFilePaths {
 		relativePath, err := filepath.Rel(filepath.Join(app.outputDir, ".."), reportFilePath)
@@ -352,6 +528,15 @@ func (app *App) doWork() (err error) {
 		}
 		fmt.Printf("  %s\n", relativePath)
 	}
+	var failedTargets []string
+	for _, collection := range collections {
+		if !collection.ok {
+			failedTargets = append(failedTargets, collection.target.GetName())
+		}
+	}
+	if len(failedTargets) > 0 {
+		return fmt.Errorf("failed to collect data from %d of %d target(s): %s", len(failedTargets), len(collections), strings.Join(failedTargets, ", "))
+	}
 	return nil
 }
 
@@ -360,7 +545,7 @@ func getLogfileName() string {
 }
 
 func (app *App) writeExecutableResources() (err error) {
-	toolNames := []string{"sshpass", "reporter", "collector", "collector_arm64", "collector_deps_amd64.tgz", "collector_deps_arm64.tgz"}
+	toolNames := []string{"reporter", "collector", "collector_arm64", "collector_deps_amd64.tgz", "collector_deps_arm64.tgz"}
 	for _, toolName := range toolNames {
 		// get the exe from our embedded resources
 		var toolBytes []byte
@@ -478,7 +663,7 @@ func mainReturnWithCode() int {
 		gVersion,
 		os.Getpid(),
 		os.Getppid(),
-		strings.Join(os.Args, " "),
+		strings.Join(maskCredentialsKey(os.Args), " "),
 	)
 	tempDir, err := os.MkdirTemp(cmdLineArgs.temp, fmt.Sprintf("%s.tmp.", filepath.Base(os.Args[0])))
 	if err != nil {
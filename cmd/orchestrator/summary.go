@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TargetSummary is the outcome of the data collection and reporting for one target
+type TargetSummary struct {
+	Name    string   `json:"name"`
+	OK      bool     `json:"ok"`
+	Reports []string `json:"reports"`
+	Error   string   `json:"error"`
+}
+
+// Summary is written to the -summary-json file so that scripts can check results
+// without parsing the log
+type Summary struct {
+	Targets []TargetSummary `json:"targets"`
+	Reports []string        `json:"reports"` // all report files, including multi-host reports
+}
+
+// newSummary builds the summary from the collections and the report files generated
+// from them. reportErr, if not nil, is the error encountered while generating reports.
+func newSummary(collections []*Collection, reportFilePaths []string, reportErr error) (summary Summary) {
+	summary.Reports = []string{}
+	summary.Reports = append(summary.Reports, reportFilePaths...)
+	for _, collection := range collections {
+		targetSummary := TargetSummary{
+			Name:    collection.target.GetName(),
+			OK:      collection.ok,
+			Reports: []string{},
+		}
+		// reporter names each host's report files <hostname>.<extension>
+		for _, reportFilePath := range reportFilePaths {
+			fileName := filepath.Base(reportFilePath)
+			if strings.TrimSuffix(fileName, filepath.Ext(fileName)) == targetSummary.Name {
+				targetSummary.Reports = append(targetSummary.Reports, reportFilePath)
+			}
+		}
+		if collection.err != nil {
+			targetSummary.Error = collection.err.Error()
+		} else if !collection.ok {
+			targetSummary.Error = "data collection failed"
+		} else if collection.ok && reportErr != nil {
+			targetSummary.Error = reportErr.Error()
+		}
+		summary.Targets = append(summary.Targets, targetSummary)
+	}
+	return
+}
+
+// writeSummary writes the summary to path as JSON
+func writeSummary(path string, summary Summary) (err error) {
+	var bytes []byte
+	bytes, err = json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return
+	}
+	err = os.WriteFile(path, append(bytes, '\n'), 0644)
+	return
+}
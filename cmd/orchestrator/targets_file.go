@@ -8,6 +8,7 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -16,14 +17,17 @@ import (
 )
 
 type targetFromFile struct {
-	label  string
-	ip     string
-	port   string
-	user   string
-	key    string
-	pwd    string
-	sudo   string
-	lineNo int
+	label    string
+	ip       string
+	port     string
+	user     string
+	key      string
+	pwd      string
+	sudo     string
+	duration int    // overrides the profile/analyze duration when greater than zero
+	jump     string // overrides the -jump host when not empty
+	credRef  string // names the entry in the -credentials file that provides pwd and sudo
+	lineNo   int
 }
 
 type TargetsFile struct {
@@ -34,8 +38,16 @@ func newTargetsFile(path string) *TargetsFile {
 	return &TargetsFile{path: path}
 }
 
+// stdinTargetsPath is the -targets value that reads the targets from stdin
+const stdinTargetsPath = "-"
+
 func (tf *TargetsFile) parse() (targets []targetFromFile, err error) {
-	content, err := os.ReadFile(tf.path)
+	var content []byte
+	if tf.path == stdinTargetsPath {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(tf.path)
+	}
 	if err != nil {
 		return
 	}
@@ -57,13 +69,18 @@ func (tf *TargetsFile) parseContent(content []byte) (targets []targetFromFile, e
 		}
 		tokens := strings.Split(line, ":")
 		var t targetFromFile
-		if len(tokens) != 6 && len(tokens) != 7 {
+		// 8 tokens when the optional duration is provided, the label is required (but may be empty) in that case
+		// 9 tokens when the optional jump host is provided, 10 when it includes a port, e.g., user@bastion:2222,
+		// the label and duration are required (but may be empty) in that case
+		// 10 or 11 tokens when the optional cred_ref follows the jump host, the jump host is required (but may be empty)
+		// in that case
+		if len(tokens) < 6 || len(tokens) > 11 {
 			fileErrors = append(fileErrors, fmt.Sprintf("-targets %s : format error, line %d\n", tf.path, lineNo))
 		} else {
 			i := 0
 			t.lineNo = lineNo
 			t.label = tokens[0]
-			if len(tokens) == 7 {
+			if len(tokens) >= 7 {
 				i++
 			}
 			t.ip = tokens[i]
@@ -71,6 +88,10 @@ func (tf *TargetsFile) parseContent(content []byte) (targets []targetFromFile, e
 			if t.ip == "" {
 				fileErrors = append(fileErrors, fmt.Sprintf("-targets %s : IP Address (or hostname) is required, line %d\n", tf.path, lineNo))
 			}
+			// label may be empty when the duration is provided, use the ip in that case
+			if len(tokens) >= 8 && t.label == "" && t.ip != "localhost" {
+				t.label = t.ip
+			}
 			// port is optional, but must be an integer if provided
 			t.port = tokens[i+1]
 			if t.port != "" {
@@ -98,6 +119,30 @@ func (tf *TargetsFile) parseContent(content []byte) (targets []targetFromFile, e
 			t.pwd = tokens[i+4]
 			t.sudo = tokens[i+5]
 			t.sudo = strings.ReplaceAll(t.sudo, "$", "\\$") // escape $ in sudo password
+			// duration is optional, but must be a positive integer if provided
+			if len(tokens) >= 8 && tokens[7] != "" {
+				duration, err := strconv.Atoi(tokens[7])
+				if err != nil || duration <= 0 {
+					fileErrors = append(fileErrors, fmt.Sprintf("-targets %s : invalid duration %s, line %d\n", tf.path, tokens[7], lineNo))
+				}
+				t.duration = duration
+			}
+			// jump host and cred_ref are optional, a 10th token is the jump host's port when it is a number
+			if len(tokens) >= 9 {
+				jumpTokens := tokens[8:]
+				if len(tokens) == 11 || (len(tokens) == 10 && !isJumpPort(tokens[8], tokens[9])) {
+					t.credRef = jumpTokens[len(jumpTokens)-1]
+					jumpTokens = jumpTokens[:len(jumpTokens)-1]
+				}
+				t.jump = strings.Join(jumpTokens, ":")
+				if t.jump != "" && t.ip == "localhost" {
+					fileErrors = append(fileErrors, fmt.Sprintf("-targets %s : jump host not supported for localhost, line %d\n", tf.path, lineNo))
+				}
+			}
+			// cred_ref provides the passwords, see applyCredentials
+			if t.credRef != "" && (t.pwd != "" || t.sudo != "") {
+				fileErrors = append(fileErrors, fmt.Sprintf("-targets %s : ssh_password and sudo_password must be empty when cred_ref is provided, line %d\n", tf.path, lineNo))
+			}
 			targets = append(targets, t)
 		}
 	}
@@ -107,3 +152,12 @@ func (tf *TargetsFile) parseContent(content []byte) (targets []targetFromFile, e
 	}
 	return
 }
+
+// isJumpPort returns true when the token that follows the jump host is the jump host's port
+func isJumpPort(jump string, token string) bool {
+	if jump == "" {
+		return false
+	}
+	_, err := strconv.Atoi(token)
+	return err == nil
+}
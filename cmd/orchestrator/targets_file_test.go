@@ -5,6 +5,7 @@
 package main
 
 import (
+	"os"
 	"strings"
 	"testing"
 )
@@ -203,3 +204,135 @@ func TestEscapeSudo(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestParseStdin(t *testing.T) {
+	content := `
+	label:localhost::user:::sudopassword
+	ip:22:user:targets.example:sshpassword:sudopassword
+	`
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = w.WriteString(content)
+	w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+	tf := newTargetsFile("-")
+	targets, err := tf.parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 2 {
+		t.Fail()
+	}
+	if targets[0].ip != "localhost" || targets[0].sudo != "sudopassword" {
+		t.Fail()
+	}
+	if targets[1].label != "ip" || targets[1].user != "user" {
+		t.Fail()
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	content := `
+	label:ip:22:user::sshpassword:sudopassword:30
+	:ip2:22:user::sshpassword:sudopassword:45
+	label3:ip3:22:user::sshpassword:sudopassword:
+	`
+	tf := newTargetsFile("testing")
+	targets, err := tf.parseContent([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 3 {
+		t.Fatal("expected 3 targets")
+	}
+	if targets[0].label != "label" || targets[0].sudo != "sudopassword" || targets[0].duration != 30 {
+		t.Fail()
+	}
+	if targets[1].label != "ip2" || targets[1].ip != "ip2" || targets[1].duration != 45 {
+		t.Fail()
+	}
+	if targets[2].label != "label3" || targets[2].duration != 0 {
+		t.Fail()
+	}
+}
+
+func TestParseInvalidDuration(t *testing.T) {
+	for _, content := range []string{
+		"label:ip:22:user::sshpassword:sudopassword:abc",
+		"label:ip:22:user::sshpassword:sudopassword:0",
+		"label:ip:22:user::sshpassword:sudopassword:-5",
+	} {
+		tf := newTargetsFile("testing")
+		_, err := tf.parseContent([]byte(content))
+		if err == nil {
+			t.Errorf("expected error for %s", content)
+		}
+	}
+}
+
+func TestParseJump(t *testing.T) {
+	content := `
+	label:ip:22:user::sshpassword:sudopassword::admin@bastion
+	:ip2:22:user::sshpassword:sudopassword:45:admin@bastion:2222
+	label3:ip3:22:user::sshpassword:sudopassword::
+	`
+	tf := newTargetsFile("testing")
+	targets, err := tf.parseContent([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 3 {
+		t.Fatal("expected 3 targets")
+	}
+	if targets[0].label != "label" || targets[0].duration != 0 || targets[0].jump != "admin@bastion" {
+		t.Fail()
+	}
+	if targets[1].label != "ip2" || targets[1].duration != 45 || targets[1].jump != "admin@bastion:2222" {
+		t.Fail()
+	}
+	if targets[2].jump != "" {
+		t.Fail()
+	}
+	if _, err = tf.parseContent([]byte("label:localhost:22:user::::admin@bastion")); err == nil {
+		t.Error("expected error for jump host with localhost")
+	}
+}
+
+func TestParseCredRef(t *testing.T) {
+	content := `
+	:ip::user::::::db1
+	:ip2::user:::::admin@bastion:db2
+	:ip3::user:::::admin@bastion:2222:db3
+	:ip4::user:::::admin@bastion:2222
+	`
+	tf := newTargetsFile("testing")
+	targets, err := tf.parseContent([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 4 {
+		t.Fatal("expected 4 targets")
+	}
+	if targets[0].credRef != "db1" || targets[0].jump != "" {
+		t.Errorf("unexpected cred_ref %s or jump %s", targets[0].credRef, targets[0].jump)
+	}
+	if targets[1].credRef != "db2" || targets[1].jump != "admin@bastion" {
+		t.Errorf("unexpected cred_ref %s or jump %s", targets[1].credRef, targets[1].jump)
+	}
+	if targets[2].credRef != "db3" || targets[2].jump != "admin@bastion:2222" {
+		t.Errorf("unexpected cred_ref %s or jump %s", targets[2].credRef, targets[2].jump)
+	}
+	if targets[3].credRef != "" || targets[3].jump != "admin@bastion:2222" {
+		t.Errorf("unexpected cred_ref %s or jump %s", targets[3].credRef, targets[3].jump)
+	}
+	if _, err = tf.parseContent([]byte(":ip::user::sshpassword::::db1")); err == nil {
+		t.Error("expected error for ssh password with cred_ref")
+	}
+}
@@ -10,6 +10,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
 	"os"
@@ -31,8 +32,9 @@ type EventDefinition struct {
 type GroupDefinition []EventDefinition
 
 // LoadEventGroups reads the events defined in the architecture specific event definition file, then
-// expands them to include the per-device uncore events
-func LoadEventGroups(eventDefinitionOverridePath string, metadata Metadata) (groups []GroupDefinition, err error) {
+// expands them to include the per-device uncore events. The names of all events defined in the file,
+// including those that can't be collected on the platform, are also returned.
+func LoadEventGroups(eventDefinitionOverridePath string, metadata Metadata) (groups []GroupDefinition, eventNames mapset.Set[string], err error) {
 	var file fs.File
 	if eventDefinitionOverridePath != "" {
 		if file, err = os.Open(eventDefinitionOverridePath); err != nil {
@@ -51,8 +53,71 @@ func LoadEventGroups(eventDefinitionOverridePath string, metadata Metadata) (gro
 		}
 	}
 	defer file.Close()
-	scanner := bufio.NewScanner(file)
-	uncollectableEvents := mapset.NewSet[string]()
+	var uncollectableEvents mapset.Set[string]
+	if groups, uncollectableEvents, err = readEventGroups(file, metadata); err != nil {
+		return
+	}
+	eventNames = getEventNames(groups).Union(uncollectableEvents)
+	// expand uncore groups for all uncore devices
+	groups, err = expandUncoreGroups(groups, metadata)
+	// // "fixed" PMU counters are not supported on (most) IaaS VMs, so we add a separate group
+	// if !isUncoreSupported(metadata) {
+	// 	group = GroupDefinition{EventDefinition{Raw: "cpu-cycles"}, EventDefinition{Raw: "instructions"}}
+	// 	if metadata.RefCyclesSupported {
+	// 		group = append(group, EventDefinition{Raw: "ref-cycles"})
+	// 	}
+	// 	groups = append(groups, group)
+	// 	group = GroupDefinition{EventDefinition{Raw: "cpu-cycles:k"}, EventDefinition{Raw: "instructions"}}
+	// 	if metadata.RefCyclesSupported {
+	// 		group = append(group, EventDefinition{Raw: "ref-cycles:k"})
+	// 	}
+	// 	groups = append(groups, group)
+
+	// }
+	if uncollectableEvents.Cardinality() != 0 && gCmdLineArgs.verbose {
+		log.Printf("Uncollectable events: %s", uncollectableEvents)
+	}
+	return
+}
+
+// LoadExtraEventGroups reads the events defined in a user-provided event definition file. Unlike
+// LoadEventGroups, events that can't be collected on the platform are reported as an error rather
+// than being silently dropped.
+func LoadExtraEventGroups(extraEventDefinitionPath string, metadata Metadata) (groups []GroupDefinition, eventNames mapset.Set[string], err error) {
+	var file *os.File
+	if file, err = os.Open(extraEventDefinitionPath); err != nil {
+		return
+	}
+	defer file.Close()
+	var uncollectableEvents mapset.Set[string]
+	if groups, uncollectableEvents, err = readEventGroups(file, metadata); err != nil {
+		return
+	}
+	if uncollectableEvents.Cardinality() != 0 {
+		err = fmt.Errorf("events not supported on this platform: %s", strings.Join(uncollectableEvents.ToSlice(), ", "))
+		return
+	}
+	eventNames = getEventNames(groups)
+	// expand uncore groups for all uncore devices
+	groups, err = expandUncoreGroups(groups, metadata)
+	return
+}
+
+// getEventNames returns the names of the events in the groups
+func getEventNames(groups []GroupDefinition) (eventNames mapset.Set[string]) {
+	eventNames = mapset.NewSet[string]()
+	for _, group := range groups {
+		for _, event := range group {
+			eventNames.Add(event.Name)
+		}
+	}
+	return
+}
+
+// readEventGroups parses event definitions into groups of collectable events
+func readEventGroups(reader io.Reader, metadata Metadata) (groups []GroupDefinition, uncollectableEvents mapset.Set[string], err error) {
+	scanner := bufio.NewScanner(reader)
+	uncollectableEvents = mapset.NewSet[string]()
 	var group GroupDefinition
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -78,28 +143,7 @@ func LoadEventGroups(eventDefinitionOverridePath string, metadata Metadata) (gro
 			group = GroupDefinition{} // clear the list
 		}
 	}
-	if err = scanner.Err(); err != nil {
-		return
-	}
-	// expand uncore groups for all uncore devices
-	groups, err = expandUncoreGroups(groups, metadata)
-	// // "fixed" PMU counters are not supported on (most) IaaS VMs, so we add a separate group
-	// if !isUncoreSupported(metadata) {
-	// 	group = GroupDefinition{EventDefinition{Raw: "cpu-cycles"}, EventDefinition{Raw: "instructions"}}
-	// 	if metadata.RefCyclesSupported {
-	// 		group = append(group, EventDefinition{Raw: "ref-cycles"})
-	// 	}
-	// 	groups = append(groups, group)
-	// 	group = GroupDefinition{EventDefinition{Raw: "cpu-cycles:k"}, EventDefinition{Raw: "instructions"}}
-	// 	if metadata.RefCyclesSupported {
-	// 		group = append(group, EventDefinition{Raw: "ref-cycles:k"})
-	// 	}
-	// 	groups = append(groups, group)
-
-	// }
-	if uncollectableEvents.Cardinality() != 0 && gCmdLineArgs.verbose {
-		log.Printf("Uncollectable events: %s", uncollectableEvents)
-	}
+	err = scanner.Err()
 	return
 }
 
@@ -244,3 +288,15 @@ func expandUncoreGroups(groups []GroupDefinition, metadata Metadata) (expandedGr
 	}
 	return
 }
+
+// splitEventGroups divides the event groups into batches of, at most, maxGroups groups
+func splitEventGroups(eventGroups []GroupDefinition, maxGroups int) (batches [][]GroupDefinition) {
+	for start := 0; start < len(eventGroups); start += maxGroups {
+		end := start + maxGroups
+		if end > len(eventGroups) {
+			end = len(eventGroups)
+		}
+		batches = append(batches, eventGroups[start:end])
+	}
+	return
+}
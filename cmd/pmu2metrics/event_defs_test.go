@@ -0,0 +1,64 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadExtraEventGroups(t *testing.T) {
+	metadata := Metadata{
+		DeviceIDs:           map[string][]int{"cha": {0, 1}},
+		PerfSupportedEvents: "cpu-cycles instructions",
+	}
+	dir := t.TempDir()
+	// one core group and one uncore group, uncore group expands to one group per device
+	path := filepath.Join(dir, "extra.txt")
+	events := "# extra events\n" +
+		"cpu-cycles,\n" +
+		"instructions;\n" +
+		"cha/event=0x35,umask=0xc80ffe01,name='UNC_CHA_TOR_INSERTS.IA_MISS_CRD'/;\n"
+	if err := os.WriteFile(path, []byte(events), 0644); err != nil {
+		t.Fatal(err)
+	}
+	groups, eventNames, err := LoadExtraEventGroups(path, metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// event names are those in the file, before the uncore events are expanded
+	if !eventNames.Contains("UNC_CHA_TOR_INSERTS.IA_MISS_CRD") || eventNames.Cardinality() != 3 {
+		t.Errorf("unexpected event names: %v", eventNames)
+	}
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(groups))
+	}
+	if groups[2][0].Name != "UNC_CHA_TOR_INSERTS.IA_MISS_CRD.1" {
+		t.Errorf("unexpected uncore event name: %s", groups[2][0].Name)
+	}
+	// events that aren't supported on the platform are an error
+	path = filepath.Join(dir, "unsupported.txt")
+	events = "cpu-cycles,\n" +
+		"imc/event=0x04,umask=0x0f,name='UNC_M_CAS_COUNT.RD'/;\n"
+	if err := os.WriteFile(path, []byte(events), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = LoadExtraEventGroups(path, metadata); err == nil {
+		t.Error("expected error for unsupported event")
+	}
+}
+
+func TestSplitEventGroups(t *testing.T) {
+	groups := make([]GroupDefinition, 5)
+	batches := splitEventGroups(groups, 2)
+	if len(batches) != 3 || len(batches[0]) != 2 || len(batches[1]) != 2 || len(batches[2]) != 1 {
+		t.Fatalf("unexpected batches: %v", batches)
+	}
+	batches = splitEventGroups(groups, 5)
+	if len(batches) != 1 || len(batches[0]) != 5 {
+		t.Fatalf("unexpected batches: %v", batches)
+	}
+}
@@ -32,6 +32,7 @@ type EventFrame struct {
 	EventGroups []EventGroup
 	Timestamp   float64
 	Socket      string
+	Node        string
 	CPU         string
 	Cgroup      string
 }
@@ -50,6 +51,7 @@ type Event struct {
 	Value        float64 // parsed value
 	Group        int     // event group index
 	Socket       string  // only relevant if granularity is socket
+	Node         string  // only relevant if granularity is numa
 }
 
 // GetEventFrames organizes raw events received from perf into one or more frames (groups of events) that
@@ -61,7 +63,7 @@ type Event struct {
 // one process at a time.
 //
 // The frames produced will differ based on the intended metric granularity. Current options are
-// system, socket, cpu (thread/logical CPU), but only when in system scope. Process and cgroup scope
+// system, socket, numa (NUMA node), cpu (thread/logical CPU), but only when in system scope. Process and cgroup scope
 // only support system-level granularity.
 func GetEventFrames(rawEvents [][]byte, eventGroupDefinitions []GroupDefinition, scope Scope, granularity Granularity, metadata Metadata) (eventFrames []EventFrame, err error) {
 	// parse raw events into list of Event
@@ -69,6 +71,65 @@ func GetEventFrames(rawEvents [][]byte, eventGroupDefinitions []GroupDefinition,
 	if allEvents, err = parseEvents(rawEvents, eventGroupDefinitions); err != nil {
 		return
 	}
+	return getEventFramesFromEvents(allEvents, scope, granularity, metadata)
+}
+
+// GetEventFramesFromBatches organizes raw events received from sequential perf runs, one run per
+// batch of event groups, into frames as if the events were collected by a single perf run. The
+// batches' events are merged into frames with the provided timestamp. Each event value is
+// multiplied by scaleFactor to account for the portion of the frame's time that its batch was
+// counted.
+func GetEventFramesFromBatches(rawEventBatches [][][]byte, batchGroupDefinitions [][]GroupDefinition, scaleFactor float64, timestamp float64, scope Scope, granularity Granularity, metadata Metadata) (eventFrames []EventFrame, err error) {
+	if len(rawEventBatches) != len(batchGroupDefinitions) {
+		err = fmt.Errorf("received events for %d batches, expected %d", len(rawEventBatches), len(batchGroupDefinitions))
+		return
+	}
+	var allEvents []Event
+	groupOffset := 0
+	for batchIdx, rawEvents := range rawEventBatches {
+		var events []Event
+		if events, err = parseEvents(rawEvents, batchGroupDefinitions[batchIdx]); err != nil {
+			return
+		}
+		for i := range events {
+			events[i].Group += groupOffset
+			events[i].Interval = timestamp
+			events[i].Value *= scaleFactor
+		}
+		allEvents = append(allEvents, events...)
+		groupOffset += len(batchGroupDefinitions[batchIdx])
+	}
+	return getEventFramesFromEvents(allEvents, scope, granularity, metadata)
+}
+
+// UncountedEvent is an event that perf reported as <not supported> or <not counted>
+type UncountedEvent struct {
+	Group  int    // event group index
+	Event  string // event name
+	Status string // perf's counter value, e.g., <not supported>
+}
+
+// GetUncountedEvents returns the events, from a perf run that collected all of the event groups,
+// that perf could not count. Each event is listed once, in the order perf reported it.
+func GetUncountedEvents(rawEvents [][]byte, eventGroupDefinitions []GroupDefinition) (uncountedEvents []UncountedEvent, err error) {
+	var events []Event
+	if events, err = parseEvents(rawEvents, eventGroupDefinitions); err != nil {
+		return
+	}
+	for _, event := range events {
+		if event.CounterValue != "<not supported>" && event.CounterValue != "<not counted>" {
+			continue
+		}
+		uncountedEvent := UncountedEvent{Group: event.Group, Event: event.Event, Status: event.CounterValue}
+		if !slices.Contains(uncountedEvents, uncountedEvent) {
+			uncountedEvents = append(uncountedEvents, uncountedEvent)
+		}
+	}
+	return
+}
+
+// getEventFramesFromEvents creates the frames from the parsed events
+func getEventFramesFromEvents(allEvents []Event, scope Scope, granularity Granularity, metadata Metadata) (eventFrames []EventFrame, err error) {
 	// coalesce events to one or more lists based on scope and granularity
 	var coalescedEvents [][]Event
 	if coalescedEvents, err = coalesceEvents(allEvents, scope, granularity, metadata); err != nil {
@@ -88,6 +149,8 @@ func GetEventFrames(rawEvents [][]byte, eventGroupDefinitions []GroupDefinition,
 					eventFrame.CPU = event.CPU
 				} else if gCmdLineArgs.granularity == GranularitySocket {
 					eventFrame.Socket = event.Socket
+				} else if gCmdLineArgs.granularity == GranularityNUMA {
+					eventFrame.Node = event.Node
 				}
 				if gCmdLineArgs.scope == ScopeCgroup {
 					eventFrame.Cgroup = event.Cgroup
@@ -198,6 +261,48 @@ func coalesceEvents(allEvents []Event, scope Scope, granularity Granularity, met
 				newEvents[cpu] = append(newEvents[cpu], event)
 			}
 			coalescedEvents = append(coalescedEvents, newEvents...)
+		} else if granularity == GranularityNUMA {
+			// one list of Events per NUMA node that has CPUs, node numbers can have gaps and
+			// memory-only nodes, e.g., CXL, have no CPUs and therefore no events
+			var nodes []int
+			for _, node := range metadata.CPUNodeMap {
+				if !slices.Contains(nodes, node) {
+					nodes = append(nodes, node)
+				}
+			}
+			slices.Sort(nodes)
+			newEvents := make([][]Event, len(nodes))
+			for i := range nodes {
+				newEvents[i] = make([]Event, 0, len(allEvents)/len(nodes))
+			}
+			// CPUs in a node aren't necessarily contiguous, e.g., 0-31,128-159, so events are merged
+			// into the last event in the node's list when they share the same name and group
+			for _, event := range allEvents {
+				var cpu int
+				if cpu, err = strconv.Atoi(event.CPU); err != nil {
+					return
+				}
+				node, ok := metadata.CPUNodeMap[cpu]
+				if !ok {
+					err = fmt.Errorf("no NUMA node found for CPU %d", cpu)
+					return
+				}
+				nodeIdx := slices.Index(nodes, node)
+				lastIdx := len(newEvents[nodeIdx]) - 1
+				if lastIdx >= 0 && newEvents[nodeIdx][lastIdx].Event == event.Event && newEvents[nodeIdx][lastIdx].Group == event.Group {
+					newEvents[nodeIdx][lastIdx].Value += event.Value
+					continue
+				}
+				newEvent := event
+				newEvent.Node = fmt.Sprintf("%d", node)
+				newEvents[nodeIdx] = append(newEvents[nodeIdx], newEvent)
+			}
+			// e.g., the node's CPUs are offline
+			for _, nodeEvents := range newEvents {
+				if len(nodeEvents) > 0 {
+					coalescedEvents = append(coalescedEvents, nodeEvents)
+				}
+			}
 		} else {
 			err = fmt.Errorf("unsupported granularity: %d", granularity)
 			return
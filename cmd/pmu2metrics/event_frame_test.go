@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGetEventFramesFromBatches(t *testing.T) {
+	batchGroupDefinitions := [][]GroupDefinition{
+		{
+			{{Name: "cpu-cycles"}, {Name: "instructions"}},
+		},
+		{
+			{{Name: "cpu-cycles"}, {Name: "ref-cycles"}},
+			{{Name: "branch-misses"}},
+		},
+	}
+	rawEventBatches := [][][]byte{
+		{
+			[]byte(`{"counter-value" : "100.000000", "unit" : "", "event" : "cpu-cycles", "event-runtime" : 1000, "pcnt-running" : 100.00}`),
+			[]byte(`{"counter-value" : "200.000000", "unit" : "", "event" : "instructions", "event-runtime" : 1000, "pcnt-running" : 100.00}`),
+		},
+		{
+			[]byte(`{"counter-value" : "110.000000", "unit" : "", "event" : "cpu-cycles", "event-runtime" : 1000, "pcnt-running" : 100.00}`),
+			[]byte(`{"counter-value" : "50.000000", "unit" : "", "event" : "ref-cycles", "event-runtime" : 1000, "pcnt-running" : 100.00}`),
+			[]byte(`{"counter-value" : "7.000000", "unit" : "", "event" : "branch-misses", "event-runtime" : 1000, "pcnt-running" : 100.00}`),
+		},
+	}
+	frames, err := GetEventFramesFromBatches(rawEventBatches, batchGroupDefinitions, 2, 10.5, ScopeSystem, GranularitySystem, Metadata{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 1 {
+		t.Fatalf("expected 1 frame, got %d", len(frames))
+	}
+	frame := frames[0]
+	if frame.Timestamp != 10.5 {
+		t.Errorf("unexpected timestamp: %f", frame.Timestamp)
+	}
+	if len(frame.EventGroups) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(frame.EventGroups))
+	}
+	for i, group := range frame.EventGroups {
+		if group.GroupID != i {
+			t.Errorf("group %d has ID %d", i, group.GroupID)
+		}
+	}
+	if frame.EventGroups[0].EventValues["instructions"] != 400 {
+		t.Errorf("unexpected instructions: %f", frame.EventGroups[0].EventValues["instructions"])
+	}
+	if frame.EventGroups[1].EventValues["cpu-cycles"] != 220 {
+		t.Errorf("unexpected cpu-cycles: %f", frame.EventGroups[1].EventValues["cpu-cycles"])
+	}
+	if frame.EventGroups[2].EventValues["branch-misses"] != 14 {
+		t.Errorf("unexpected branch-misses: %f", frame.EventGroups[2].EventValues["branch-misses"])
+	}
+}
+
+func TestGetUncountedEvents(t *testing.T) {
+	groupDefinitions := []GroupDefinition{
+		{{Name: "cpu-cycles"}, {Name: "instructions"}},
+		{{Name: "ref-cycles"}, {Name: "branch-misses"}},
+	}
+	rawEvents := [][]byte{
+		[]byte(`{"counter-value" : "100.000000", "unit" : "", "event" : "cpu-cycles", "event-runtime" : 1000, "pcnt-running" : 100.00}`),
+		[]byte(`{"counter-value" : "200.000000", "unit" : "", "event" : "instructions", "event-runtime" : 1000, "pcnt-running" : 100.00}`),
+		[]byte(`{"counter-value" : "<not supported>", "unit" : "", "event" : "ref-cycles", "event-runtime" : 0, "pcnt-running" : 100.00}`),
+		[]byte(`{"counter-value" : "<not counted>", "unit" : "", "event" : "branch-misses", "event-runtime" : 0, "pcnt-running" : 0.00}`),
+	}
+	uncountedEvents, err := GetUncountedEvents(rawEvents, groupDefinitions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []UncountedEvent{
+		{Group: 1, Event: "ref-cycles", Status: "<not supported>"},
+		{Group: 1, Event: "branch-misses", Status: "<not counted>"},
+	}
+	if len(uncountedEvents) != len(expected) {
+		t.Fatalf("expected %d uncounted events, got %d", len(expected), len(uncountedEvents))
+	}
+	for i := range expected {
+		if uncountedEvents[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], uncountedEvents[i])
+		}
+	}
+}
+
+func TestCoalesceEventsNUMA(t *testing.T) {
+	// nodes 0 and 2 have CPUs, node 1 (e.g., HBM) and node 3 (e.g., CXL) are memory-only
+	metadata := Metadata{
+		CPUNodeMap:    map[int]int{0: 0, 1: 2, 2: 0, 3: 2},
+		NUMANodeCount: 2,
+	}
+	var allEvents []Event
+	for cpu := 0; cpu < 4; cpu++ {
+		allEvents = append(allEvents,
+			Event{CPU: fmt.Sprintf("%d", cpu), Event: "cpu-cycles", Group: 0, Value: 100},
+			Event{CPU: fmt.Sprintf("%d", cpu), Event: "instructions", Group: 0, Value: float64(cpu)},
+		)
+	}
+	coalescedEvents, err := coalesceEvents(allEvents, ScopeSystem, GranularityNUMA, metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coalescedEvents) != 2 {
+		t.Fatalf("expected 2 nodes, got %d: %v", len(coalescedEvents), coalescedEvents)
+	}
+	for i, expected := range []struct {
+		node         string
+		cycles       float64
+		instructions float64
+	}{
+		{"0", 200, 2},
+		{"2", 200, 4},
+	} {
+		values := make(map[string]float64)
+		for _, event := range coalescedEvents[i] {
+			if event.Node != expected.node {
+				t.Errorf("node %s: unexpected event node %s", expected.node, event.Node)
+			}
+			values[event.Event] += event.Value
+		}
+		if values["cpu-cycles"] != expected.cycles || values["instructions"] != expected.instructions {
+			t.Errorf("node %s: unexpected values %v", expected.node, values)
+		}
+	}
+	// CPUs that aren't in any node
+	if _, err := coalesceEvents([]Event{{CPU: "4", Event: "cpu-cycles"}}, ScopeSystem, GranularityNUMA, metadata); err == nil {
+		t.Error("expected an error for a CPU that isn't in a NUMA node")
+	}
+}
@@ -9,21 +9,26 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"embed"
 	"encoding/binary"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"log/syslog"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/intel/svr-info/internal/util"
 )
 
@@ -32,6 +37,9 @@ var (
 	gVersion             string = "dev"
 	gCmdLineArgs         CmdLineArgs
 	gCollectionStartTime time.Time
+	gMetricOutput        io.Writer = os.Stdout
+	// highlight metric values in human and wide output, see --thresholds
+	gMetricThresholds MetricThresholds
 )
 
 // Granularity represents the requested granularity level for produced metrics
@@ -41,9 +49,10 @@ const (
 	GranularitySystem Granularity = iota
 	GranularitySocket
 	GranularityCPU
+	GranularityNUMA
 )
 
-var GranularityOptions = []string{"system", "socket", "cpu"}
+var GranularityOptions = []string{"system", "socket", "cpu", "numa"}
 
 // Scope represents the requested scope of event collection
 type Scope int
@@ -84,18 +93,21 @@ type CmdLineArgs struct {
 	// collection options
 	timeout int // seconds
 	// collection options
-	scope   Scope
-	pidList string
-	cidList string
-	filter  string
-	count   int
-	refresh int // seconds
+	scope      Scope
+	pidList    string
+	cidList    string
+	containers string
+	filter     string
+	count      int
+	refresh    int // seconds
+	stickyPIDs bool
 	// post-processing options
 	inputCSVFilePath string
 	summaryFormat    Summary
 	// output format options
 	granularity  Granularity
 	metricsList  string
+	metricsRegex string
 	outputFormat Format
 	verbose      bool
 	veryVerbose  bool
@@ -103,10 +115,21 @@ type CmdLineArgs struct {
 	showMetricNames   bool
 	syslog            bool
 	eventFilePath     string
+	extraEventPath    string
 	metricFilePath    string
 	perfPrintInterval int // milliseconds
 	perfMuxInterval   int // milliseconds
 	rawFilePath       string
+	noWatchdogChange  bool
+	perfAffinity      string
+	perfPath          string
+	prometheusAddr    string
+	maxGroups         int
+	outputFilePath    string
+	rawEvents         bool
+	thresholdsPath    string
+	eventsOnly        bool
+	localTime         bool
 	// debugging options
 	metadataFilePath string
 	perfStatFilePath string
@@ -153,45 +176,72 @@ func resourceExists(filename string) (exists bool) {
 	return
 }
 
-// printMetrics prints one frame of metrics to stdout in the format requested by the user. The
+// getFrameTime returns the time when the frame's metrics were captured, in the local time zone
+// when --localtime is provided, otherwise in UTC
+func getFrameTime(metricFrame MetricFrame) time.Time {
+	frameTime := gCollectionStartTime.Add(time.Second * time.Duration(int(metricFrame.Timestamp)))
+	if gCmdLineArgs.localTime {
+		return frameTime.Local()
+	}
+	return frameTime.UTC()
+}
+
+// printMetrics prints one frame of metrics to stdout, or the --output-file, in the format requested by the user. The
 // frameCount argument is used to control when the headers are printed, e.g., on the first frame
 // only.
 func printMetrics(metricFrame MetricFrame, frameCount int) {
 	if gCmdLineArgs.outputFormat == FormatCSV {
 		if frameCount == 1 {
-			fmt.Print("TS,SKT,CPU,PID,CMD,CID,")
-			names := make([]string, 0, len(metricFrame.Metrics))
+			fmt.Fprint(gMetricOutput, "TS,")
+			if gCmdLineArgs.localTime {
+				fmt.Fprintf(gMetricOutput, "%s,", localTimeColumn)
+			}
+			fmt.Fprint(gMetricOutput, "SKT,NODE,CPU,PID,CMD,CID,")
+			names := make([]string, 0, len(metricFrame.Metrics)+len(metricFrame.Events))
 			for _, metric := range metricFrame.Metrics {
 				names = append(names, metric.Name)
 			}
-			fmt.Printf("%s\n", strings.Join(names, ","))
+			for _, event := range metricFrame.Events {
+				names = append(names, event.Name)
+			}
+			fmt.Fprintf(gMetricOutput, "%s\n", strings.Join(names, ","))
+		}
+		fmt.Fprintf(gMetricOutput, "%d,", gCollectionStartTime.Unix()+int64(metricFrame.Timestamp))
+		if gCmdLineArgs.localTime {
+			fmt.Fprintf(gMetricOutput, "%s,", getFrameTime(metricFrame).Format(time.RFC3339))
 		}
-		fmt.Printf("%d,%s,%s,%s,%s,%s,", gCollectionStartTime.Unix()+int64(metricFrame.Timestamp), metricFrame.Socket, metricFrame.CPU, metricFrame.PID, metricFrame.Cmd, metricFrame.Cgroup)
-		values := make([]string, 0, len(metricFrame.Metrics))
+		fmt.Fprintf(gMetricOutput, "%s,%s,%s,%s,%s,%s,", metricFrame.Socket, metricFrame.Node, metricFrame.CPU, metricFrame.PID, metricFrame.Cmd, metricFrame.Cgroup)
+		values := make([]string, 0, len(metricFrame.Metrics)+len(metricFrame.Events))
 		for _, metric := range metricFrame.Metrics {
 			values = append(values, strconv.FormatFloat(metric.Value, 'g', 8, 64))
 		}
-		fmt.Printf("%s\n", strings.ReplaceAll(strings.Join(values, ","), "NaN", ""))
+		for _, event := range metricFrame.Events {
+			values = append(values, strconv.FormatFloat(event.Value, 'f', -1, 64))
+		}
+		fmt.Fprintf(gMetricOutput, "%s\n", strings.ReplaceAll(strings.Join(values, ","), "NaN", ""))
 	} else {
 		if gCmdLineArgs.outputFormat == FormatHuman {
-			fmt.Println("--------------------------------------------------------------------------------------")
-			fmt.Printf("- Metrics captured at %s\n", gCollectionStartTime.Add(time.Second*time.Duration(int(metricFrame.Timestamp))).UTC())
+			fmt.Fprintln(gMetricOutput, "--------------------------------------------------------------------------------------")
+			fmt.Fprintf(gMetricOutput, "- Metrics captured at %s\n", getFrameTime(metricFrame))
 			if metricFrame.PID != "" {
-				fmt.Printf("- PID: %s\n", metricFrame.PID)
-				fmt.Printf("- CMD: %s\n", metricFrame.Cmd)
+				fmt.Fprintf(gMetricOutput, "- PID: %s\n", metricFrame.PID)
+				fmt.Fprintf(gMetricOutput, "- CMD: %s\n", metricFrame.Cmd)
 			} else if metricFrame.Cgroup != "" {
-				fmt.Printf("- CID: %s\n", metricFrame.Cgroup)
+				fmt.Fprintf(gMetricOutput, "- CID: %s\n", metricFrame.Cgroup)
 			}
 			if metricFrame.CPU != "" {
-				fmt.Printf("- CPU: %s\n", metricFrame.CPU)
+				fmt.Fprintf(gMetricOutput, "- CPU: %s\n", metricFrame.CPU)
+			} else if metricFrame.Node != "" {
+				fmt.Fprintf(gMetricOutput, "- NUMA Node: %s\n", metricFrame.Node)
 			} else if metricFrame.Socket != "" {
-				fmt.Printf("- Socket: %s\n", metricFrame.Socket)
+				fmt.Fprintf(gMetricOutput, "- Socket: %s\n", metricFrame.Socket)
 			}
-			fmt.Println("--------------------------------------------------------------------------------------")
-			fmt.Printf("%-70s %15s\n", "metric", "value")
-			fmt.Printf("%-70s %15s\n", "------------------------", "----------")
+			fmt.Fprintln(gMetricOutput, "--------------------------------------------------------------------------------------")
+			fmt.Fprintf(gMetricOutput, "%-70s %15s\n", "metric", "value")
+			fmt.Fprintf(gMetricOutput, "%-70s %15s\n", "------------------------", "----------")
 			for _, metric := range metricFrame.Metrics {
-				fmt.Printf("%-70s %15s\n", metric.Name, strconv.FormatFloat(metric.Value, 'g', 4, 64))
+				formattedVal := fmt.Sprintf("%15s", strconv.FormatFloat(metric.Value, 'g', 4, 64))
+				fmt.Fprintf(gMetricOutput, "%-70s %s\n", metric.Name, colorizeLevel(formattedVal, gMetricThresholds.Level(metric.Name, metric.Value)))
 			}
 		} else { // wide format
 			var names []string
@@ -212,6 +262,8 @@ func printMetrics(metricFrame MetricFrame, frameCount int) {
 				}
 				if metricFrame.CPU != "" {
 					header += "CPU   " // 3 + 3
+				} else if metricFrame.Node != "" {
+					header += "NODE   " // 4 + 3
 				} else if metricFrame.Socket != "" {
 					header += "SKT   " // 3 + 3
 				}
@@ -222,7 +274,7 @@ func printMetrics(metricFrame MetricFrame, frameCount int) {
 					}
 					header += fmt.Sprintf("%s%*s%*s", name, extend, "", colSpacing, "")
 				}
-				fmt.Println(header)
+				fmt.Fprintln(gMetricOutput, header)
 			}
 			// handle values
 			TimestampColWidth := 10
@@ -246,6 +298,9 @@ func printMetrics(metricFrame MetricFrame, frameCount int) {
 			if metricFrame.CPU != "" {
 				CPUColWidth := 3
 				row += fmt.Sprintf("%s%*s%*s", metricFrame.CPU, CPUColWidth-len(metricFrame.CPU), "", colSpacing, "")
+			} else if metricFrame.Node != "" {
+				NodeColWidth := 4
+				row += fmt.Sprintf("%s%*s%*s", metricFrame.Node, NodeColWidth-len(metricFrame.Node), "", colSpacing, "")
 			} else if metricFrame.Socket != "" {
 				SKTColWidth := 3
 				row += fmt.Sprintf("%s%*s%*s", metricFrame.Socket, SKTColWidth-len(metricFrame.Socket), "", colSpacing, "")
@@ -254,19 +309,31 @@ func printMetrics(metricFrame MetricFrame, frameCount int) {
 			for i, value := range values {
 				colWidth := max(len(names[i]), minColWidth)
 				formattedVal := fmt.Sprintf("%.2f", value)
-				row += fmt.Sprintf("%s%*s%*s", formattedVal, colWidth-len(formattedVal), "", colSpacing, "")
+				row += fmt.Sprintf("%s%*s%*s", colorizeLevel(formattedVal, gMetricThresholds.Level(names[i], value)), colWidth-len(formattedVal), "", colSpacing, "")
 			}
-			fmt.Println(row)
+			fmt.Fprintln(gMetricOutput, row)
 		}
 	}
 }
 
+// createOutputFile creates, or truncates, the file that metrics will be written to, creating
+// its parent directories if they don't exist
+func createOutputFile(path string) (file *os.File, err error) {
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	file, err = os.Create(path)
+	return
+}
+
 // getPerfPath returns the path to the perf executable that will be used to collect
-// events. If the perf binary is included in the embedded resources, it will be extracted
-// to a temporary directory and run from there, otherwise the system-installed perf will
-// be used.
+// events. The perf binary provided with --perf-path takes precedence. Otherwise, if the
+// perf binary is included in the embedded resources, it will be extracted to a temporary
+// directory and run from there, otherwise the system-installed perf will be used.
 func getPerfPath() (path string, tempDir string, err error) {
-	if resourceExists("perf") {
+	if gCmdLineArgs.perfPath != "" {
+		path = gCmdLineArgs.perfPath
+	} else if resourceExists("perf") {
 		if tempDir, err = os.MkdirTemp("", fmt.Sprintf("%s.tmp.", filepath.Base(os.Args[0]))); err != nil {
 			log.Printf("failed to create temporary directory: %v", err)
 			return
@@ -289,7 +356,7 @@ func getPerfCommandArgs(pid string, cgroups []string, timeout int, eventGroups [
 	args = append(args, "stat", "-I", fmt.Sprintf("%d", gCmdLineArgs.perfPrintInterval), "-j")
 	if gCmdLineArgs.scope == ScopeSystem {
 		args = append(args, "-a") // system-wide collection
-		if gCmdLineArgs.granularity == GranularityCPU || gCmdLineArgs.granularity == GranularitySocket {
+		if gCmdLineArgs.granularity == GranularityCPU || gCmdLineArgs.granularity == GranularitySocket || gCmdLineArgs.granularity == GranularityNUMA {
 			args = append(args, "-A") // no aggregation
 		}
 	} else if gCmdLineArgs.scope == ScopeProcess {
@@ -298,7 +365,16 @@ func getPerfCommandArgs(pid string, cgroups []string, timeout int, eventGroups [
 		args = append(args, "--for-each-cgroup", strings.Join(cgroups, ",")) // collect only for these cgroups
 	}
 	// -i: event groups to collect
-	args = append(args, "-e")
+	args = append(args, "-e", getPerfEventsArg(eventGroups))
+	// add timeout, if applicable
+	if gCmdLineArgs.scope != ScopeCgroup && timeout != 0 {
+		args = append(args, "sleep", fmt.Sprintf("%d", timeout))
+	}
+	return
+}
+
+// getPerfEventsArg formats the event groups for perf's -e option
+func getPerfEventsArg(eventGroups []GroupDefinition) string {
 	var groups []string
 	for _, group := range eventGroups {
 		var events []string
@@ -307,24 +383,64 @@ func getPerfCommandArgs(pid string, cgroups []string, timeout int, eventGroups [
 		}
 		groups = append(groups, fmt.Sprintf("{%s}", strings.Join(events, ",")))
 	}
-	args = append(args, fmt.Sprintf("'%s'", strings.Join(groups, ",")))
-	// add timeout, if applicable
-	if gCmdLineArgs.scope != ScopeCgroup && timeout != 0 {
-		args = append(args, "sleep", fmt.Sprintf("%d", timeout))
+	return fmt.Sprintf("'%s'", strings.Join(groups, ","))
+}
+
+// getPerfBatchCommandArgs assembles the arguments for a perf run that counts one batch of
+// event groups, system-wide, for the given number of seconds. Perf prints the counts once,
+// when the run ends.
+func getPerfBatchCommandArgs(eventGroups []GroupDefinition, seconds float64) (args []string) {
+	args = append(args, "stat", "-j", "-a")
+	if gCmdLineArgs.granularity == GranularityCPU || gCmdLineArgs.granularity == GranularitySocket || gCmdLineArgs.granularity == GranularityNUMA {
+		args = append(args, "-A") // no aggregation
+	}
+	args = append(args, "-e", getPerfEventsArg(eventGroups))
+	args = append(args, "sleep", fmt.Sprintf("%.3f", seconds))
+	return
+}
+
+// newPerfCommand creates the command that runs perf with the given arguments. When
+// --perf-affinity is specified, perf is run by taskset so that perf itself is pinned to
+// the specified CPUs.
+func newPerfCommand(perfPath string, args []string) (cmd *exec.Cmd) {
+	if gCmdLineArgs.perfAffinity != "" {
+		cmd = exec.Command("taskset", append([]string{"-c", gCmdLineArgs.perfAffinity, perfPath}, args...)...)
+		return
+	}
+	cmd = exec.Command(perfPath, args...)
+	return
+}
+
+// validatePerfAffinity confirms that the CPUs specified with --perf-affinity exist on the platform
+func validatePerfAffinity(metadata Metadata) (err error) {
+	var cpus []int
+	if cpus, err = util.ParseCPUList(gCmdLineArgs.perfAffinity); err != nil {
+		return
+	}
+	numCPUs := metadata.SocketCount * metadata.CoresPerSocket * metadata.ThreadsPerCore
+	for _, cpu := range cpus {
+		if cpu < 0 || cpu >= numCPUs {
+			err = fmt.Errorf("CPU %d not found, platform has %d CPUs", cpu, numCPUs)
+			return
+		}
+	}
+	if _, err = exec.LookPath("taskset"); err != nil {
+		err = fmt.Errorf("taskset is required: %v", err)
 	}
 	return
 }
 
 // getPerfCommands is responsible for assembling the command(s) that will be
-// executed to collect event data
-func getPerfCommands(perfPath string, eventGroups []GroupDefinition) (processes []Process, perfCommands []*exec.Cmd, err error) {
+// executed to collect event data. The previously monitored processes are kept in
+// the refreshed list of hot processes when --sticky-pids is set.
+func getPerfCommands(perfPath string, eventGroups []GroupDefinition, previousProcesses []Process) (processes []Process, perfCommands []*exec.Cmd, err error) {
 	if gCmdLineArgs.scope == ScopeSystem {
 		var args []string
 		if args, err = getPerfCommandArgs("", []string{}, gCmdLineArgs.timeout, eventGroups); err != nil {
 			err = fmt.Errorf("failed to assemble perf args: %v", err)
 			return
 		}
-		cmd := exec.Command(perfPath, args...)
+		cmd := newPerfCommand(perfPath, args)
 		perfCommands = append(perfCommands, cmd)
 	} else if gCmdLineArgs.scope == ScopeProcess {
 		if gCmdLineArgs.pidList != "" {
@@ -335,6 +451,9 @@ func getPerfCommands(perfPath string, eventGroups []GroupDefinition) (processes
 			if processes, err = GetHotProcesses(gCmdLineArgs.count, gCmdLineArgs.filter); err != nil {
 				return
 			}
+			if gCmdLineArgs.stickyPIDs {
+				processes = MergeStickyProcesses(previousProcesses, processes, gCmdLineArgs.count)
+			}
 		}
 		if len(processes) == 0 {
 			err = fmt.Errorf("no PIDs selected")
@@ -352,7 +471,7 @@ func getPerfCommands(perfPath string, eventGroups []GroupDefinition) (processes
 				err = fmt.Errorf("failed to assemble perf args: %v", err)
 				return
 			}
-			cmd := exec.Command(perfPath, args...)
+			cmd := newPerfCommand(perfPath, args)
 			perfCommands = append(perfCommands, cmd)
 		}
 	} else if gCmdLineArgs.scope == ScopeCgroup {
@@ -375,7 +494,7 @@ func getPerfCommands(perfPath string, eventGroups []GroupDefinition) (processes
 			err = fmt.Errorf("failed to assemble perf args: %v", err)
 			return
 		}
-		cmd := exec.Command(perfPath, args...)
+		cmd := newPerfCommand(perfPath, args)
 		perfCommands = append(perfCommands, cmd)
 	}
 	return
@@ -475,12 +594,112 @@ func runPerf(process Process, cmd *exec.Cmd, eventGroupDefinitions []GroupDefini
 	}
 }
 
-// receiveMetrics prints metrics that it receives over the provided channel
-func receiveMetrics(frameChannel chan MetricFrame) {
+// runPerfBatch runs perf to count one batch of event groups for the given number of seconds
+// and returns perf's output
+func runPerfBatch(perfPath string, eventGroups []GroupDefinition, seconds float64) (outputLines [][]byte, err error) {
+	cmd := newPerfCommand(perfPath, getPerfBatchCommandArgs(eventGroups, seconds))
+	if gCmdLineArgs.veryVerbose {
+		log.Printf("perf command: %s", cmd)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		if !strings.Contains(err.Error(), "signal") { // perf received kill signal, caller will stop
+			err = fmt.Errorf("error from perf on exit: %v, %s", err, stderr.String())
+		}
+		return
+	}
+	for _, line := range strings.Split(stderr.String(), "\n") {
+		if gCmdLineArgs.veryVerbose {
+			log.Print(line)
+		}
+		if strings.TrimSpace(line) != "" {
+			outputLines = append(outputLines, []byte(line))
+		}
+	}
+	return
+}
+
+// runPerfBatches collects the event groups, when there are more than --max-groups, by running
+// perf sequentially, once per batch of event groups, for one --interval each. The batches'
+// events are merged into one frame of metrics per round of perf runs.
+func runPerfBatches(perfPath string, eventGroupDefinitions []GroupDefinition, metricDefinitions []MetricDefinition, metadata Metadata, frameChannel chan MetricFrame) (err error) {
+	batches := splitEventGroups(eventGroupDefinitions, gCmdLineArgs.maxGroups)
+	batchSeconds := float64(gCmdLineArgs.perfPrintInterval) / 1000
+	log.Printf("Collecting %d event groups in %d sequential perf runs of %d milliseconds each", len(eventGroupDefinitions), len(batches), gCmdLineArgs.perfPrintInterval)
+	gCollectionStartTime = time.Now()
+	var frameTimestamp float64
+	frameCount := 0
+	for {
+		perfEventBatches := make([][][]byte, 0, len(batches))
+		for _, batch := range batches {
+			var outputLines [][]byte
+			if outputLines, err = runPerfBatch(perfPath, batch, batchSeconds); err != nil {
+				if strings.Contains(err.Error(), "signal") { // interrupted, stop collecting
+					err = nil
+				} else {
+					log.Printf("%v", err)
+				}
+				return
+			}
+			perfEventBatches = append(perfEventBatches, outputLines)
+		}
+		// each batch was counted for batchSeconds of the time since the previous frame
+		timestamp := time.Since(gCollectionStartTime).Seconds()
+		scaleFactor := (timestamp - frameTimestamp) / batchSeconds
+		var metricFrames []MetricFrame
+		if metricFrames, frameTimestamp, err = ProcessEventBatches(perfEventBatches, batches, scaleFactor, timestamp, metricDefinitions, frameTimestamp, metadata); err != nil {
+			log.Printf("%v", err)
+			return
+		}
+		for _, metricFrame := range metricFrames {
+			frameCount += 1
+			metricFrame.FrameCount = frameCount
+			frameChannel <- metricFrame
+		}
+		if gCmdLineArgs.timeout != 0 && int(timestamp) >= gCmdLineArgs.timeout {
+			return
+		}
+	}
+}
+
+// checkEvents runs perf once, for one second, with all of the event groups to find the events
+// that perf can't count on this platform
+func checkEvents(perfPath string, eventGroupDefinitions []GroupDefinition) (uncountedEvents []UncountedEvent, err error) {
+	var outputLines [][]byte
+	if outputLines, err = runPerfBatch(perfPath, eventGroupDefinitions, 1); err != nil {
+		return
+	}
+	uncountedEvents, err = GetUncountedEvents(outputLines, eventGroupDefinitions)
+	return
+}
+
+// printUncountedEvents prints the result of checkEvents
+func printUncountedEvents(uncountedEvents []UncountedEvent, eventGroupDefinitions []GroupDefinition) {
+	eventCount := 0
+	for _, group := range eventGroupDefinitions {
+		eventCount += len(group)
+	}
+	if len(uncountedEvents) == 0 {
+		fmt.Printf("All %d events in %d event groups are supported on this platform.\n", eventCount, len(eventGroupDefinitions))
+		return
+	}
+	fmt.Printf("%d of %d events in %d event groups can't be counted on this platform:\n", len(uncountedEvents), eventCount, len(eventGroupDefinitions))
+	for _, event := range uncountedEvents {
+		fmt.Printf("  group %d: %s %s\n", event.Group, event.Event, event.Status)
+	}
+}
+
+// receiveMetrics prints metrics that it receives over the provided channel and, if
+// provided, stores them in the cache served to Prometheus
+func receiveMetrics(frameChannel chan MetricFrame, prometheusCache *PrometheusCache) {
 	totalFrameCount := 0
 	// block until next frame of metrics arrives, will exit loop when channel is closed
 	for frame := range frameChannel {
 		totalFrameCount++
+		if prometheusCache != nil {
+			prometheusCache.Update(frame)
+		}
 		printMetrics(frame, totalFrameCount)
 	}
 }
@@ -494,14 +713,27 @@ func doWork(perfPath string, eventGroupDefinitions []GroupDefinition, metricDefi
 	errorChannel := make(chan error)
 	frameChannel := make(chan MetricFrame)
 	totalRuntimeSeconds := 0 // only relevant in process scope
-	go receiveMetrics(frameChannel)
+	var prometheusCache *PrometheusCache
+	if gCmdLineArgs.prometheusAddr != "" {
+		prometheusCache = NewPrometheusCache()
+		if err = StartPrometheusServer(gCmdLineArgs.prometheusAddr, prometheusCache); err != nil {
+			err = fmt.Errorf("failed to start prometheus server: %v", err)
+			return
+		}
+	}
+	go receiveMetrics(frameChannel, prometheusCache)
+	if gCmdLineArgs.maxGroups > 0 && len(eventGroupDefinitions) > gCmdLineArgs.maxGroups {
+		err = runPerfBatches(perfPath, eventGroupDefinitions, metricDefinitions, metadata, frameChannel)
+		close(frameChannel) // trigger receiveMetrics to end
+		return
+	}
+	var processes []Process
 	for {
 		// get current time for use in setting timestamps on output
 		gCollectionStartTime = time.Now()
 		var perfCommands []*exec.Cmd
-		var processes []Process
 		// One perf command when in system or cgroup scope and one or more perf commands when in process scope.
-		if processes, perfCommands, err = getPerfCommands(perfPath, eventGroupDefinitions); err != nil {
+		if processes, perfCommands, err = getPerfCommands(perfPath, eventGroupDefinitions, processes); err != nil {
 			break
 		}
 		beginTimestamp := time.Now()
@@ -623,7 +855,7 @@ func showUsage() {
 	fmt.Printf("Usage:  sudo %s [OPTIONS]\n", filepath.Base(os.Args[0]))
 	fmt.Println()
 	fmt.Println("Prints system metrics at 5 second intervals until interrupted by user.")
-	fmt.Println("Note: Metrics are printed to stdout. Log messages are printed to stderr or, optionally, sent to syslog.")
+	fmt.Println("Note: Metrics are printed to stdout or, optionally, written to a file. Log messages are printed to stderr or, optionally, sent to syslog.")
 	fmt.Println()
 	args := `Options
   -h, --help
@@ -640,24 +872,38 @@ Collection Options
         Comma separated list of process ids. Only valid when collecting in process scope. If not provided while collecting at process scope, the currently most active processes will be monitored (default: None).
   -c, --cid <cids>
         Comma separated list of cids. Only valid when collecting at cgroup scope. If not provided while collecting at cgroup scope, the currently most active cgroups will be monitored (default: None).
+  --container-name <names>
+        Comma separated list of Docker or containerd container names. The names are resolved to cids using the docker or crictl command. Only valid when collecting at cgroup scope. Not valid with --cid (default: None).
   -F, --filter <regex>
         Regular expression used to match process names or cgroup IDs when --pid or --cid are not specified (default: None).
   -n, --count <count>
         The maximum number of processes or cgroups to monitor (default: 5).
   -r, --refresh <seconds>
         The number of seconds to run before refreshing the "hot" process or cgroup list (default: 30).
+  --sticky-pids
+        Keep monitoring the processes from the previous "hot" process list, while they are running, when the list is refreshed. Only the remaining slots, up to --count, are filled with the currently most active processes. Only valid when collecting at process scope without --pid (default: False).
 
 Output Options
   -g, --granularity <option>
         Specify the level of metric granularity. Only valid when collecting at system scope. Options: %[2]s (default: system).
   -o, --output <option>
         Specify the output format. Options: %[3]s. 'csv' is required for post-processing (default: human).
+  --prometheus <address>
+        Serve the most recent metric values in Prometheus text format at http://<address>/metrics, e.g., --prometheus :9100. Metrics are also written to the selected output (default: None).
+  --output-file <path>
+        Write metrics to this file instead of stdout. Parent directories are created as needed. An existing file is overwritten (default: None).
+  --thresholds <path>
+        Path to a YAML file that maps metric names to warn and/or crit levels, e.g., 'CPU utilization %%: {warn: 80, crit: 95}'. Metric values that exceed a level are shown in yellow (warn) or red (crit). Only valid when --output is human or wide (default: None).
+  --raw-events
+        Add a column for each event's value, in each interval, after the metric columns. Events are named by their group, e.g., g2:instructions. Useful for debugging metric formulas. Only valid when --output is csv (default: False).
+  --localtime
+        Show the time when metrics are captured in the local time zone instead of UTC. In csv output, a LOCALTIME column, in ISO 8601 format, is added after the TS (Unix epoch) column. Post-processing ignores the LOCALTIME column (default: False).
   -[v]v, --[very]verbose
         Enable verbose, or very verbose (-vv) logging (Default: False).
 
 Post-processing Options
-  -P, --post-process <CSV file>
-        Path to a CSV file created during collection. Outputs a report containing summarized metric values (default: None).
+  -P, --post-process <CSV file(s)>
+        Path to a CSV file created during collection, or a comma-separated list of CSV files and/or directories containing CSV files. Data from multiple files is combined in timestamp order. Outputs a report containing summarized metric values (default: None).
   -f, --format <option>
         File format to generate when post-processing the collected CSV file. Options: %[4]s. The 'html' format is supported only when data's scope and granularity is 'system' (default: csv).
 
@@ -668,16 +914,30 @@ Advanced Options
         Show metric names available on this platform and exit (default: False).
   -m, --metrics <metric names>
         A quoted and comma separated list of metric names to include in output. Use --list to view metric names. (default: all metrics).
+  --metrics-regex <pattern>
+        A regular expression that selects the metrics to include in output by name, e.g., "TMA_.*Bound". Not valid with --metrics. When used with --list, the matching metrics are marked with '*' (default: all metrics).
   -e, --eventfile <path>
         Path to perf event definition file (default: None).
+  --extra-events <path>
+        Path to a perf event definition file whose event groups are added to the event groups loaded by default or from --eventfile. Metrics that use these events can be defined in the --metricfile (default: None).
   -M, --metricfile <path>
-        Path to metric definition file (default: None).
+        Path to metric definition file. Each event referenced by a metric must be defined in the event definition file(s), otherwise collection does not start (default: None).
   -i, --interval <milliseconds>
         Event collection interval in milliseconds (default: 5000).
   -x, --muxinterval <milliseconds>
         Multiplexing interval in milliseconds (default: 125).
   -R, --raw <output file name>
         Write metadata and raw perf event data to this file (default: None).
+  --no-watchdog-change
+        Do not disable the NMI watchdog during collection. The NMI watchdog uses a performance counter, so one fewer counter is available for collecting events (default: False).
+  --perf-affinity <cpulist>
+        Run perf only on the CPUs in this list, e.g., 0-1,8, to limit perf's own impact on the remaining CPUs. Events are still counted on all CPUs, so output at --granularity cpu still includes every CPU (default: None).
+  --perf-path <path>
+        Path to the perf executable to use, e.g., a perf built for the running kernel, instead of the embedded or system-installed perf (default: None).
+  --max-groups <N>
+        Maximum number of event groups to collect in one perf run. When more groups are needed, perf is run repeatedly, for one --interval per run, to collect the groups N at a time, and the runs are merged into one set of metrics. This reduces multiplexing error on platforms with few counters, but each set of metrics takes longer to collect (one --interval per run) and metrics that combine events from different runs are calculated from different time periods. Only valid when --scope is system (default: 0, no limit).
+  --events-only
+        Run perf once, for one second, with all of the event groups, then report the events that perf reports as <not supported> or <not counted> and exit. The exit status is 3 when any event can't be counted. Use to confirm that the event groups match this platform's microarchitecture before a long collection. Only valid when --scope is system (default: False).
 `
 	fmt.Printf(args, strings.Join(ScopeOptions, ", "), strings.Join(GranularityOptions, ", "), strings.Join(FormatOptions, ", "), strings.Join(SummaryOptions, ", "))
 	fmt.Println()
@@ -686,14 +946,34 @@ Advanced Options
     $ sudo %[1]s
   Metrics to screen and file in CSV format.
     $ sudo %[1]s --output csv | tee %[1]s.csv
+  Metrics to file in CSV format, with logs sent to the System Log daemon.
+    $ sudo %[1]s --output csv --output-file ./metrics/%[1]s.csv --syslog
   Metrics with socket-level granularity to screen in CSV format for 60 seconds.
     $ sudo %[1]s --output csv --granularity socket --timeout 60
+  Metrics with NUMA node-level granularity to screen in wide format.
+    $ sudo %[1]s --output wide --granularity numa
   Metrics for "hot" processes to screen in CSV format.
     $ sudo %[1]s --output csv --scope process
   Metrics for specified process PIDs to screen in CSV format.
     $ sudo %[1]s --output csv --scope process --pid 12345,67890
+  Metrics for the cgroups of specified containers to screen in CSV format.
+    $ sudo %[1]s --output csv --scope cgroup --container-name web,db
   Specified Metrics to screen in wide format.
     $ sudo %[1]s --output wide --metrics "CPU utilization %%, TMA_Frontend_Bound(%%)"
+  Metrics with names that match a regular expression to screen in wide format.
+    $ sudo %[1]s --output wide --metrics-regex "TMA_.*Bound"
+  Metrics to screen in CSV format, collecting at most 4 event groups per perf run.
+    $ sudo %[1]s --output csv --max-groups 4
+  Metrics to screen in wide format, highlighting values that exceed the levels in a thresholds file.
+    $ sudo %[1]s --output wide --thresholds thresholds.yaml
+  Metrics and the events they are calculated from to screen in CSV format.
+    $ sudo %[1]s --output csv --raw-events
+  Metrics to screen in CSV format and to Prometheus scrapes on port 9100.
+    $ sudo %[1]s --output csv --prometheus :9100
+  Confirm that all events can be counted on this platform, without collecting metrics.
+    $ sudo %[1]s --events-only
+  Metrics for "hot" processes to screen in CSV format, continuing to monitor processes that remain running when the list is refreshed.
+    $ sudo %[1]s --output csv --scope process --sticky-pids
   Metrics for the "hottest" process to screen in CSV format.
     $ sudo %[1]s --output csv --scope process --count 1
 Post-processing Examples
@@ -701,6 +981,8 @@ Post-processing Examples
     $ %[1]s --post-process %[1]s.csv --format html >summary.html
   Create summary CSV report from any metrics CSV file to screen and file.
     $ %[1]s --post-process %[1]s.csv --format csv | tee summary.csv
+  Create summary CSV report from all metrics CSV files in a directory.
+    $ %[1]s --post-process ./hourly --format csv
 `
 	fmt.Printf(examples, filepath.Base(os.Args[0]))
 }
@@ -725,12 +1007,14 @@ func configureArgs() (err error) {
 	flag.StringVar(&gCmdLineArgs.pidList, "pid", "", "")
 	flag.StringVar(&gCmdLineArgs.cidList, "c", "", "")
 	flag.StringVar(&gCmdLineArgs.cidList, "cid", "", "")
+	flag.StringVar(&gCmdLineArgs.containers, "container-name", "", "")
 	flag.StringVar(&gCmdLineArgs.filter, "F", "", "")
 	flag.StringVar(&gCmdLineArgs.filter, "filter", "", "")
 	flag.IntVar(&gCmdLineArgs.count, "n", 5, "")
 	flag.IntVar(&gCmdLineArgs.count, "count", 5, "")
 	flag.IntVar(&gCmdLineArgs.refresh, "r", 30, "")
 	flag.IntVar(&gCmdLineArgs.refresh, "refresh", 30, "")
+	flag.BoolVar(&gCmdLineArgs.stickyPIDs, "sticky-pids", false, "")
 	// output options
 	var granularity string
 	flag.StringVar(&granularity, "g", GranularityOptions[GranularitySystem], "")
@@ -742,6 +1026,11 @@ func configureArgs() (err error) {
 	flag.BoolVar(&gCmdLineArgs.verbose, "verbose", false, "")
 	flag.BoolVar(&gCmdLineArgs.veryVerbose, "vv", false, "")
 	flag.BoolVar(&gCmdLineArgs.veryVerbose, "veryverbose", false, "")
+	flag.StringVar(&gCmdLineArgs.prometheusAddr, "prometheus", "", "")
+	flag.StringVar(&gCmdLineArgs.outputFilePath, "output-file", "", "")
+	flag.BoolVar(&gCmdLineArgs.rawEvents, "raw-events", false, "")
+	flag.StringVar(&gCmdLineArgs.thresholdsPath, "thresholds", "", "")
+	flag.BoolVar(&gCmdLineArgs.localTime, "localtime", false, "")
 	// post-processing options
 	flag.StringVar(&gCmdLineArgs.inputCSVFilePath, "P", "", "")
 	flag.StringVar(&gCmdLineArgs.inputCSVFilePath, "post-process", "", "")
@@ -755,8 +1044,10 @@ func configureArgs() (err error) {
 	flag.BoolVar(&gCmdLineArgs.syslog, "syslog", false, "")
 	flag.StringVar(&gCmdLineArgs.metricsList, "m", "", "")
 	flag.StringVar(&gCmdLineArgs.metricsList, "metrics", "", "")
+	flag.StringVar(&gCmdLineArgs.metricsRegex, "metrics-regex", "", "")
 	flag.StringVar(&gCmdLineArgs.eventFilePath, "e", "", "")
 	flag.StringVar(&gCmdLineArgs.eventFilePath, "eventfile", "", "")
+	flag.StringVar(&gCmdLineArgs.extraEventPath, "extra-events", "", "")
 	flag.StringVar(&gCmdLineArgs.metricFilePath, "M", "", "")
 	flag.StringVar(&gCmdLineArgs.metricFilePath, "metricfile", "", "")
 	flag.IntVar(&gCmdLineArgs.perfPrintInterval, "i", 5000, "")
@@ -765,6 +1056,11 @@ func configureArgs() (err error) {
 	flag.IntVar(&gCmdLineArgs.perfMuxInterval, "muxinterval", 125, "")
 	flag.StringVar(&gCmdLineArgs.rawFilePath, "R", "", "")
 	flag.StringVar(&gCmdLineArgs.rawFilePath, "raw", "", "")
+	flag.BoolVar(&gCmdLineArgs.noWatchdogChange, "no-watchdog-change", false, "")
+	flag.StringVar(&gCmdLineArgs.perfAffinity, "perf-affinity", "", "")
+	flag.StringVar(&gCmdLineArgs.perfPath, "perf-path", "", "")
+	flag.IntVar(&gCmdLineArgs.maxGroups, "max-groups", 0, "")
+	flag.BoolVar(&gCmdLineArgs.eventsOnly, "events-only", false, "")
 	// debugging options (not shown in help/usage)
 	flag.StringVar(&gCmdLineArgs.metadataFilePath, "metadata", "", "")
 	flag.StringVar(&gCmdLineArgs.perfStatFilePath, "perfstat", "", "")
@@ -796,6 +1092,21 @@ func configureArgs() (err error) {
 		err = fmt.Errorf("--cid only valid when --scope is cgroup")
 		return
 	}
+	//  container names only when scope is cgroup and cids not specified
+	if gCmdLineArgs.containers != "" {
+		if gCmdLineArgs.scope != ScopeCgroup {
+			err = fmt.Errorf("--container-name only valid when --scope is cgroup")
+			return
+		}
+		if gCmdLineArgs.cidList != "" {
+			err = fmt.Errorf("--container-name not valid with --cid")
+			return
+		}
+		if gCmdLineArgs.filter != "" {
+			err = fmt.Errorf("--filter only valid when --pid, --cid, and --container-name are not specified")
+			return
+		}
+	}
 	//  filter only when scope is process or cgroup
 	if gCmdLineArgs.filter != "" && (gCmdLineArgs.scope != ScopeProcess && gCmdLineArgs.scope != ScopeCgroup) {
 		err = fmt.Errorf("--filter only valid when --scope is process or cgroup")
@@ -811,6 +1122,11 @@ func configureArgs() (err error) {
 		err = fmt.Errorf("--count must be one or more")
 		return
 	}
+	//  sticky pids only when refreshing the hot process list
+	if gCmdLineArgs.stickyPIDs && (gCmdLineArgs.scope != ScopeProcess || gCmdLineArgs.pidList != "") {
+		err = fmt.Errorf("--sticky-pids only valid when --scope is process and --pid is not specified")
+		return
+	}
 	//  refresh must be greater than perf print intervaal
 	if gCmdLineArgs.refresh*1000 < gCmdLineArgs.perfPrintInterval {
 		err = fmt.Errorf("--refresh must be greater than or equal to --interval")
@@ -836,6 +1152,31 @@ func configureArgs() (err error) {
 	} else {
 		gCmdLineArgs.outputFormat = Format(idx)
 	}
+	//  prometheus only when collecting
+	if gCmdLineArgs.prometheusAddr != "" && gCmdLineArgs.inputCSVFilePath != "" {
+		err = fmt.Errorf("--prometheus is not valid when post-processing")
+		return
+	}
+	//  output file only when collecting
+	if gCmdLineArgs.outputFilePath != "" && gCmdLineArgs.inputCSVFilePath != "" {
+		err = fmt.Errorf("--output-file is not valid when post-processing")
+		return
+	}
+	//  local time only when collecting
+	if gCmdLineArgs.localTime && gCmdLineArgs.inputCSVFilePath != "" {
+		err = fmt.Errorf("--localtime is not valid when post-processing")
+		return
+	}
+	//  raw events only in csv output
+	if gCmdLineArgs.rawEvents && gCmdLineArgs.outputFormat != FormatCSV {
+		err = fmt.Errorf("--raw-events is only valid when --output is csv")
+		return
+	}
+	//  thresholds only in human and wide output
+	if gCmdLineArgs.thresholdsPath != "" && gCmdLineArgs.outputFormat == FormatCSV {
+		err = fmt.Errorf("--thresholds is only valid when --output is human or wide")
+		return
+	}
 	// post-processing options
 	//  confirm a valid summary format
 	if idx, err = util.StringIndexInList(strings.ToLower(summary), SummaryOptions); err != nil {
@@ -855,6 +1196,60 @@ func configureArgs() (err error) {
 		err = fmt.Errorf("--muxinterval value must be a positive integer")
 		return
 	}
+	//  metrics regex must compile and can't be combined with a list of metrics
+	if gCmdLineArgs.metricsRegex != "" {
+		if gCmdLineArgs.metricsList != "" {
+			err = fmt.Errorf("--metrics and --metrics-regex are mutually exclusive")
+			return
+		}
+		if _, err = regexp.Compile(gCmdLineArgs.metricsRegex); err != nil {
+			err = fmt.Errorf("--metrics-regex is not a valid regular expression: %v", err)
+			return
+		}
+	}
+	//  perf affinity must be a valid cpulist, CPUs are confirmed to exist after metadata is loaded
+	if _, err = util.ParseCPUList(gCmdLineArgs.perfAffinity); err != nil {
+		err = fmt.Errorf("--perf-affinity must be a list of CPUs, e.g., 0-1,8")
+		return
+	}
+	//  perf path must be an executable file
+	if gCmdLineArgs.perfPath != "" {
+		var fileInfo os.FileInfo
+		if fileInfo, err = os.Stat(gCmdLineArgs.perfPath); err != nil {
+			err = fmt.Errorf("--perf-path %s does not exist", gCmdLineArgs.perfPath)
+			return
+		}
+		if !fileInfo.Mode().IsRegular() || fileInfo.Mode().Perm()&0111 == 0 {
+			err = fmt.Errorf("--perf-path %s is not an executable file", gCmdLineArgs.perfPath)
+			return
+		}
+	}
+	//  max groups splits collection into sequential system-wide perf runs
+	if gCmdLineArgs.maxGroups < 0 {
+		err = fmt.Errorf("--max-groups value must be a positive integer")
+		return
+	}
+	if gCmdLineArgs.maxGroups > 0 {
+		if gCmdLineArgs.scope != ScopeSystem {
+			err = fmt.Errorf("--max-groups is only valid when --scope is system")
+			return
+		}
+		if gCmdLineArgs.rawFilePath != "" {
+			err = fmt.Errorf("--max-groups and --raw are mutually exclusive")
+			return
+		}
+	}
+	//  events only checks the system-wide event groups
+	if gCmdLineArgs.eventsOnly {
+		if gCmdLineArgs.scope != ScopeSystem {
+			err = fmt.Errorf("--events-only is only valid when --scope is system")
+			return
+		}
+		if gCmdLineArgs.inputCSVFilePath != "" {
+			err = fmt.Errorf("--events-only is not valid when post-processing")
+			return
+		}
+	}
 	// debugging options
 	//  if metadata file path is provided, then perf stat file needs to be provided...and vice versa
 	if (gCmdLineArgs.metadataFilePath != "" || gCmdLineArgs.perfStatFilePath != "") &&
@@ -870,6 +1265,8 @@ const (
 	exitNoError   = 0
 	exitError     = 1
 	exitInterrupt = 2
+	// --events-only found events that can't be counted
+	exitUnsupportedEvents = 3
 )
 
 // mainReturnWithCode is responsible for initialization and highest-level program
@@ -936,6 +1333,13 @@ func mainReturnWithCode() int {
 			gCmdLineArgs.timeout = (qi + 1) * intervalSeconds
 		}
 	}
+	if gCmdLineArgs.containers != "" {
+		// collect for the named containers' cgroups, as if their IDs were provided with --cid
+		if gCmdLineArgs.cidList, err = GetContainerIDs(gCmdLineArgs.containers); err != nil {
+			log.Printf("failed to resolve container names: %v", err)
+			return exitError
+		}
+	}
 	if gCmdLineArgs.outputFormat != FormatCSV {
 		fmt.Print("Loading.")
 	}
@@ -967,6 +1371,12 @@ func mainReturnWithCode() int {
 		}
 	}
 	log.Printf("%s", metadata)
+	if gCmdLineArgs.perfAffinity != "" {
+		if err = validatePerfAffinity(metadata); err != nil {
+			log.Printf("invalid --perf-affinity: %v", err)
+			return exitError
+		}
+	}
 	if gCmdLineArgs.rawFilePath != "" {
 		if err = metadata.WriteJSONToFile(gCmdLineArgs.rawFilePath); err != nil {
 			log.Printf("failed to write metadata to file: %v", err)
@@ -992,25 +1402,85 @@ func mainReturnWithCode() int {
 	if gCmdLineArgs.outputFormat != FormatCSV {
 		fmt.Print(".")
 	}
+	var metricsRegex *regexp.Regexp
+	if gCmdLineArgs.metricsRegex != "" {
+		metricsRegex = regexp.MustCompile(gCmdLineArgs.metricsRegex) // validated in configureArgs
+	}
 	if gCmdLineArgs.showMetricNames {
 		fmt.Println()
 		for _, metric := range metricDefinitions {
-			fmt.Println(metric.Name)
+			if metricsRegex == nil {
+				fmt.Println(metric.Name)
+			} else if metricsRegex.MatchString(metric.Name) {
+				fmt.Printf("* %s\n", metric.Name)
+			} else {
+				fmt.Printf("  %s\n", metric.Name)
+			}
 		}
 		return exitNoError
 	}
+	if metricsRegex != nil {
+		if metricDefinitions, err = FilterMetricDefinitions(metricDefinitions, metricsRegex); err != nil {
+			log.Printf("failed to select metrics: %v", err)
+			return exitError
+		}
+	}
+	if gCmdLineArgs.thresholdsPath != "" {
+		if gMetricThresholds, err = LoadMetricThresholds(gCmdLineArgs.thresholdsPath); err != nil {
+			log.Printf("failed to load metric thresholds: %v", err)
+			return exitError
+		}
+		// a threshold for a metric that isn't collected is probably a typo
+		for name := range gMetricThresholds {
+			if !slices.ContainsFunc(metricDefinitions, func(m MetricDefinition) bool { return m.Name == name }) {
+				log.Printf("WARNING: threshold for unknown or unselected metric: %s", name)
+			}
+		}
+	}
 	if err = ConfigureMetrics(metricDefinitions, evaluatorFunctions, metadata); err != nil {
 		log.Printf("failed to configure metrics: %v", err)
 		return exitError
 	}
 	var groupDefinitions []GroupDefinition
-	if groupDefinitions, err = LoadEventGroups(gCmdLineArgs.eventFilePath, metadata); err != nil {
+	var eventNames mapset.Set[string]
+	if groupDefinitions, eventNames, err = LoadEventGroups(gCmdLineArgs.eventFilePath, metadata); err != nil {
 		log.Printf("failed to load event definitions: %v", err)
 		return exitError
 	}
+	if gCmdLineArgs.extraEventPath != "" {
+		var extraGroupDefinitions []GroupDefinition
+		var extraEventNames mapset.Set[string]
+		if extraGroupDefinitions, extraEventNames, err = LoadExtraEventGroups(gCmdLineArgs.extraEventPath, metadata); err != nil {
+			log.Printf("failed to load extra event definitions: %v", err)
+			return exitError
+		}
+		groupDefinitions = append(groupDefinitions, extraGroupDefinitions...)
+		eventNames = eventNames.Union(extraEventNames)
+	}
+	// catch typos in custom metric definitions before collection starts, a metric that references
+	// an undefined event would otherwise always be NaN
+	if err = ValidateMetricVariables(metricDefinitions, eventNames); err != nil {
+		if gCmdLineArgs.metricFilePath != "" {
+			log.Printf("invalid metric definitions: %v", err)
+			return exitError
+		}
+		if gCmdLineArgs.verbose {
+			log.Printf("%v", err)
+		}
+		err = nil
+	}
 	if gCmdLineArgs.outputFormat != FormatCSV {
 		fmt.Print(".")
 	}
+	if gCmdLineArgs.outputFilePath != "" {
+		var outputFile *os.File
+		if outputFile, err = createOutputFile(gCmdLineArgs.outputFilePath); err != nil {
+			log.Printf("failed to create output file: %v", err)
+			return exitError
+		}
+		defer outputFile.Close()
+		gMetricOutput = outputFile
+	}
 	if gCmdLineArgs.perfStatFilePath != "" { // testing/debugging flow
 		fmt.Print(".\n")
 		if err = doWorkDebug(gCmdLineArgs.perfStatFilePath, groupDefinitions, metricDefinitions, metadata); err != nil {
@@ -1022,22 +1492,24 @@ func mainReturnWithCode() int {
 			log.Println("Elevated permissions required, try again as root user or with sudo.")
 			return exitError
 		}
-		var nmiWatchdogEnabled bool
-		if nmiWatchdogEnabled, err = NMIWatchdogEnabled(); err != nil {
-			log.Printf("failed to retrieve NMI watchdog status: %v", err)
-			return exitError
-		}
-		if nmiWatchdogEnabled {
-			if err = DisableNMIWatchdog(); err != nil {
-				log.Printf("failed to disable NMI watchdog: %v", err)
-				return exitError
-			}
-			defer func() {
-				err = EnableNMIWatchdog()
-				if err != nil {
-					log.Printf("failed to enable NMI watchdog: %v", err)
+		// the NMI watchdog occupies a counter, disable it (when allowed) to make the counter available
+		// for collection, failure to do so is not fatal...collection proceeds with one fewer counter
+		if !gCmdLineArgs.noWatchdogChange {
+			var nmiWatchdogEnabled bool
+			if nmiWatchdogEnabled, err = NMIWatchdogEnabled(); err != nil {
+				log.Printf("Warning: failed to retrieve NMI watchdog status, continuing without disabling it: %v", err)
+			} else if nmiWatchdogEnabled {
+				if err = DisableNMIWatchdog(); err != nil {
+					log.Printf("Warning: failed to disable NMI watchdog, continuing with one fewer counter: %v", err)
+				} else {
+					defer func() {
+						err = EnableNMIWatchdog()
+						if err != nil {
+							log.Printf("failed to enable NMI watchdog: %v", err)
+						}
+					}()
 				}
-			}()
+			}
 		}
 		if gCmdLineArgs.outputFormat != FormatCSV {
 			fmt.Print(".")
@@ -1052,6 +1524,21 @@ func mainReturnWithCode() int {
 			return exitError
 		}
 		defer SetMuxIntervals(perfMuxIntervals)
+		if gCmdLineArgs.eventsOnly {
+			if gCmdLineArgs.outputFormat != FormatCSV {
+				fmt.Print(".\n")
+			}
+			var uncountedEvents []UncountedEvent
+			if uncountedEvents, err = checkEvents(perfPath, groupDefinitions); err != nil {
+				log.Printf("failed to check events: %v", err)
+				return exitError
+			}
+			printUncountedEvents(uncountedEvents, groupDefinitions)
+			if len(uncountedEvents) > 0 {
+				return exitUnsupportedEvents
+			}
+			return exitNoError
+		}
 		if gCmdLineArgs.outputFormat != FormatCSV {
 			fmt.Print(".\n")
 			fmt.Printf("Reporting metrics in %d millisecond intervals...\n", gCmdLineArgs.perfPrintInterval)
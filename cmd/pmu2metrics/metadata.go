@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	"github.com/intel/svr-info/internal/cpudb"
+	"github.com/intel/svr-info/internal/util"
 	"gopkg.in/yaml.v2"
 )
 
@@ -30,10 +31,12 @@ import (
 type Metadata struct {
 	CoresPerSocket           int `yaml:"CoresPerSocket"`
 	CPUSocketMap             map[int]int
+	CPUNodeMap               map[int]int
 	DeviceIDs                map[string][]int `yaml:"DeviceIDs"`
 	FixedCounterTMASupported bool             `yaml:"FixedCounterTMASupported"`
 	Microarchitecture        string           `yaml:"Microarchitecture"`
 	ModelName                string
+	NUMANodeCount            int    `yaml:"NUMANodeCount"`
 	PerfSupportedEvents      string `yaml:"PerfSupportedEvents"`
 	PMUDriverVersion         string `yaml:"PMUDriverVersion"`
 	RefCyclesSupported       bool   `yaml:"RefCyclesSupported"`
@@ -130,6 +133,15 @@ func LoadMetadata(perfPath string) (metadata Metadata, err error) {
 	}
 	// CPUSocketMap
 	metadata.CPUSocketMap = createCPUSocketMap(metadata.CoresPerSocket, metadata.SocketCount, metadata.ThreadsPerCore == 2)
+	// CPUNodeMap
+	if metadata.CPUNodeMap, metadata.NUMANodeCount, err = getCPUNodeMap(); err != nil {
+		err = fmt.Errorf("failed to retrieve NUMA node CPU lists: %v", err)
+		return
+	}
+	if metadata.NUMANodeCount == 0 { // no NUMA nodes reported by the kernel, treat each socket as a node
+		metadata.NUMANodeCount = metadata.SocketCount
+		metadata.CPUNodeMap = createCPUNodeMap(metadata.CoresPerSocket, metadata.SocketCount, metadata.ThreadsPerCore == 2, metadata.NUMANodeCount)
+	}
 	// System TSC Frequency
 	metadata.TSCFrequencyHz = GetTSCFreqMHz() * 1000000
 	// calculate TSC
@@ -156,6 +168,7 @@ func LoadMetadata(perfPath string) (metadata Metadata, err error) {
 // Microarchitecture   string
 // SocketCount         int
 // ThreadsPerCore      int
+// NUMANodeCount       int (optional, defaults to SocketCount)
 // TSC                 int
 // TSCFrequencyHz      int
 func LoadMetadataFromFile(metadataFilePath string) (metadata Metadata, err error) {
@@ -171,6 +184,14 @@ func LoadMetadataFromFile(metadataFilePath string) (metadata Metadata, err error
 		return
 	}
 	metadata.CPUSocketMap = createCPUSocketMap(metadata.CoresPerSocket, metadata.SocketCount, metadata.ThreadsPerCore == 2)
+	if metadata.NUMANodeCount == 0 {
+		metadata.NUMANodeCount = metadata.SocketCount
+	}
+	if metadata.NUMANodeCount%metadata.SocketCount != 0 {
+		err = fmt.Errorf("NUMA node count must be a multiple of socket count")
+		return
+	}
+	metadata.CPUNodeMap = createCPUNodeMap(metadata.CoresPerSocket, metadata.SocketCount, metadata.ThreadsPerCore == 2, metadata.NUMANodeCount)
 	return
 }
 
@@ -180,6 +201,7 @@ func (md Metadata) String() string {
 		"Model Name: %s, "+
 		"Microarchitecture: %s, "+
 		"Socket Count: %d, "+
+		"NUMA Node Count: %d, "+
 		"Cores Per Socket: %d, "+
 		"Threads per Core: %d, "+
 		"TSC Frequency (Hz): %d, "+
@@ -190,6 +212,7 @@ func (md Metadata) String() string {
 		md.ModelName,
 		md.Microarchitecture,
 		md.SocketCount,
+		md.NUMANodeCount,
 		md.CoresPerSocket,
 		md.ThreadsPerCore,
 		md.TSCFrequencyHz,
@@ -378,3 +401,63 @@ func createCPUSocketMap(coresPerSocket int, sockets int, hyperthreading bool) (c
 	}
 	return cpuSocketMap
 }
+
+// createCPUNodeMap creates a map from CPU number to NUMA node number assuming that each
+// socket's cores are evenly divided between the socket's NUMA nodes
+func createCPUNodeMap(coresPerSocket int, sockets int, hyperthreading bool, nodes int) (cpuNodeMap map[int]int) {
+	cpuNodeMap = make(map[int]int)
+	nodesPerSocket := max(nodes/sockets, 1)
+	coresPerNode := max(coresPerSocket/nodesPerSocket, 1)
+	totalCores := coresPerSocket * sockets
+	totalCPUs := totalCores
+	if hyperthreading {
+		totalCPUs *= 2
+	}
+	for i := 0; i < totalCPUs; i++ {
+		// the second logical CPU of each core is in the second half
+		core := i % totalCores
+		socket := core / coresPerSocket
+		nodeInSocket := min((core%coresPerSocket)/coresPerNode, nodesPerSocket-1)
+		cpuNodeMap[i] = socket*nodesPerSocket + nodeInSocket
+	}
+	return
+}
+
+// getCPUNodeMap creates a map from CPU number to NUMA node number from the CPU lists
+// found in sysfs. Node count is the number of nodes that have CPUs, i.e., memory-only
+// nodes aren't counted. Node count will be zero if no NUMA nodes are found.
+func getCPUNodeMap() (cpuNodeMap map[int]int, nodeCount int, err error) {
+	cpuNodeMap = make(map[int]int)
+	pattern := filepath.Join("/", "sys", "devices", "system", "node", "node*", "cpulist")
+	var fileNames []string
+	if fileNames, err = filepath.Glob(pattern); err != nil {
+		return
+	}
+	re := regexp.MustCompile(`node(\d+)`)
+	for _, fileName := range fileNames {
+		match := re.FindStringSubmatch(filepath.Base(filepath.Dir(fileName)))
+		if match == nil {
+			continue
+		}
+		var node int
+		if node, err = strconv.Atoi(match[1]); err != nil {
+			return
+		}
+		var content []byte
+		if content, err = os.ReadFile(fileName); err != nil {
+			return
+		}
+		var cpus []int
+		if cpus, err = util.ParseCPUList(strings.TrimSpace(string(content))); err != nil {
+			return
+		}
+		if len(cpus) == 0 {
+			continue // memory-only node
+		}
+		for _, cpu := range cpus {
+			cpuNodeMap[cpu] = node
+		}
+		nodeCount++
+	}
+	return
+}
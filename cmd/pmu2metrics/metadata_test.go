@@ -0,0 +1,28 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"testing"
+)
+
+func TestCreateCPUNodeMap(t *testing.T) {
+	// 2 sockets, 4 cores per socket, hyperthreading, 2 nodes per socket
+	cpuNodeMap := createCPUNodeMap(4, 2, true, 4)
+	expected := []int{0, 0, 1, 1, 2, 2, 3, 3, 0, 0, 1, 1, 2, 2, 3, 3}
+	for cpu, node := range expected {
+		if cpuNodeMap[cpu] != node {
+			t.Errorf("cpu %d: expected node %d, got %d", cpu, node, cpuNodeMap[cpu])
+		}
+	}
+	// one node per socket matches the socket map
+	cpuSocketMap := createCPUSocketMap(4, 2, true)
+	cpuNodeMap = createCPUNodeMap(4, 2, true, 2)
+	for cpu, socket := range cpuSocketMap {
+		if cpuNodeMap[cpu] != socket {
+			t.Errorf("cpu %d: expected node %d, got %d", cpu, socket, cpuNodeMap[cpu])
+		}
+	}
+}
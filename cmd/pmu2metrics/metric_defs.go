@@ -10,9 +10,12 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/Knetic/govaluate"
+	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/intel/svr-info/internal/util"
 )
 
@@ -87,6 +90,20 @@ func LoadMetricDefinitions(metricDefinitionOverridePath string, selectedMetrics
 	return
 }
 
+// FilterMetricDefinitions returns the metric definitions whose names match the provided
+// regular expression
+func FilterMetricDefinitions(metrics []MetricDefinition, re *regexp.Regexp) (filtered []MetricDefinition, err error) {
+	for _, metric := range metrics {
+		if re.MatchString(metric.Name) {
+			filtered = append(filtered, metric)
+		}
+	}
+	if len(filtered) == 0 {
+		err = fmt.Errorf("no metric names match the regular expression: %s", re.String())
+	}
+	return
+}
+
 // ConfigureMetrics prepares metrics for use by the evaluator, by e.g., replacing
 // metric constants with known values and aligning metric variables to perf event
 // groups
@@ -145,6 +162,26 @@ func ConfigureMetrics(metrics []MetricDefinition, evaluatorFunctions map[string]
 	return
 }
 
+// ValidateMetricVariables confirms that each variable in the metrics' expressions, i.e., each
+// name in square brackets that isn't a known constant, is the name of a defined event. Call
+// after ConfigureMetrics.
+func ValidateMetricVariables(metrics []MetricDefinition, eventNames mapset.Set[string]) (err error) {
+	for _, metric := range metrics {
+		variableNames := make([]string, 0, len(metric.Variables))
+		for variableName := range metric.Variables {
+			variableNames = append(variableNames, variableName)
+		}
+		sort.Strings(variableNames)
+		for _, variableName := range variableNames {
+			if !eventNames.Contains(variableName) {
+				err = fmt.Errorf("metric %s references unknown event %s", metric.Name, variableName)
+				return
+			}
+		}
+	}
+	return
+}
+
 // transformConditional transforms if/else to ternary conditional (? :) so expression evaluator can handle it
 // simple:
 // from: <expression 1> if <condition> else <expression 2>
@@ -4,7 +4,12 @@
  */
 package main
 
-import "testing"
+import (
+	"regexp"
+	"testing"
+
+	mapset "github.com/deckarep/golang-set/v2"
+)
 
 func TestTransformConditional(t *testing.T) {
 	var in string
@@ -74,3 +79,44 @@ func TestTransformConditional(t *testing.T) {
 		t.Errorf("improper transform: [%s] -> [%s]", in, out)
 	}
 }
+
+func TestFilterMetricDefinitions(t *testing.T) {
+	metrics := []MetricDefinition{
+		{Name: "CPU utilization %"},
+		{Name: "TMA_Frontend_Bound(%)"},
+		{Name: "TMA_Backend_Bound(%)"},
+		{Name: "TMA_Retiring(%)"},
+	}
+	filtered, err := FilterMetricDefinitions(metrics, regexp.MustCompile(`TMA_.*Bound`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 2 || filtered[0].Name != "TMA_Frontend_Bound(%)" || filtered[1].Name != "TMA_Backend_Bound(%)" {
+		t.Errorf("unexpected metrics selected: %v", filtered)
+	}
+	if _, err = FilterMetricDefinitions(metrics, regexp.MustCompile(`^nomatch$`)); err == nil {
+		t.Error("didn't catch regex that matches no metrics")
+	}
+}
+
+func TestValidateMetricVariables(t *testing.T) {
+	metrics := []MetricDefinition{
+		{Name: "CPI", Expression: "[cpu-cycles] / [instructions]"},
+		{Name: "TSC Ratio", Expression: "[cpu-cycles] / [TSC]"},
+	}
+	if err := ConfigureMetrics(metrics, GetEvaluatorFunctions(), Metadata{}); err != nil {
+		t.Fatal(err)
+	}
+	eventNames := mapset.NewSet("cpu-cycles", "instructions")
+	if err := ValidateMetricVariables(metrics, eventNames); err != nil {
+		t.Error(err)
+	}
+	metrics = []MetricDefinition{{Name: "typo", Expression: "[cpu-cycles] / [instructons]"}}
+	if err := ConfigureMetrics(metrics, GetEvaluatorFunctions(), Metadata{}); err != nil {
+		t.Fatal(err)
+	}
+	err := ValidateMetricVariables(metrics, eventNames)
+	if err == nil || err.Error() != "metric typo references unknown event instructons" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"log"
 	"math"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 
@@ -31,10 +32,12 @@ type MetricFrame struct {
 	Timestamp  float64
 	FrameCount int
 	Socket     string
+	Node       string
 	CPU        string
 	Cgroup     string
 	PID        string
 	Cmd        string
+	Events     []Metric // raw event values, only when --raw-events is specified
 }
 
 // ProcessEvents is responsible for producing metrics from raw perf events
@@ -50,6 +53,23 @@ func ProcessEvents(perfEvents [][]byte, eventGroupDefinitions []GroupDefinition,
 		err = fmt.Errorf("failed to put perf events into groups: %v", err)
 		return
 	}
+	return getMetricFrames(eventFrames, metricDefinitions, process, previousTimestamp, metadata)
+}
+
+// ProcessEventBatches is ProcessEvents for events collected by sequential perf runs, one run
+// per batch of event groups (see --max-groups). The batches' events are merged into frames
+// with the given timestamp.
+func ProcessEventBatches(perfEventBatches [][][]byte, batchGroupDefinitions [][]GroupDefinition, scaleFactor float64, timestamp float64, metricDefinitions []MetricDefinition, previousTimestamp float64, metadata Metadata) (metricFrames []MetricFrame, timeStamp float64, err error) {
+	var eventFrames []EventFrame
+	if eventFrames, err = GetEventFramesFromBatches(perfEventBatches, batchGroupDefinitions, scaleFactor, timestamp, gCmdLineArgs.scope, gCmdLineArgs.granularity, metadata); err != nil {
+		err = fmt.Errorf("failed to put perf events into groups: %v", err)
+		return
+	}
+	return getMetricFrames(eventFrames, metricDefinitions, Process{}, previousTimestamp, metadata)
+}
+
+// getMetricFrames evaluates the metrics for each frame of events
+func getMetricFrames(eventFrames []EventFrame, metricDefinitions []MetricDefinition, process Process, previousTimestamp float64, metadata Metadata) (metricFrames []MetricFrame, timeStamp float64, err error) {
 	metricFrames = make([]MetricFrame, 0, len(eventFrames))
 	for _, eventFrame := range eventFrames {
 		timeStamp = eventFrame.Timestamp
@@ -57,10 +77,14 @@ func ProcessEvents(perfEvents [][]byte, eventGroupDefinitions []GroupDefinition,
 		metricFrame.Metrics = make([]Metric, 0, len(metricDefinitions))
 		metricFrame.Timestamp = eventFrame.Timestamp
 		metricFrame.Socket = eventFrame.Socket
+		metricFrame.Node = eventFrame.Node
 		metricFrame.CPU = eventFrame.CPU
 		metricFrame.Cgroup = eventFrame.Cgroup
 		metricFrame.PID = process.pid
 		metricFrame.Cmd = process.cmd
+		if gCmdLineArgs.rawEvents {
+			metricFrame.Events = getRawEvents(eventFrame)
+		}
 		// produce metrics from event groups
 		for _, metricDef := range metricDefinitions {
 			metric := Metric{Name: metricDef.Name, Value: math.NaN()}
@@ -95,6 +119,23 @@ func ProcessEvents(perfEvents [][]byte, eventGroupDefinitions []GroupDefinition,
 	return
 }
 
+// getRawEvents returns the value of each event in the frame. An event, e.g., a fixed counter
+// event, may be collected in more than one group, so each event's name is prefixed with the
+// index of its group, e.g., g2:instructions.
+func getRawEvents(eventFrame EventFrame) (events []Metric) {
+	for groupIdx, group := range eventFrame.EventGroups {
+		eventNames := make([]string, 0, len(group.EventValues))
+		for eventName := range group.EventValues {
+			eventNames = append(eventNames, eventName)
+		}
+		sort.Strings(eventNames)
+		for _, eventName := range eventNames {
+			events = append(events, Metric{Name: fmt.Sprintf("g%d:%s", groupIdx, eventName), Value: group.EventValues[eventName]})
+		}
+	}
+	return
+}
+
 // GetEvaluatorFunctions defines functions that can be called in metric expressions
 func GetEvaluatorFunctions() (functions map[string]govaluate.ExpressionFunction) {
 	functions = make(map[string]govaluate.ExpressionFunction)
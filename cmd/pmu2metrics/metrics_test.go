@@ -0,0 +1,33 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"testing"
+)
+
+func TestGetRawEvents(t *testing.T) {
+	frame := EventFrame{
+		EventGroups: []EventGroup{
+			{EventValues: map[string]float64{"instructions": 400, "cpu-cycles": 200}},
+			{EventValues: map[string]float64{"instructions": 410, "branch-misses": 14}},
+		},
+	}
+	events := getRawEvents(frame)
+	expected := []Metric{
+		{Name: "g0:cpu-cycles", Value: 200},
+		{Name: "g0:instructions", Value: 400},
+		{Name: "g1:branch-misses", Value: 14},
+		{Name: "g1:instructions", Value: 410},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], events[i])
+		}
+	}
+}
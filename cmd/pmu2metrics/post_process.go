@@ -14,17 +14,25 @@ import (
 	"io"
 	"math"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/intel/svr-info/internal/util"
 )
 
-// PostProcess - generates formatted output from a CSV file containing metric values. Format
+// PostProcess - generates formatted output from CSV file(s) containing metric values. The
+// input is a comma-separated list of CSV files and/or directories containing CSV files. Format
 // options are 'html' and 'csv'.
-func PostProcess(csvInputPath string, format Summary) (out string, err error) {
+func PostProcess(csvInput string, format Summary) (out string, err error) {
+	var csvInputPaths []string
+	if csvInputPaths, err = getCSVInputPaths(csvInput); err != nil {
+		return
+	}
 	var metrics []metricsFromCSV
-	if metrics, err = newMetricsFromCSV(csvInputPath); err != nil {
+	if metrics, err = newMetricsFromCSV(csvInputPaths); err != nil {
 		return
 	}
 	if format == SummaryHTML {
@@ -48,6 +56,30 @@ func PostProcess(csvInputPath string, format Summary) (out string, err error) {
 	return
 }
 
+// getCSVInputPaths expands the comma-separated list of files and directories into a list of
+// CSV file paths
+func getCSVInputPaths(csvInput string) (csvInputPaths []string, err error) {
+	for _, path := range strings.Split(csvInput, ",") {
+		var fileInfo os.FileInfo
+		if fileInfo, err = os.Stat(path); err != nil {
+			return
+		}
+		if !fileInfo.IsDir() {
+			csvInputPaths = append(csvInputPaths, path)
+			continue
+		}
+		var matches []string
+		if matches, err = filepath.Glob(filepath.Join(path, "*.csv")); err != nil {
+			return
+		}
+		csvInputPaths = append(csvInputPaths, matches...)
+	}
+	if len(csvInputPaths) == 0 {
+		err = fmt.Errorf("no CSV files found in %s", csvInput)
+	}
+	return
+}
+
 type metricStats struct {
 	mean   float64
 	min    float64
@@ -58,6 +90,7 @@ type metricStats struct {
 type row struct {
 	timestamp float64
 	socket    string
+	node      string
 	cpu       string
 	pid       string
 	cmd       string
@@ -77,6 +110,8 @@ func newRow(fields []string, names []string) (r row, err error) {
 			r.timestamp = ts
 		} else if fIdx == Socket {
 			r.socket = field
+		} else if fIdx == Node {
+			r.node = field
 		} else if fIdx == CPU {
 			r.cpu = field
 		} else if fIdx == Pid {
@@ -104,6 +139,7 @@ func newRow(fields []string, names []string) (r row, err error) {
 const (
 	Timestamp int = iota
 	Socket
+	Node
 	CPU
 	Pid
 	Cmd
@@ -111,6 +147,15 @@ const (
 	FirstMetric
 )
 
+// nonMetricColumns are the names of the columns that precede the metrics, in the order of the
+// indices above. Columns are found by name when the CSV is read, so that CSVs written by earlier
+// versions, e.g., without NODE, are read too.
+var nonMetricColumns = []string{"TS", "SKT", "NODE", "CPU", "PID", "CMD", "CID"}
+
+// localTimeColumn is the optional column, after TS, added by --localtime. It is removed when
+// the CSV is read.
+const localTimeColumn = "LOCALTIME"
+
 type metricsFromCSV struct {
 	names        []string
 	rows         []row
@@ -118,18 +163,14 @@ type metricsFromCSV struct {
 	groupByValue string
 }
 
-// newMetricsFromCSV - loads data from CSV. Returns a list of metrics, one per
-// scope unit or granularity unit, e.g., one per socket, or one per PID
-func newMetricsFromCSV(csvPath string) (metrics []metricsFromCSV, err error) {
+// readCSV - reads the header and data records from a CSV file
+func readCSV(csvPath string) (header []string, records [][]string, err error) {
 	var file *os.File
 	if file, err = os.Open(csvPath); err != nil {
 		return
 	}
+	defer file.Close()
 	reader := csv.NewReader(file)
-	groupByField := -1
-	var groupByValues []string
-	var metricNames []string
-	var nonMetricNames []string
 	for idx := 0; true; idx++ {
 		var fields []string
 		if fields, err = reader.Read(); err != nil {
@@ -143,22 +184,117 @@ func newMetricsFromCSV(csvPath string) (metrics []metricsFromCSV, err error) {
 			break
 		}
 		if idx == 0 {
-			// headers
-			for fIdx, field := range fields {
-				if fIdx < FirstMetric {
-					nonMetricNames = append(nonMetricNames, field)
-				} else {
-					metricNames = append(metricNames, field)
-				}
-			}
+			header = fields
 			continue
 		}
+		records = append(records, fields)
+	}
+	header, records, err = normalizeColumns(header, records)
+	return
+}
+
+// normalizeColumns - reorders the columns to the non-metric columns, at the indices above, followed
+// by the metrics. Non-metric columns missing from the header are added with empty values and the
+// LOCALTIME column is removed.
+func normalizeColumns(header []string, records [][]string) (normalizedHeader []string, normalizedRecords [][]string, err error) {
+	if !slices.Contains(header, nonMetricColumns[Timestamp]) {
+		err = fmt.Errorf("%s column not found", nonMetricColumns[Timestamp])
+		return
+	}
+	// column indices in the file, -1 when the column isn't present
+	var columns []int
+	for _, name := range nonMetricColumns {
+		columns = append(columns, slices.Index(header, name))
+	}
+	normalizedHeader = append(normalizedHeader, nonMetricColumns...)
+	for idx, name := range header {
+		if !slices.Contains(nonMetricColumns, name) && name != localTimeColumn {
+			columns = append(columns, idx)
+			normalizedHeader = append(normalizedHeader, name)
+		}
+	}
+	for _, record := range records {
+		var normalized []string
+		for _, idx := range columns {
+			if idx >= 0 && idx < len(record) {
+				normalized = append(normalized, record[idx])
+			} else {
+				normalized = append(normalized, "")
+			}
+		}
+		normalizedRecords = append(normalizedRecords, normalized)
+	}
+	return
+}
+
+// readCSVs - reads the data records from one or more CSV files, in timestamp order. All files
+// must have the same header, i.e., the same metrics.
+func readCSVs(csvPaths []string) (header []string, records [][]string, err error) {
+	for i, csvPath := range csvPaths {
+		var fileHeader []string
+		var fileRecords [][]string
+		if fileHeader, fileRecords, err = readCSV(csvPath); err != nil {
+			err = fmt.Errorf("failed to read %s: %v", csvPath, err)
+			return
+		}
+		if i == 0 {
+			header = fileHeader
+		} else if strings.Join(fileHeader, ",") != strings.Join(header, ",") {
+			err = fmt.Errorf("columns in %s do not match columns in %s", csvPath, csvPaths[0])
+			return
+		}
+		records = append(records, fileRecords...)
+	}
+	if len(csvPaths) == 1 {
+		return
+	}
+	timestamps := make([]float64, len(records))
+	for i, record := range records {
+		if timestamps[i], err = strconv.ParseFloat(record[Timestamp], 64); err != nil {
+			return
+		}
+	}
+	indices := make([]int, len(records))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool { return timestamps[indices[i]] < timestamps[indices[j]] })
+	sorted := make([][]string, len(records))
+	for i, idx := range indices {
+		sorted[i] = records[idx]
+	}
+	records = sorted
+	return
+}
+
+// newMetricsFromCSV - loads data from CSV file(s). Returns a list of metrics, one per
+// scope unit or granularity unit, e.g., one per socket, or one per PID
+func newMetricsFromCSV(csvPaths []string) (metrics []metricsFromCSV, err error) {
+	var header []string
+	var records [][]string
+	if header, records, err = readCSVs(csvPaths); err != nil {
+		return
+	}
+	groupByField := -1
+	var groupByValues []string
+	var metricNames []string
+	var nonMetricNames []string
+	for fIdx, field := range header {
+		if fIdx < FirstMetric {
+			nonMetricNames = append(nonMetricNames, field)
+		} else {
+			metricNames = append(metricNames, field)
+		}
+	}
+	for idx, fields := range records {
 		// Determine the scope and granularity of the captured data by looking
 		// at the first row of values. If none of these are set, then it's
 		// system scope and system granularity
-		if idx == 1 {
+		if idx == 0 {
 			if fields[Socket] != "" {
 				groupByField = Socket
+			} else if fields[Node] != "" {
+				groupByField = Node
 			} else if fields[CPU] != "" {
 				groupByField = CPU
 			} else if fields[Pid] != "" {
@@ -183,12 +319,15 @@ func newMetricsFromCSV(csvPath string) (metrics []metricsFromCSV, err error) {
 			groupByValue := fields[groupByField]
 			var listIdx int
 			if listIdx, err = util.StringIndexInList(groupByValue, groupByValues); err != nil {
+				err = nil // first row for this group
 				groupByValues = append(groupByValues, groupByValue)
 				metrics = append(metrics, metricsFromCSV{})
 				listIdx = len(metrics) - 1
 				metrics[listIdx].names = metricNames
 				if groupByField == Socket {
 					metrics[listIdx].groupByField = nonMetricNames[Socket]
+				} else if groupByField == Node {
+					metrics[listIdx].groupByField = nonMetricNames[Node]
 				} else if groupByField == CPU {
 					metrics[listIdx].groupByField = nonMetricNames[CPU]
 				} else if groupByField == Pid {
@@ -0,0 +1,141 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewMetricsFromCSVMultipleFiles(t *testing.T) {
+	dir := t.TempDir()
+	header := "TS,SKT,NODE,CPU,PID,CMD,CID,metric_a,metric_b\n"
+	first := filepath.Join(dir, "1.csv")
+	second := filepath.Join(dir, "2.csv")
+	// files are provided out of timestamp order
+	if err := os.WriteFile(first, []byte(header+"30,,,,,,,3,30\n40,,,,,,,4,40\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte(header+"10,,,,,,,1,10\n20,,,,,,,2,20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	paths, err := getCSVInputPaths(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("expected 2 CSV files, got %d", len(paths))
+	}
+	metrics, err := newMetricsFromCSV([]string{first, second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics) != 1 || len(metrics[0].rows) != 4 {
+		t.Fatal("expected one set of metrics with four rows")
+	}
+	for i, r := range metrics[0].rows {
+		if r.timestamp != float64((i+1)*10) {
+			t.Errorf("rows not in timestamp order: row %d has timestamp %f", i, r.timestamp)
+		}
+	}
+	stats, err := metrics[0].getStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats["metric_a"].min != 1 || stats["metric_a"].max != 4 || stats["metric_a"].mean != 2.5 {
+		t.Errorf("unexpected stats: %+v", stats["metric_a"])
+	}
+	// columns must match
+	mismatched := filepath.Join(dir, "3.csv")
+	if err := os.WriteFile(mismatched, []byte("TS,SKT,NODE,CPU,PID,CMD,CID,metric_a\n50,,,,,,,5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = newMetricsFromCSV([]string{first, mismatched}); err == nil {
+		t.Error("expected error for mismatched columns")
+	}
+}
+
+func TestNewMetricsFromCSVLocalTime(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "localtime.csv")
+	content := "TS,LOCALTIME,SKT,NODE,CPU,PID,CMD,CID,metric_a\n" +
+		"10,2024-01-02T03:04:10-08:00,,,,,,,1\n" +
+		"20,2024-01-02T03:04:20-08:00,,,,,,,3\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	metrics, err := newMetricsFromCSV([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics) != 1 || len(metrics[0].names) != 1 || metrics[0].names[0] != "metric_a" {
+		t.Fatalf("unexpected metrics: %+v", metrics)
+	}
+	if metrics[0].rows[1].timestamp != 20 || metrics[0].rows[1].metrics["metric_a"] != 3 {
+		t.Errorf("unexpected row: %+v", metrics[0].rows[1])
+	}
+}
+
+// the CSVs in scripts/ were written before the NODE column was added
+func TestNewMetricsFromCSVWithoutNode(t *testing.T) {
+	for _, tc := range []struct {
+		path         string
+		groupByField string
+		firstValue   string
+	}{
+		{"scripts/system-system.csv", "", ""},
+		{"scripts/system-socket.csv", "SKT", "0"},
+		{"scripts/system-cpu.csv", "CPU", "0"},
+		{"scripts/process-pids.csv", "PID", "230275"},
+		{"scripts/cgroup-cids.csv", "CID", ""},
+	} {
+		metrics, err := newMetricsFromCSV([]string{tc.path})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.path, err)
+		}
+		if metrics[0].groupByField != tc.groupByField {
+			t.Errorf("%s: expected group by %q, got %q", tc.path, tc.groupByField, metrics[0].groupByField)
+		}
+		if tc.firstValue != "" && metrics[0].groupByValue != tc.firstValue {
+			t.Errorf("%s: expected first group %q, got %q", tc.path, tc.firstValue, metrics[0].groupByValue)
+		}
+		if metrics[0].names[0] != "CPU operating frequency (in GHz)" {
+			t.Errorf("%s: unexpected first metric %q", tc.path, metrics[0].names[0])
+		}
+		if freq := metrics[0].rows[0].metrics["CPU operating frequency (in GHz)"]; freq < 1 || freq > 5 {
+			t.Errorf("%s: unexpected CPU operating frequency %f", tc.path, freq)
+		}
+	}
+}
+
+func TestNewMetricsFromCSVNode(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "node.csv")
+	content := "TS,SKT,NODE,CPU,PID,CMD,CID,metric_a\n" +
+		"10,,0,,,,,1\n" +
+		"10,,1,,,,,2\n" +
+		"20,,0,,,,,3\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	metrics, err := newMetricsFromCSV([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics) != 2 || metrics[0].groupByField != "NODE" || metrics[1].groupByValue != "1" {
+		t.Fatalf("unexpected metrics: %+v", metrics)
+	}
+	if len(metrics[0].rows) != 2 || metrics[0].rows[1].metrics["metric_a"] != 3 {
+		t.Errorf("unexpected rows: %+v", metrics[0].rows)
+	}
+	// TS is required
+	if err := os.WriteFile(path, []byte("SKT,metric_a\n0,1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = newMetricsFromCSV([]string{path}); err == nil {
+		t.Error("expected error for missing TS column")
+	}
+}
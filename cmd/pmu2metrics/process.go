@@ -65,6 +65,77 @@ func GetCgroups(cidList string) (cgroups []string, err error) {
 	return
 }
 
+// GetContainerIDs - gets the comma separated list of container IDs associated with the
+// given comma separated list of Docker or containerd (crictl) container names. The IDs
+// are part of the containers' cgroup names, i.e., they can be used as cids. An error
+// occurs when a given container name is not found.
+func GetContainerIDs(containerNames string) (cidList string, err error) {
+	var cids []string
+	for _, name := range strings.Split(containerNames, ",") {
+		// e.g., --container-name "web, cache"
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		var ids []string
+		if ids, err = getContainerIDs(name); err != nil {
+			return
+		}
+		cids = append(cids, ids...)
+	}
+	cidList = strings.Join(cids, ",")
+	return
+}
+
+// getContainerIDs - asks the container runtime(s) for the ID of the container with the
+// given name. Kubernetes may run more than one container with the same name, e.g., one
+// per pod, in which case all of their IDs are returned.
+func getContainerIDs(name string) (ids []string, err error) {
+	var runtimes [][]string
+	if _, lookErr := exec.LookPath("docker"); lookErr == nil {
+		runtimes = append(runtimes, []string{"docker", "inspect", "--type", "container", "--format", "{{.Id}}", name})
+	}
+	if _, lookErr := exec.LookPath("crictl"); lookErr == nil {
+		runtimes = append(runtimes, []string{"crictl", "ps", "--quiet", "--no-trunc", "--name", "^" + regexp.QuoteMeta(name) + "$"})
+	}
+	if len(runtimes) == 0 {
+		err = fmt.Errorf("container runtime (docker or crictl) not found")
+		return
+	}
+	for _, runtime := range runtimes {
+		cmd := exec.Command(runtime[0], runtime[1:]...)
+		var outBuffer, errBuffer bytes.Buffer
+		cmd.Stderr = &errBuffer
+		cmd.Stdout = &outBuffer
+		if runErr := cmd.Run(); runErr != nil {
+			if gCmdLineArgs.veryVerbose {
+				log.Printf("%s: %v, %s", strings.Join(runtime, " "), runErr, errBuffer.String())
+			}
+			continue
+		}
+		ids = append(ids, parseContainerIDs(outBuffer.String())...)
+		if len(ids) > 0 {
+			if gCmdLineArgs.verbose {
+				log.Printf("Container %s: %s", name, strings.Join(ids, ", "))
+			}
+			return
+		}
+	}
+	err = fmt.Errorf("container not found: %s", name)
+	return
+}
+
+// parseContainerIDs - gets the container IDs, one per line, from the output of the container
+// runtime's command. Blank lines are ignored.
+func parseContainerIDs(output string) (ids []string) {
+	for _, line := range strings.Split(output, "\n") {
+		if id := strings.TrimSpace(line); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return
+}
+
 // GetHotProcesses - get maxProcesses processes with highest CPU utilization, matching
 // filter if provided
 func GetHotProcesses(maxProcesses int, filter string) (processes []Process, err error) {
@@ -115,6 +186,32 @@ func GetHotProcesses(maxProcesses int, filter string) (processes []Process, err
 	return
 }
 
+// MergeStickyProcesses - keeps the previously monitored processes that are still
+// running and adds the hottest of the newly found processes, in order, until there
+// are maxProcesses processes
+func MergeStickyProcesses(previous []Process, hot []Process, maxProcesses int) (processes []Process) {
+	selected := make(map[string]bool)
+	for _, process := range previous {
+		if len(processes) == maxProcesses {
+			break
+		}
+		if processExists(process.pid) {
+			processes = append(processes, process)
+			selected[process.pid] = true
+		}
+	}
+	for _, process := range hot {
+		if len(processes) == maxProcesses {
+			break
+		}
+		if !selected[process.pid] {
+			processes = append(processes, process)
+			selected[process.pid] = true
+		}
+	}
+	return
+}
+
 // GetHotCgroups - get maxCgroups cgroup names whose associated processes have the
 // highest CPU utilization, matching filter if provided
 func GetHotCgroups(maxCgroups int, filter string) (cgroups []string, err error) {
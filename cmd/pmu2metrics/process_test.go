@@ -0,0 +1,60 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMergeStickyProcesses(t *testing.T) {
+	alive := Process{pid: strconv.Itoa(os.Getpid()), comm: "alive"}
+	exited := Process{pid: "999999999", comm: "exited"}
+	hot := []Process{{pid: "3", comm: "a"}, alive, {pid: "4", comm: "b"}, {pid: "5", comm: "c"}}
+	processes := MergeStickyProcesses([]Process{exited, alive}, hot, 3)
+	var pids []string
+	for _, process := range processes {
+		pids = append(pids, process.pid)
+	}
+	expected := []string{alive.pid, "3", "4"}
+	if len(pids) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, pids)
+	}
+	for i := range expected {
+		if pids[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, pids)
+		}
+	}
+	// without previous processes, the hot processes are used as-is
+	if processes = MergeStickyProcesses(nil, hot, 2); len(processes) != 2 || processes[0].pid != "3" {
+		t.Errorf("unexpected processes: %v", processes)
+	}
+}
+
+func TestParseContainerIDs(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		output string
+		ids    []string
+	}{
+		// docker inspect --type container --format {{.Id}} web
+		{"docker", "4c01db0b339cb3e1f2a8d07a5b1c8e5d7f6a9b0c1d2e3f4a5b6c7d8e9f0a1b2c\n", []string{"4c01db0b339cb3e1f2a8d07a5b1c8e5d7f6a9b0c1d2e3f4a5b6c7d8e9f0a1b2c"}},
+		// crictl ps --quiet --no-trunc --name ^coredns$, one container per pod
+		{"crictl, two pods", "1f73f2d81bf98ffb1a9d3e2c4b5a6978a1b2c3d4e5f60718293a4b5c6d7e8f90\r\n2a84c3e9d1f0a7b6c5d4e3f2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2\n\n", []string{"1f73f2d81bf98ffb1a9d3e2c4b5a6978a1b2c3d4e5f60718293a4b5c6d7e8f90", "2a84c3e9d1f0a7b6c5d4e3f2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2"}},
+		{"not found", "\n", nil},
+		{"no output", "", nil},
+	} {
+		ids := parseContainerIDs(tc.output)
+		if strings.Join(ids, ",") != strings.Join(tc.ids, ",") {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.ids, ids)
+		}
+	}
+	// blank names are skipped, the runtimes aren't asked about them
+	if cids, err := GetContainerIDs(" , "); err != nil || cids != "" {
+		t.Errorf("expected no container IDs, got %q, %v", cids, err)
+	}
+}
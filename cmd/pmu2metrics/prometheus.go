@@ -0,0 +1,140 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+//
+// serves the most recent metric values in Prometheus text exposition format
+//
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PrometheusCache holds the most recent metric frame for each unique set of labels, e.g.,
+// one frame per socket when collecting at socket granularity
+type PrometheusCache struct {
+	mutex  sync.RWMutex
+	frames map[string]MetricFrame // label set: frame
+}
+
+// NewPrometheusCache returns an empty cache
+func NewPrometheusCache() *PrometheusCache {
+	return &PrometheusCache{frames: make(map[string]MetricFrame)}
+}
+
+// Update replaces the cached frame having the same labels as the provided frame
+func (c *PrometheusCache) Update(frame MetricFrame) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.frames[getPrometheusLabels(frame)] = frame
+}
+
+// Write writes the cached metric values in Prometheus text exposition format
+func (c *PrometheusCache) Write(w io.Writer) (err error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	var labelSets []string
+	for labels := range c.frames {
+		labelSets = append(labelSets, labels)
+	}
+	sort.Strings(labelSets)
+	// all samples for a metric must be grouped together, following its TYPE line
+	var names []string
+	samples := make(map[string][]string) // prometheus name: samples
+	for _, labels := range labelSets {
+		for _, metric := range c.frames[labels].Metrics {
+			if math.IsNaN(metric.Value) || math.IsInf(metric.Value, 0) {
+				continue
+			}
+			name := getPrometheusName(metric.Name)
+			if _, ok := samples[name]; !ok {
+				names = append(names, name)
+			}
+			samples[name] = append(samples[name], fmt.Sprintf("%s%s %s", name, labels, strconv.FormatFloat(metric.Value, 'g', -1, 64)))
+		}
+	}
+	for _, name := range names {
+		if _, err = fmt.Fprintf(w, "# TYPE %s gauge\n%s\n", name, strings.Join(samples[name], "\n")); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// ServeHTTP handles requests for /metrics
+func (c *PrometheusCache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := c.Write(w); err != nil {
+		log.Printf("failed to write prometheus metrics: %v", err)
+	}
+}
+
+// StartPrometheusServer starts serving the cached metrics at addr/metrics. The listener is created
+// before returning so that address errors are reported to the caller.
+func StartPrometheusServer(addr string, cache *PrometheusCache) (err error) {
+	var listener net.Listener
+	if listener, err = net.Listen("tcp", addr); err != nil {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", cache)
+	go func() {
+		if err := http.Serve(listener, mux); err != nil {
+			log.Printf("prometheus server stopped: %v", err)
+		}
+	}()
+	return
+}
+
+var rePrometheusInvalid = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// getPrometheusName converts a metric name into a valid Prometheus metric name,
+// e.g., "CPU utilization %" -> "pmu2metrics_cpu_utilization_percent"
+func getPrometheusName(metricName string) (name string) {
+	name = strings.ReplaceAll(metricName, "%", " percent ")
+	name = rePrometheusInvalid.ReplaceAllString(name, "_")
+	name = strings.Trim(strings.ToLower(name), "_")
+	name = "pmu2metrics_" + name
+	return
+}
+
+// getPrometheusLabels formats the frame's socket, node, cpu, pid, cmd, and cgroup, when
+// set, as Prometheus labels, e.g., {socket="0"}
+func getPrometheusLabels(frame MetricFrame) (labels string) {
+	var pairs []string
+	for _, label := range []struct {
+		name  string
+		value string
+	}{
+		{"socket", frame.Socket},
+		{"node", frame.Node},
+		{"cpu", frame.CPU},
+		{"pid", frame.PID},
+		{"cmd", frame.Cmd},
+		{"cgroup", frame.Cgroup},
+	} {
+		if label.value != "" {
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", label.name, escapePrometheusLabelValue(label.value)))
+		}
+	}
+	if len(pairs) > 0 {
+		labels = "{" + strings.Join(pairs, ",") + "}"
+	}
+	return
+}
+
+// escapePrometheusLabelValue escapes backslash, double-quote, and line feed characters
+func escapePrometheusLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
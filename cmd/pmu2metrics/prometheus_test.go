@@ -0,0 +1,42 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGetPrometheusName(t *testing.T) {
+	for in, expected := range map[string]string{
+		"CPU utilization %":                "pmu2metrics_cpu_utilization_percent",
+		"CPU operating frequency (in GHz)": "pmu2metrics_cpu_operating_frequency_in_ghz",
+		"TMA_..Core_Bound(%)":              "pmu2metrics_tma_core_bound_percent",
+		"memory bandwidth read (MB/sec)":   "pmu2metrics_memory_bandwidth_read_mb_sec",
+	} {
+		if out := getPrometheusName(in); out != expected {
+			t.Errorf("%s: expected %s, got %s", in, expected, out)
+		}
+	}
+}
+
+func TestPrometheusCacheWrite(t *testing.T) {
+	cache := NewPrometheusCache()
+	cache.Update(MetricFrame{Socket: "0", Metrics: []Metric{{Name: "CPI", Value: 1.5}, {Name: "IPC", Value: math.NaN()}}})
+	cache.Update(MetricFrame{Socket: "1", Metrics: []Metric{{Name: "CPI", Value: 2}, {Name: "IPC", Value: math.NaN()}}})
+	// replaces the earlier frame for socket 0
+	cache.Update(MetricFrame{Socket: "0", Metrics: []Metric{{Name: "CPI", Value: 0.5}, {Name: "IPC", Value: math.NaN()}}})
+	var out strings.Builder
+	if err := cache.Write(&out); err != nil {
+		t.Fatal(err)
+	}
+	expected := "# TYPE pmu2metrics_cpi gauge\n" +
+		"pmu2metrics_cpi{socket=\"0\"} 0.5\n" +
+		"pmu2metrics_cpi{socket=\"1\"} 2\n"
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+//
+// per-metric warn and crit thresholds, used to highlight metric values in live output
+//
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// MetricThreshold holds the levels at which a metric's value is highlighted, a level that isn't
+// set is not checked
+type MetricThreshold struct {
+	Warn *float64 `yaml:"warn"`
+	Crit *float64 `yaml:"crit"`
+}
+
+// MetricThresholds maps metric name to its threshold
+type MetricThresholds map[string]MetricThreshold
+
+const (
+	levelWarn = "warn"
+	levelCrit = "crit"
+)
+
+// LoadMetricThresholds reads the thresholds YAML file, e.g.,
+//
+//	CPU utilization %:
+//	  warn: 80
+//	  crit: 95
+func LoadMetricThresholds(path string) (thresholds MetricThresholds, err error) {
+	var bytes []byte
+	if bytes, err = os.ReadFile(path); err != nil {
+		return
+	}
+	var thresholdsInFile MetricThresholds
+	if err = yaml.UnmarshalStrict(bytes, &thresholdsInFile); err != nil {
+		err = fmt.Errorf("failed to parse thresholds file %s: %v", path, err)
+		return
+	}
+	thresholds = make(MetricThresholds)
+	for name, threshold := range thresholdsInFile {
+		if threshold.Warn == nil && threshold.Crit == nil {
+			err = fmt.Errorf("%s: threshold requires warn, crit, or both", name)
+			return
+		}
+		if threshold.Warn != nil && threshold.Crit != nil && *threshold.Warn > *threshold.Crit {
+			err = fmt.Errorf("%s: warn (%g) is greater than crit (%g)", name, *threshold.Warn, *threshold.Crit)
+			return
+		}
+		// metric names are shown without the "metric_" prefix, accept either
+		thresholds[strings.TrimPrefix(name, "metric_")] = threshold
+	}
+	return
+}
+
+// Level returns levelCrit or levelWarn when the metric's value exceeds the corresponding
+// threshold, otherwise an empty string
+func (t MetricThresholds) Level(name string, value float64) string {
+	threshold, ok := t[name]
+	if !ok || math.IsNaN(value) {
+		return ""
+	}
+	if threshold.Crit != nil && value > *threshold.Crit {
+		return levelCrit
+	}
+	if threshold.Warn != nil && value > *threshold.Warn {
+		return levelWarn
+	}
+	return ""
+}
+
+// colorizeLevel wraps the string in the ANSI color for the level, red for crit and yellow for warn
+func colorizeLevel(s string, level string) string {
+	switch level {
+	case levelCrit:
+		return "\033[31m" + s + "\033[0m"
+	case levelWarn:
+		return "\033[33m" + s + "\033[0m"
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadMetricThresholds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "thresholds.yaml")
+	content := "metric_CPU utilization %:\n  warn: 80\n  crit: 95\nCPI:\n  crit: 2\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	thresholds, err := LoadMetricThresholds(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name     string
+		value    float64
+		expected string
+	}{
+		{"CPU utilization %", 50, ""},
+		{"CPU utilization %", 80, ""},
+		{"CPU utilization %", 81, levelWarn},
+		{"CPU utilization %", 96, levelCrit},
+		{"CPU utilization %", math.NaN(), ""},
+		{"CPI", 1.5, ""},
+		{"CPI", 2.5, levelCrit},
+		{"IPC", 100, ""},
+	} {
+		if level := thresholds.Level(tc.name, tc.value); level != tc.expected {
+			t.Errorf("%s %g: expected \"%s\", got \"%s\"", tc.name, tc.value, tc.expected, level)
+		}
+	}
+	// warn above crit
+	if err := os.WriteFile(path, []byte("CPI:\n  warn: 3\n  crit: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadMetricThresholds(path); err == nil {
+		t.Error("expected error when warn is greater than crit")
+	}
+	// misspelled level
+	if err := os.WriteFile(path, []byte("CPI:\n  warning: 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadMetricThresholds(path); err == nil {
+		t.Error("expected error for unknown key")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/intel/svr-info/internal/core"
@@ -23,18 +24,42 @@ import (
 var resources embed.FS
 
 type CmdLineArgs struct {
-	help         bool
-	version      bool
-	format       string
-	input        string
-	output       string
-	internalJSON bool
+	help           bool
+	version        bool
+	format         string
+	input          string
+	output         string
+	internalJSON   bool
+	kernelLogLines int
+	processTop     int
+	processSort    string
+	table          string
+	anonymize      bool
+	embedRaw       bool
+	configOnly     bool
+	theme          string
+	referenceLabel string
+	splitHTML      bool
+	dimmGrid       int
+	vulnPolicy     string
+	readiness      string
+	workloadClass  string
+	pmuMetricsCSV  bool
+	validate       string
+	listTables     bool
+	baseline       string
+	lang           string
 }
 
 // globals
 var (
 	gVersion     string = "dev" // build overrides this, see makefile
 	gCmdLineArgs CmdLineArgs
+	gVulnPolicy  VulnerabilityPolicy // loaded from -vuln-policy when the arguments are validated
+	// loaded from -readiness-policy when the arguments are validated, checks that aren't in the
+	// file keep their default
+	gReadinessPolicy = defaultBenchmarkReadinessPolicy
+	gBaseline        *Source // parsed from -baseline when the arguments are validated
 )
 
 func showUsage() {
@@ -45,21 +70,52 @@ func showVersion() {
 	fmt.Println(gVersion)
 }
 
-func init() {
+// configureArgs defines, parses, and validates the command line arguments. It is called from
+// main, rather than init, so that the package can be tested.
+func configureArgs() {
 	// init command line flags
 	flag.Usage = func() { showUsage() } // override default usage output
 	flag.BoolVar(&gCmdLineArgs.help, "h", false, "Print this usage message.")
 	flag.BoolVar(&gCmdLineArgs.version, "v", false, "Print program version.")
-	flag.StringVar(&gCmdLineArgs.format, "format", "html", "comma separated list of desired report format(s):"+strings.Join(core.ReportTypes[:len(core.ReportTypes)-1], ", ")+", or all")
-	flag.StringVar(&gCmdLineArgs.input, "input", "", "required, comma separated list of input files or directory containing input (*.raw.json) files")
+	flag.StringVar(&gCmdLineArgs.format, "format", "html", "comma separated list of desired report format(s):"+strings.Join(core.ReportTypes[:len(core.ReportTypes)-1], ", ")+", csv (requires -table), dot (NUMA topology graph), or all")
+	flag.StringVar(&gCmdLineArgs.input, "input", "", "required, comma separated list of input files or directory containing input (*.raw.json, *.raw.json.gz) files")
 	flag.StringVar(&gCmdLineArgs.output, "output", ".", "output directory")
 	flag.BoolVar(&gCmdLineArgs.internalJSON, "internal_json", false, "Produce the internal json format introduced in the 2.0 release. This option is deprecated. Recommend transitioning to the new JSON report format ASAP.")
+	flag.IntVar(&gCmdLineArgs.kernelLogLines, "kernel-log-lines", 500, "maximum number of most recent kernel log entries to include in the Kernel Log table, 0 for all. The txt report always includes all entries.")
+	flag.IntVar(&gCmdLineArgs.processTop, "process-top", 20, "maximum number of processes to include in the Process table, the top processes by -process-sort, 0 for all")
+	flag.StringVar(&gCmdLineArgs.processSort, "process-sort", "cpu", "column that selects the top processes included by -process-top: cpu (%CPU) or mem (%MEM)")
+	flag.StringVar(&gCmdLineArgs.table, "table", "", "name of the table to write, one file per host, when -format csv, e.g., -format csv -table DIMM")
+	flag.BoolVar(&gCmdLineArgs.anonymize, "anonymize", false, "replace host names with host1, host2, etc., and remove serial numbers and UUIDs from the reports")
+	flag.BoolVar(&gCmdLineArgs.embedRaw, "embed-raw", false, "embed each host's raw data in its HTML report with a link to download it")
+	flag.BoolVar(&gCmdLineArgs.configOnly, "config-only", false, "produce only the configuration (and brief) reports, skipping the benchmark, profile, analyze, and insights reports, e.g., for faster reporting of inventory-only collections")
+	flag.StringVar(&gCmdLineArgs.theme, "theme", "light", "color theme of the HTML report: light or dark")
+	flag.StringVar(&gCmdLineArgs.referenceLabel, "reference-label", "", "compare all hosts to this reference data set in the HTML report's charts and tables, e.g., SPR_XCC_2, instead of the reference data for each host's microarchitecture and socket count")
+	flag.BoolVar(&gCmdLineArgs.splitHTML, "split-html", false, "write one HTML file per report (Configuration, Benchmark, Profile, etc.), linked to each other, instead of one HTML file per host, e.g., for faster loading of large reports")
+	flag.IntVar(&gCmdLineArgs.dimmGrid, "dimm-grid", 0, "render the HTML report's DIMM Population as a compact grid, one row per socket and channel, one color coded cell per slot, and a legend of the modules, for hosts with more than this number of DIMM slots, e.g., for large memory systems, 0 to always render the nested tables")
+	flag.StringVar(&gCmdLineArgs.vulnPolicy, "vuln-policy", "", "YAML file that maps each vulnerability to a substring expected in its status, e.g., CVE-2017-5753: OK, the configuration report's Vulnerability table and the insights report non-compliant vulnerabilities")
+	flag.StringVar(&gCmdLineArgs.readiness, "readiness-policy", "", "YAML file that selects the checks of the insights report's Benchmark Readiness PASS/FAIL: turbo (default true), governor (default performance), thp (default not checked), chassis (default true, no power or cooling faults), and channels (default true, all memory channels populated), e.g., thp: madvise")
+	flag.StringVar(&gCmdLineArgs.workloadClass, "workload-class", "throughput", "workload class that the insights report expects the active tuned profile to suit: throughput, latency, hpc, virtual-host, or virtual-guest, the profiles expected for each class are listed in resources/tuned_profiles.yaml")
+	flag.BoolVar(&gCmdLineArgs.pmuMetricsCSV, "pmu-metrics-csv", false, "write each host's PMU metrics time series to <host>_pmu_metrics_series.csv in the output directory, one metric,timestamp,value row per sample, e.g., for plotting")
+	flag.StringVar(&gCmdLineArgs.validate, "validate", "", "comma separated list of input files or directory containing input (*.raw.json, *.raw.json.gz) files to check against the raw data schema, reports each structural problem found and exits without generating reports")
+	flag.StringVar(&gCmdLineArgs.baseline, "baseline", "", "raw data file (*.raw.json or *.raw.json.gz) from an earlier collection. Adds a Hardware Changes table that lists the system, DIMM, disk, and NIC components added, removed, or changed since the baseline, matched by serial number or MAC address where available. Requires a single input file.")
+	flag.StringVar(&gCmdLineArgs.lang, "lang", defaultLanguage, "language of the category, table, and value names in the HTML and Excel reports: "+strings.Join(getLanguages(), ", ")+". Names without a translation, the data, and the other report formats are in English.")
+	flag.BoolVar(&gCmdLineArgs.listTables, "list-tables", false, "print the names of the tables in the reports, grouped by report and category, and exit without generating reports, e.g., to find the names used with -table")
 	flag.Parse()
 	// validate input flag arguments
 	// -format
 	if gCmdLineArgs.format != "" {
 		reportTypes := strings.Split(gCmdLineArgs.format, ",")
 		for _, reportType := range reportTypes {
+			if reportType == "csv" {
+				if gCmdLineArgs.table == "" {
+					fmt.Fprintf(os.Stderr, "-format csv : -table is required\n")
+					os.Exit(1)
+				}
+				continue
+			}
+			if reportType == "dot" {
+				continue
+			}
 			if !core.IsValidReportType(reportType) {
 				fmt.Fprintf(os.Stderr, "-report %s : invalid report type: %s\n", gCmdLineArgs.format, reportType)
 				os.Exit(1)
@@ -85,11 +141,100 @@ func init() {
 				os.Exit(1)
 			}
 		}
-	} else if !gCmdLineArgs.help && !gCmdLineArgs.version {
+	} else if !gCmdLineArgs.help && !gCmdLineArgs.version && gCmdLineArgs.validate == "" {
 		fmt.Fprintf(os.Stderr, "-input : input file list or directory is required\n")
 		showUsage()
 		os.Exit(1)
 	}
+	// -table
+	if gCmdLineArgs.table != "" && !slices.Contains(strings.Split(gCmdLineArgs.format, ","), "csv") {
+		fmt.Fprintf(os.Stderr, "-table %s : only valid with -format csv\n", gCmdLineArgs.table)
+		os.Exit(1)
+	}
+	// -reference-label
+	if gCmdLineArgs.referenceLabel != "" {
+		var labels []string
+		if referenceData := newReferenceData(); referenceData != nil {
+			for label := range *referenceData {
+				labels = append(labels, label)
+			}
+		}
+		if !slices.Contains(labels, gCmdLineArgs.referenceLabel) {
+			slices.Sort(labels)
+			fmt.Fprintf(os.Stderr, "-reference-label %s : must be one of %s\n", gCmdLineArgs.referenceLabel, strings.Join(labels, ", "))
+			os.Exit(1)
+		}
+	}
+	// -split-html
+	if gCmdLineArgs.splitHTML {
+		formats := strings.Split(gCmdLineArgs.format, ",")
+		if !slices.Contains(formats, "html") && !slices.Contains(formats, "all") {
+			fmt.Fprintf(os.Stderr, "-split-html : only valid with -format html\n")
+			os.Exit(1)
+		}
+	}
+	// -dimm-grid
+	if gCmdLineArgs.dimmGrid < 0 {
+		fmt.Fprintf(os.Stderr, "-dimm-grid %d : must be zero or a positive integer\n", gCmdLineArgs.dimmGrid)
+		os.Exit(1)
+	}
+	// -vuln-policy
+	if gCmdLineArgs.vulnPolicy != "" {
+		var err error
+		if gVulnPolicy, err = loadVulnerabilityPolicy(gCmdLineArgs.vulnPolicy); err != nil {
+			fmt.Fprintf(os.Stderr, "-vuln-policy %s : %v\n", gCmdLineArgs.vulnPolicy, err)
+			os.Exit(1)
+		}
+	}
+	// -lang
+	if _, err := loadMessageCatalog(gCmdLineArgs.lang); err != nil {
+		fmt.Fprintf(os.Stderr, "-lang %s : %v\n", gCmdLineArgs.lang, err)
+		os.Exit(1)
+	}
+	// -workload-class
+	if profiles, err := loadTunedProfiles(); err != nil {
+		fmt.Fprintf(os.Stderr, "-workload-class %s : %v\n", gCmdLineArgs.workloadClass, err)
+		os.Exit(1)
+	} else if _, ok := profiles[gCmdLineArgs.workloadClass]; !ok {
+		fmt.Fprintf(os.Stderr, "-workload-class %s : not found in tuned_profiles.yaml\n", gCmdLineArgs.workloadClass)
+		os.Exit(1)
+	}
+	// -readiness-policy
+	if gCmdLineArgs.readiness != "" {
+		var err error
+		if gReadinessPolicy, err = loadBenchmarkReadinessPolicy(gCmdLineArgs.readiness); err != nil {
+			fmt.Fprintf(os.Stderr, "-readiness-policy %s : %v\n", gCmdLineArgs.readiness, err)
+			os.Exit(1)
+		}
+	}
+	// -baseline
+	if gCmdLineArgs.baseline != "" {
+		gBaseline = newSource(gCmdLineArgs.baseline)
+		if err := gBaseline.parse(); err != nil {
+			fmt.Fprintf(os.Stderr, "-baseline %s : %v\n", gCmdLineArgs.baseline, err)
+			os.Exit(1)
+		}
+	}
+	// -theme
+	if gCmdLineArgs.theme != "light" && gCmdLineArgs.theme != "dark" {
+		fmt.Fprintf(os.Stderr, "-theme %s : must be light or dark\n", gCmdLineArgs.theme)
+		os.Exit(1)
+	}
+	// -kernel-log-lines
+	if gCmdLineArgs.kernelLogLines < 0 {
+		fmt.Fprintf(os.Stderr, "-kernel-log-lines %d : must be zero or a positive integer\n", gCmdLineArgs.kernelLogLines)
+		os.Exit(1)
+	}
+	// -process-top
+	if gCmdLineArgs.processTop < 0 {
+		fmt.Fprintf(os.Stderr, "-process-top %d : must be zero or a positive integer\n", gCmdLineArgs.processTop)
+		os.Exit(1)
+	}
+	// -process-sort
+	if _, ok := processSortColumns[gCmdLineArgs.processSort]; !ok {
+		fmt.Fprintf(os.Stderr, "-process-sort %s : must be cpu or mem\n", gCmdLineArgs.processSort)
+		os.Exit(1)
+	}
 	// -output
 	if gCmdLineArgs.output != "" {
 		path, err := util.AbsPath(gCmdLineArgs.output)
@@ -121,12 +266,14 @@ func getInputFilePaths(input string) (inputFilePaths []string, err error) {
 		if fileInfo.Mode().IsRegular() {
 			inputFilePaths = append(inputFilePaths, filename)
 		} else if fileInfo.IsDir() {
-			var matches []string
-			matches, err = filepath.Glob(filepath.Join(filename, "*.raw.json"))
-			if err != nil {
-				return
+			for _, pattern := range []string{"*.raw.json", "*.raw.json.gz"} {
+				var matches []string
+				matches, err = filepath.Glob(filepath.Join(filename, pattern))
+				if err != nil {
+					return
+				}
+				inputFilePaths = append(inputFilePaths, matches...)
 			}
-			inputFilePaths = append(inputFilePaths, matches...)
 		}
 	}
 	return
@@ -159,23 +306,74 @@ func getSources(inputFilePaths []string) (sources []*Source) {
 	return
 }
 
+// anonymizeSources gives each source a generic name, assigned in input order, so that
+// hosts line up across the reports generated in a single run
+func anonymizeSources(sources []*Source) {
+	for i, source := range sources {
+		source.anonymize(fmt.Sprintf("host%d", i+1))
+	}
+}
+
+// reporterOnlyTypes are the report types that the reporter supports but the orchestrator does not
+var reporterOnlyTypes = []string{"csv", "dot"}
+
+// getReportTypes adds the reporter-only types to the report types shared with the orchestrator
+func getReportTypes(format string) (reportTypes []string, err error) {
+	var otherTypes, reporterTypes []string
+	for _, reportType := range strings.Split(format, ",") {
+		if slices.Contains(reporterOnlyTypes, reportType) {
+			reporterTypes = append(reporterTypes, reportType)
+		} else {
+			otherTypes = append(otherTypes, reportType)
+		}
+	}
+	if len(otherTypes) > 0 {
+		reportTypes, err = core.GetReportTypes(strings.Join(otherTypes, ","))
+		if err != nil {
+			return
+		}
+	}
+	reportTypes = append(reportTypes, reporterTypes...)
+	return
+}
+
 func getReports(sources []*Source, reportTypes []string, outputDir string) (reportFilePaths []string, err error) {
 	CPUdb := cpudb.NewCPUDB()
 	if CPUdb == nil {
 		err = fmt.Errorf("failed to load CPU database")
 		return
 	}
-	configReport := NewConfigurationReport(sources, *CPUdb)
+	if gBaseline != nil && gCmdLineArgs.anonymize {
+		gBaseline.anonymize("baseline")
+	}
+	configReport := NewConfigurationReport(sources, *CPUdb, gCmdLineArgs.kernelLogLines, gCmdLineArgs.processTop, gCmdLineArgs.processSort, gVulnPolicy, gBaseline)
 	briefReport := NewBriefReport(sources, configReport, *CPUdb)
-	profileReport := NewProfileReport(sources)
-	analyzeReport := NewAnalyzeReport(sources)
-	benchmarkReport := NewBenchmarkReport(sources, *CPUdb)
-	insightsReport := NewInsightsReport(sources, configReport, briefReport, profileReport, benchmarkReport, analyzeReport, *CPUdb)
+	var profileReport, analyzeReport, benchmarkReport, insightsReport *Report
+	if gCmdLineArgs.configOnly {
+		profileReport = NewEmptyReport(sources, "Profile")
+		analyzeReport = NewEmptyReport(sources, "Analyze")
+		benchmarkReport = NewEmptyReport(sources, "Performance")
+		insightsReport = NewEmptyReport(sources, "Recommendations")
+	} else {
+		profileReport = NewProfileReport(sources)
+		analyzeReport = NewAnalyzeReport(sources)
+		benchmarkReport = NewBenchmarkReport(sources, configReport, *CPUdb)
+		insightsReport = NewInsightsReport(sources, configReport, briefReport, profileReport, benchmarkReport, analyzeReport, *CPUdb, gReadinessPolicy, gCmdLineArgs.workloadClass)
+	}
+	markPrivilegedValues(configReport)
+	if gCmdLineArgs.listTables {
+		listTables(os.Stdout, configReport, briefReport, insightsReport, profileReport, benchmarkReport, analyzeReport)
+		return
+	}
+	messages, err := loadMessageCatalog(gCmdLineArgs.lang)
+	if err != nil {
+		return
+	}
 	var rpt ReportGenerator
 	for _, rt := range reportTypes {
 		switch rt {
 		case "html":
-			rpt = newReportGeneratorHTML(outputDir, *CPUdb, configReport, insightsReport, profileReport, benchmarkReport, analyzeReport)
+			rpt = newReportGeneratorHTML(outputDir, *CPUdb, gCmdLineArgs.embedRaw, gCmdLineArgs.theme, gCmdLineArgs.referenceLabel, gCmdLineArgs.splitHTML, gCmdLineArgs.dimmGrid, messages, configReport, insightsReport, profileReport, benchmarkReport, analyzeReport)
 		case "json":
 			if gCmdLineArgs.internalJSON {
 				rpt = newReportGeneratorJSON(outputDir, configReport, insightsReport, profileReport, benchmarkReport, analyzeReport)
@@ -183,9 +381,17 @@ func getReports(sources []*Source, reportTypes []string, outputDir string) (repo
 				rpt = newReportGeneratorJSONSimplified(outputDir, configReport, briefReport, insightsReport, profileReport, benchmarkReport, analyzeReport)
 			}
 		case "xlsx":
-			rpt = newReportGeneratorXLSX(outputDir, configReport, briefReport, insightsReport, profileReport, benchmarkReport, analyzeReport) // only Excel has 'brief' report
+			rpt = newReportGeneratorXLSX(outputDir, messages, configReport, briefReport, insightsReport, profileReport, benchmarkReport, analyzeReport) // only Excel has 'brief' report
+		case "xlsx-combined":
+			rpt = newReportGeneratorXLSXCombined(outputDir, messages, configReport, insightsReport, profileReport, benchmarkReport, analyzeReport)
+		case "yaml":
+			rpt = newReportGeneratorYAML(outputDir, configReport, briefReport, insightsReport, profileReport, benchmarkReport, analyzeReport)
 		case "txt":
 			rpt = newReportGeneratorTXT(sources, outputDir) // txt report is special...more of a raw data dump than a report
+		case "csv":
+			rpt = newReportGeneratorCSV(outputDir, gCmdLineArgs.table, configReport, briefReport, insightsReport, profileReport, benchmarkReport, analyzeReport)
+		case "dot":
+			rpt = newReportGeneratorDOT(outputDir, configReport, benchmarkReport)
 		default:
 			err = fmt.Errorf("unsupported report type: %s", rt)
 			return
@@ -197,6 +403,14 @@ func getReports(sources []*Source, reportTypes []string, outputDir string) (repo
 		}
 		reportFilePaths = append(reportFilePaths, reportPaths...)
 	}
+	if gCmdLineArgs.pmuMetricsCSV {
+		var csvPaths []string
+		csvPaths, err = writePMUMetricsCSV(sources, outputDir)
+		if err != nil {
+			return
+		}
+		reportFilePaths = append(reportFilePaths, csvPaths...)
+	}
 	return
 }
 
@@ -209,6 +423,9 @@ func mainReturnWithCode() int {
 		showVersion()
 		return 0
 	}
+	if gCmdLineArgs.validate != "" {
+		return validateInputs(gCmdLineArgs.validate)
+	}
 	outputDir, err := getOutputDir(gCmdLineArgs.output)
 	if err != nil {
 		log.Printf("Error: %v", err)
@@ -238,7 +455,7 @@ func mainReturnWithCode() int {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return 1
 	}
-	reportTypes, err := core.GetReportTypes(gCmdLineArgs.format)
+	reportTypes, err := getReportTypes(gCmdLineArgs.format)
 	if err != nil {
 		log.Printf("Error: %v", err)
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -251,6 +468,21 @@ func mainReturnWithCode() int {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return 1
 	}
+	// the baseline is an earlier collection from the one input host
+	if gBaseline != nil {
+		if len(sources) > 1 {
+			err = fmt.Errorf("-baseline : requires a single input file, found %d", len(sources))
+			log.Printf("Error: %v", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if gBaseline.getHostname() != sources[0].getHostname() {
+			log.Printf("Warning: baseline host %s differs from input host %s", gBaseline.getHostname(), sources[0].getHostname())
+		}
+	}
+	if gCmdLineArgs.anonymize {
+		anonymizeSources(sources)
+	}
 	reportFilePaths, err := getReports(sources, reportTypes, outputDir)
 	if err != nil {
 		log.Printf("Error: %v", err)
@@ -264,4 +496,39 @@ func mainReturnWithCode() int {
 	return 0
 }
 
-func main() { os.Exit(mainReturnWithCode()) }
+// validateInputs checks each input file against the raw data schema, returns 1 if any of the
+// files are invalid
+func validateInputs(input string) int {
+	inputFilePaths, err := getInputFilePaths(input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(inputFilePaths) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no input files found\n")
+		return 1
+	}
+	exitCode := 0
+	for _, inputFilePath := range inputFilePaths {
+		problems, err := validateInputFile(inputFilePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", inputFilePath, err)
+			exitCode = 1
+			continue
+		}
+		if len(problems) == 0 {
+			fmt.Printf("%s: valid\n", inputFilePath)
+			continue
+		}
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", inputFilePath, problem)
+		}
+		exitCode = 1
+	}
+	return exitCode
+}
+
+func main() {
+	configureArgs()
+	os.Exit(mainReturnWithCode())
+}
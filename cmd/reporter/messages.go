@@ -0,0 +1,102 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+// translated presentation labels of the HTML and Excel reports, see -lang
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// defaultLanguage is the language of the table and value names, it has no message catalog
+const defaultLanguage = "en"
+
+// MessageCatalog maps the English category, table, and value names to their translations. Names
+// that aren't in the catalog are shown in English. A nil catalog shows all names in English.
+type MessageCatalog struct {
+	Categories map[string]string `yaml:"categories"`
+	Tables     map[string]string `yaml:"tables"`
+	Values     map[string]string `yaml:"values"`
+}
+
+// getLanguages returns the languages that have a message catalog in resources/locales, and
+// the default language
+func getLanguages() (languages []string) {
+	languages = append(languages, defaultLanguage)
+	entries, err := resources.ReadDir("resources/locales")
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if lang, found := strings.CutSuffix(entry.Name(), ".yaml"); found {
+			languages = append(languages, lang)
+		}
+	}
+	slices.Sort(languages)
+	return
+}
+
+// loadMessageCatalog reads the language's message catalog, returns nil for the default language
+func loadMessageCatalog(lang string) (catalog *MessageCatalog, err error) {
+	if lang == defaultLanguage {
+		return
+	}
+	if !slices.Contains(getLanguages(), lang) {
+		err = fmt.Errorf("must be one of %s", strings.Join(getLanguages(), ", "))
+		return
+	}
+	yamlBytes, err := resources.ReadFile(path.Join("resources/locales", lang+".yaml"))
+	if err != nil {
+		return
+	}
+	catalog = &MessageCatalog{}
+	err = yaml.UnmarshalStrict(yamlBytes, catalog)
+	return
+}
+
+// translate returns the translation of the name, or the name when it has no translation
+func translate(messages map[string]string, name string) string {
+	if translation, ok := messages[name]; ok && translation != "" {
+		return translation
+	}
+	return name
+}
+
+// Category returns the translated label of the table category
+func (m *MessageCatalog) Category(category TableCategory) string {
+	if m == nil {
+		return TableCategoryLabels[category]
+	}
+	return translate(m.Categories, TableCategoryLabels[category])
+}
+
+// Table returns the translated table name
+func (m *MessageCatalog) Table(name string) string {
+	if m == nil {
+		return name
+	}
+	return translate(m.Tables, name)
+}
+
+// Value returns the translated value name
+func (m *MessageCatalog) Value(name string) string {
+	if m == nil {
+		return name
+	}
+	return translate(m.Values, name)
+}
+
+// ValueList returns a copy of the value names, translated
+func (m *MessageCatalog) ValueList(names []string) (translated []string) {
+	for _, name := range names {
+		translated = append(translated, m.Value(name))
+	}
+	return
+}
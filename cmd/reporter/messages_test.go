@@ -0,0 +1,35 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import "testing"
+
+func TestMessageCatalog(t *testing.T) {
+	// every catalog must load
+	for _, lang := range getLanguages() {
+		if _, err := loadMessageCatalog(lang); err != nil {
+			t.Fatalf("%s: %v", lang, err)
+		}
+	}
+	if _, err := loadMessageCatalog("xx"); err == nil {
+		t.Fatal("expected error for unknown language")
+	}
+	// default language has no catalog, names are in English
+	var english *MessageCatalog
+	if english.Category(Memory) != "Memory" || english.Table("DIMM") != "DIMM" || english.Value("Serial #") != "Serial #" {
+		t.Fatal("expected English names from nil catalog")
+	}
+	german, err := loadMessageCatalog("de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if german.Category(Memory) != "Arbeitsspeicher" || german.Table("Baseboard") != "Mainboard" || german.Value("Serial #") != "Seriennummer" {
+		t.Fatal("expected German names")
+	}
+	// names without a translation fall back to English
+	if german.Table("Uncore") != "Uncore" {
+		t.Fatalf("expected fallback to English, got %s", german.Table("Uncore"))
+	}
+}
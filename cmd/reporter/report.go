@@ -7,7 +7,10 @@
 package main
 
 import (
+	"fmt"
+	"io"
 	"log"
+	"slices"
 
 	"github.com/intel/svr-info/internal/cpudb"
 )
@@ -19,25 +22,48 @@ type Report struct {
 	Tables       []*Table
 }
 
+// NewEmptyReport -- a report without tables, stands in for the reports that weren't requested
+func NewEmptyReport(sources []*Source, internalName string) (report *Report) {
+	report = &Report{
+		InternalName: internalName,
+		Sources:      sources,
+		Tables:       []*Table{},
+	}
+	return
+}
+
 // NewConfigurationReport -- includes all verbose tables
-func NewConfigurationReport(sources []*Source, CPUdb cpudb.CPUDB) (report *Report) {
+func NewConfigurationReport(sources []*Source, CPUdb cpudb.CPUDB, kernelLogLines int, processTop int, processSort string, vulnPolicy VulnerabilityPolicy, baseline *Source) (report *Report) {
 	report = &Report{
 		InternalName: "Configuration",
 		Sources:      sources,
 		Tables:       []*Table{},
 	}
 
+	tablePCIeLink := newPCIeLinkTable(sources, System)
+	tableSystem := newSystemTable(sources, System)
+
 	report.Tables = append(report.Tables,
 		[]*Table{
 			newHostTable(sources, System),
-			newSystemTable(sources, System),
+			tableSystem,
 			newBaseboardTable(sources, System),
 			newChassisTable(sources, System),
 			newPCIeSlotsTable(sources, System),
+			newPCIeLinkSummaryTable(tablePCIeLink, System),
+			tablePCIeLink,
+			newIOMMUTable(sources, System),
+			newSRIOVTable(sources, System),
+			newBMCTable(sources, System),
 
 			newBIOSTable(sources, Software),
+			newBIOSSettingsTable(sources, Software),
 			newOperatingSystemTable(sources, Software),
 			newSoftwareTable(sources, Software),
+			newServicesTable(sources, Software),
+			newContainersTable(sources, Software),
+			newKernelModulesTable(sources, Software),
+			newTunedTable(sources, Software),
 
 			newCPUTable(sources, CPUdb, CPUCategory),
 			newISATable(sources, CPUCategory),
@@ -46,40 +72,53 @@ func NewConfigurationReport(sources []*Source, CPUdb cpudb.CPUDB) (report *Repor
 			newPowerTable(sources, Power),
 			newUncoreTable(sources, CPUdb, Power),
 			newEfficiencyLatencyControlTable(sources, Power),
+			newSSTTable(sources, Power),
 		}...,
 	)
 
 	tableDIMM := newDIMMTable(sources, Memory)
 	tableDIMMPopulation := newDIMMPopulationTable(sources, tableDIMM, CPUdb, Memory)
+	tableNIC := newNICTable(sources, Network)
+	tableDisk := newDiskTable(sources, Storage)
 
 	report.Tables = append(report.Tables,
 		[]*Table{
 			newMemoryTable(sources, tableDIMM, tableDIMMPopulation, Memory),
+			newHugepagesTable(sources, Memory),
+			newNUMAStatsTable(sources, Memory),
 			tableDIMMPopulation,
+			newDIMMPopulationBalanceTable(sources, tableDIMMPopulation, Memory),
 			tableDIMM,
 
-			newNICTable(sources, Network),
+			tableNIC,
 			newNetworkIRQTable(sources, Network),
+			newNICQueueStatsTable(sources, Network),
 
-			newDiskTable(sources, Storage),
+			tableDisk,
+			newNVMeHealthTable(sources, Storage),
 			newFilesystemTable(sources, Storage),
 
 			newGPUTable(sources, GPU),
 			newGaudiTable(sources, GPU),
 
 			newCXLDeviceTable(sources, CXL),
+			newCXLMemoryDeviceTable(sources, CXL),
 
-			newVulnerabilityTable(sources, Security),
+			newVulnerabilityTable(sources, vulnPolicy, Security),
 
-			newProcessTable(sources, Status),
+			newProcessTable(sources, processTop, processSort, Status),
 			newSensorTable(sources, Status),
 			newChassisStatusTable(sources, Status),
 			newSystemEventLogTable(sources, Status),
-			newKernelLogTable(sources, Status),
+			newKernelLogTable(sources, kernelLogLines, Status),
 			newPMUTable(sources, Status),
 			newSvrinfoTable(sources, Status),
+			newCollectionTimingTable(sources, Status),
 		}...,
 	)
+	if baseline != nil {
+		report.Tables = append(report.Tables, newHardwareChangesTable(tableSystem, tableDIMM, tableDisk, tableNIC, baseline, System))
+	}
 	// TODO: remove check when code is stable
 	for _, table := range report.Tables {
 		check(table, sources)
@@ -87,6 +126,75 @@ func NewConfigurationReport(sources []*Source, CPUdb cpudb.CPUDB) (report *Repor
 	return
 }
 
+// privilegedValue replaces the values that weren't collected because the collector couldn't
+// elevate its privileges, see markPrivilegedValues
+const privilegedValue = "requires elevated privileges"
+
+// privilegedTableCommands are the superuser commands that provide the data in the Configuration
+// report's tables. Tables that combine superuser and regular commands' data, e.g., CPU, aren't
+// included, their empty values may not be due to privileges.
+var privilegedTableCommands = map[string][]string{
+	"System":                     {"dmidecode"},
+	"Baseboard":                  {"dmidecode"},
+	"Chassis":                    {"dmidecode"},
+	"PCIe Slots":                 {"dmidecode"},
+	"BIOS":                       {"dmidecode"},
+	"BIOS Settings":              {"bios settings"},
+	"IOMMU":                      {"iommu groups"},
+	"Containers":                 {"containers"},
+	"Uncore":                     {"lspci bits", "uncore cha count", "uncore cha count spr", "uncore client cha count", "uncore max frequency", "uncore max frequency tpmi", "uncore min frequency", "uncore min frequency tpmi"},
+	"Efficiency Latency Control": {"efficiency latency control"},
+	"SST-PP":                     {"intel-speed-select perf-profile current level", "intel-speed-select perf-profile info"},
+	"DIMM Population":            {"dmidecode"},
+	"DIMM":                       {"dmidecode"},
+	"NIC":                        {"lshw"},
+	"Network IRQ Mapping":        {"lshw"},
+	"NIC Queue Stats":            {"lshw", "ethtool -S"},
+	"NVMe Health":                {"nvme list", "nvme smart-log"},
+	"GPU":                        {"lshw"},
+	"Gaudi":                      {"gaudi info"},
+	"CXL Memory Device":          {"cxl list"},
+	"Vulnerability":              {"spectre-meltdown-checker"},
+	"Sensor":                     {"ipmitool sdr list full"},
+	"Chassis Status":             {"ipmitool chassis status", "ipmitool sel time get"},
+	"System Event Log":           {"ipmitool sel elist"},
+	"Kernel Log":                 {"dmesg"},
+	"PMU":                        {"msrbusy", "pmu driver version"},
+}
+
+// markPrivilegedValues replaces the empty values in the Configuration report's tables with
+// privilegedValue, for the hosts where the table's superuser commands were skipped, so that
+// missing data isn't mistaken for data that isn't present. A table without values gets one
+// record. Called after all reports are created, so that the reports are derived from the
+// collected data.
+func markPrivilegedValues(report *Report) {
+	for _, table := range report.Tables {
+		cmdLabels, ok := privilegedTableCommands[table.Name]
+		if !ok {
+			continue
+		}
+		for sourceIdx, source := range report.Sources {
+			if !slices.ContainsFunc(cmdLabels, source.commandRequiredPrivileges) {
+				continue
+			}
+			hv := &table.AllHostValues[sourceIdx]
+			if len(hv.Values) == 0 && len(hv.ValueNames) > 0 {
+				record := make([]string, len(hv.ValueNames))
+				record[0] = privilegedValue
+				hv.Values = append(hv.Values, record)
+				continue
+			}
+			for _, record := range hv.Values {
+				for i := range record {
+					if record[i] == "" {
+						record[i] = privilegedValue
+					}
+				}
+			}
+		}
+	}
+}
+
 func NewBriefReport(sources []*Source, fullReport *Report, CPUdb cpudb.CPUDB) (report *Report) {
 	report = &Report{
 		InternalName: "Brief",
@@ -123,7 +231,7 @@ func NewBriefReport(sources []*Source, fullReport *Report, CPUdb cpudb.CPUDB) (r
 	return
 }
 
-func NewInsightsReport(sources []*Source, configReport, briefReport, profileReport, benchmarkReport *Report, analyzeReport *Report, CPUdb cpudb.CPUDB) (report *Report) {
+func NewInsightsReport(sources []*Source, configReport, briefReport, profileReport, benchmarkReport *Report, analyzeReport *Report, CPUdb cpudb.CPUDB, readinessPolicy BenchmarkReadinessPolicy, workloadClass string) (report *Report) {
 	report = &Report{
 		InternalName: "Recommendations",
 		Sources:      sources,
@@ -131,7 +239,7 @@ func NewInsightsReport(sources []*Source, configReport, briefReport, profileRepo
 	}
 	report.Tables = append(report.Tables,
 		[]*Table{
-			newInsightTable(configReport, briefReport, profileReport, benchmarkReport, analyzeReport),
+			newInsightTable(configReport, briefReport, profileReport, benchmarkReport, analyzeReport, readinessPolicy, workloadClass),
 		}...,
 	)
 	// TODO: remove check when code is stable
@@ -155,6 +263,7 @@ func NewProfileReport(sources []*Source) (report *Report) {
 	memStatsTable := newMemoryStatsTable(sources, NoCategory)
 	PMUMetricsTable := newPMUMetricsTable(sources, NoCategory)
 	powerStatsTable := newPowerStatsTable(sources, NoCategory)
+	cStateResidencyTable := newCStateResidencyTable(sources, NoCategory)
 	summaryTable := newProfileSummaryTable(sources, NoCategory, averageCPUUtilizationTable, driveStatsTable, netStatsTable, memStatsTable, PMUMetricsTable, powerStatsTable)
 	report.Tables = append(report.Tables,
 		[]*Table{
@@ -162,6 +271,7 @@ func NewProfileReport(sources []*Source) (report *Report) {
 			averageCPUUtilizationTable,
 			CPUUtilizationTable,
 			powerStatsTable,
+			cStateResidencyTable,
 			IRQRateTable,
 			driveStatsTable,
 			netStatsTable,
@@ -185,6 +295,7 @@ func NewAnalyzeReport(sources []*Source) (report *Report) {
 	report.Tables = append(report.Tables,
 		[]*Table{
 			newCodePathTable(sources, NoCategory),
+			newCacheContentionTable(sources, NoCategory),
 		}...,
 	)
 	// TODO: remove check when code is stable
@@ -194,7 +305,7 @@ func NewAnalyzeReport(sources []*Source) (report *Report) {
 	return
 }
 
-func NewBenchmarkReport(sources []*Source, CPUdb cpudb.CPUDB) (report *Report) {
+func NewBenchmarkReport(sources []*Source, configReport *Report, CPUdb cpudb.CPUDB) (report *Report) {
 	report = &Report{
 		InternalName: "Performance",
 		Sources:      sources,
@@ -203,7 +314,7 @@ func NewBenchmarkReport(sources []*Source, CPUdb cpudb.CPUDB) (report *Report) {
 	tableMemBandwidthLatency := newMemoryBandwidthLatencyTable(sources, NoCategory)
 	report.Tables = append(report.Tables,
 		[]*Table{
-			newBenchmarkSummaryTable(sources, tableMemBandwidthLatency, NoCategory),
+			newBenchmarkSummaryTable(sources, tableMemBandwidthLatency, configReport.findTable("DIMM"), configReport.findTable("Memory"), NoCategory),
 			newFrequencyTable(sources, CPUdb, NoCategory),
 			tableMemBandwidthLatency,
 			newMemoryNUMABandwidthTable(sources, NoCategory),
@@ -243,6 +354,36 @@ func check(table *Table, sources []*Source) {
 	}
 }
 
+// listTables writes the names of the reports' tables, grouped by report and category, reports
+// without tables are skipped
+func listTables(w io.Writer, reports ...*Report) {
+	for _, report := range reports {
+		if len(report.Tables) == 0 {
+			continue
+		}
+		fmt.Fprintln(w, report.InternalName)
+		for category := System; category <= NoCategory; category++ {
+			var names []string
+			for _, table := range report.Tables {
+				if table != nil && table.Category == category {
+					names = append(names, table.Name)
+				}
+			}
+			if len(names) == 0 {
+				continue
+			}
+			indent := "  "
+			if category != NoCategory {
+				fmt.Fprintf(w, "  %s\n", TableCategoryLabels[category])
+				indent = "    "
+			}
+			for _, name := range names {
+				fmt.Fprintf(w, "%s%s\n", indent, name)
+			}
+		}
+	}
+}
+
 func (r *Report) findTable(name string) (table *Table) {
 	for _, t := range r.Tables {
 		if t.Name == name {
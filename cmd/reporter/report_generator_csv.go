@@ -0,0 +1,112 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ReportGeneratorCSV writes a single table, one file per host, e.g., for pasting into a spreadsheet
+type ReportGeneratorCSV struct {
+	reports   []*Report
+	tableName string
+	outputDir string
+}
+
+func newReportGeneratorCSV(outputDir string, tableName string, configurationData *Report, briefReport *Report, insightReport *Report, profileReport *Report, benchmarkReport *Report, analyzeReport *Report) (rpt *ReportGeneratorCSV) {
+	rpt = &ReportGeneratorCSV{
+		reports:   []*Report{configurationData, briefReport, insightReport, profileReport, benchmarkReport, analyzeReport},
+		tableName: tableName,
+		outputDir: outputDir,
+	}
+	return
+}
+
+func (r *ReportGeneratorCSV) findTable() (table *Table) {
+	for _, report := range r.reports {
+		table = report.findTable(r.tableName)
+		if table != nil {
+			return
+		}
+	}
+	return
+}
+
+func (r *ReportGeneratorCSV) generate() (reportFilePaths []string, err error) {
+	table := r.findTable()
+	if table == nil {
+		err = fmt.Errorf("table not found: %s", r.tableName)
+		return
+	}
+	// file names can't include path separators
+	tableFileName := strings.NewReplacer(" ", "_", "/", "_").Replace(table.Name)
+	for _, hv := range table.AllHostValues {
+		reportFilePath := filepath.Join(r.outputDir, hv.Name+"_"+tableFileName+".csv")
+		var f *os.File
+		f, err = os.OpenFile(reportFilePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return
+		}
+		w := csv.NewWriter(f)
+		// a row of value names followed by one row per record
+		err = w.Write(hv.ValueNames)
+		if err == nil {
+			err = w.WriteAll(hv.Values)
+		}
+		f.Close()
+		if err != nil {
+			return
+		}
+		reportFilePaths = append(reportFilePaths, reportFilePath)
+	}
+	return
+}
+
+// writePMUMetricsCSV writes each host's PMU metrics time series to a CSV file in tidy format, i.e.,
+// one row per metric and timestamp, e.g., for plotting. Hosts without PMU metrics are skipped.
+func writePMUMetricsCSV(sources []*Source, outputDir string) (filePaths []string, err error) {
+	for _, source := range sources {
+		metricNames, timeStamps, metrics := source.getPMUMetrics()
+		if len(metrics) == 0 {
+			continue
+		}
+		filePath := filepath.Join(outputDir, source.getHostname()+"_pmu_metrics_series.csv")
+		var f *os.File
+		f, err = os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return
+		}
+		w := csv.NewWriter(f)
+		err = w.Write([]string{"metric", "timestamp", "value"})
+		for _, name := range metricNames {
+			for i, val := range metrics[name].series {
+				if err != nil || i >= len(timeStamps) {
+					break
+				}
+				value := strconv.FormatFloat(val, 'f', -1, 64)
+				if math.IsNaN(val) {
+					value = ""
+				}
+				err = w.Write([]string{name, strconv.FormatFloat(timeStamps[i], 'f', -1, 64), value})
+			}
+		}
+		if err == nil {
+			w.Flush()
+			err = w.Error()
+		}
+		f.Close()
+		if err != nil {
+			return
+		}
+		filePaths = append(filePaths, filePath)
+	}
+	return
+}
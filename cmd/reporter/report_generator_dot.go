@@ -0,0 +1,133 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ReportGeneratorDOT writes each host's NUMA topology as a Graphviz DOT graph, render with,
+// e.g., dot -Tpng host.dot -o host.png
+type ReportGeneratorDOT struct {
+	tableCPU           *Table
+	tableNUMABandwidth *Table
+	outputDir          string
+}
+
+func newReportGeneratorDOT(outputDir string, configurationReport *Report, benchmarkReport *Report) (rpt *ReportGeneratorDOT) {
+	rpt = &ReportGeneratorDOT{
+		tableCPU:           configurationReport.findTable("CPU"),
+		tableNUMABandwidth: benchmarkReport.findTable("Memory NUMA Bandwidth"),
+		outputDir:          outputDir,
+	}
+	return
+}
+
+// getNUMABandwidths returns the bandwidth matrix, [from node][to node], in MB/s
+func (r *ReportGeneratorDOT) getNUMABandwidths(sourceIdx int) (bandwidths [][]string) {
+	if r.tableNUMABandwidth == nil {
+		return
+	}
+	for _, row := range r.tableNUMABandwidth.AllHostValues[sourceIdx].Values {
+		bandwidths = append(bandwidths, strings.Split(row[1], ","))
+	}
+	return
+}
+
+// renderTopology creates the DOT graph for one host. Sockets are clusters that contain their
+// NUMA nodes. Nodes are assigned to sockets in order, e.g., nodes 0 and 1 on socket 0 and nodes
+// 2 and 3 on socket 1 when there are two sockets and four nodes, which matches how Linux
+// numbers the sub-NUMA clusters. When the memory bandwidth was measured, an edge from node A
+// to node B is labeled with the bandwidth of CPUs on node A reading memory on node B.
+func (r *ReportGeneratorDOT) renderTopology(sourceIdx int) (out string, err error) {
+	hostName := r.tableCPU.AllHostValues[sourceIdx].Name
+	var cpuModel, socketsVal, numaCPUList string
+	if cpuModel, err = r.tableCPU.getValue(sourceIdx, "CPU Model"); err != nil {
+		return
+	}
+	if socketsVal, err = r.tableCPU.getValue(sourceIdx, "Sockets"); err != nil {
+		return
+	}
+	if numaCPUList, err = r.tableCPU.getValue(sourceIdx, "NUMA CPU List"); err != nil {
+		return
+	}
+	sockets, err := strconv.Atoi(socketsVal)
+	if err != nil {
+		err = fmt.Errorf("unknown socket count: %s", socketsVal)
+		return
+	}
+	var nodeCPUs []string
+	if numaCPUList != "" {
+		nodeCPUs = strings.Split(numaCPUList, " :: ")
+	}
+	bandwidths := r.getNUMABandwidths(sourceIdx)
+	nodeLabel := func(node int) string {
+		label := fmt.Sprintf("NUMA Node %d\\nCPUs: %s", node, nodeCPUs[node])
+		if node < len(bandwidths) && node < len(bandwidths[node]) {
+			label += fmt.Sprintf("\\nLocal Memory: %s MB/s", bandwidths[node][node])
+		}
+		return label
+	}
+	out = fmt.Sprintf("digraph %q {\n", hostName)
+	graphLabel := hostName
+	if cpuModel != "" {
+		graphLabel += "\n" + cpuModel
+	}
+	out += fmt.Sprintf("  label=%q;\n", graphLabel)
+	out += "  labelloc=t;\n"
+	out += "  node [shape=box];\n"
+	if len(nodeCPUs) >= sockets && len(nodeCPUs)%sockets == 0 {
+		nodesPerSocket := len(nodeCPUs) / sockets
+		for socket := 0; socket < sockets; socket++ {
+			out += fmt.Sprintf("  subgraph cluster_socket%d {\n", socket)
+			out += fmt.Sprintf("    label=\"Socket %d\";\n", socket)
+			for node := socket * nodesPerSocket; node < (socket+1)*nodesPerSocket; node++ {
+				out += fmt.Sprintf("    node%d [label=\"%s\"];\n", node, nodeLabel(node))
+			}
+			out += "  }\n"
+		}
+	} else { // can't assign nodes to sockets
+		for socket := 0; socket < sockets; socket++ {
+			out += fmt.Sprintf("  socket%d [label=\"Socket %d\", shape=ellipse];\n", socket, socket)
+		}
+		for node := range nodeCPUs {
+			out += fmt.Sprintf("  node%d [label=\"%s\"];\n", node, nodeLabel(node))
+		}
+	}
+	for from := range bandwidths {
+		for to, bandwidth := range bandwidths[from] {
+			if from == to || from >= len(nodeCPUs) || to >= len(nodeCPUs) {
+				continue
+			}
+			out += fmt.Sprintf("  node%d -> node%d [label=\"%s MB/s\"];\n", from, to, bandwidth)
+		}
+	}
+	out += "}\n"
+	return
+}
+
+func (r *ReportGeneratorDOT) generate() (reportFilePaths []string, err error) {
+	if r.tableCPU == nil {
+		err = fmt.Errorf("table not found: CPU")
+		return
+	}
+	for sourceIdx, hv := range r.tableCPU.AllHostValues {
+		var out string
+		if out, err = r.renderTopology(sourceIdx); err != nil {
+			err = fmt.Errorf("failed to create topology graph for %s: %v", hv.Name, err)
+			return
+		}
+		reportFilePath := filepath.Join(r.outputDir, hv.Name+".dot")
+		if err = os.WriteFile(reportFilePath, []byte(out), 0644); err != nil {
+			return
+		}
+		reportFilePaths = append(reportFilePaths, reportFilePath)
+	}
+	return
+}
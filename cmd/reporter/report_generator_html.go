@@ -7,14 +7,17 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"html"
 	"html/template"
 	"log"
 	"math"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -39,13 +42,52 @@ type ReportGeneratorHTML struct {
 	reports   []*Report
 	outputDir string
 	CPUdb     cpudb.CPUDB
+	embedRaw  bool
+	theme     string
+	refLabel  string // when set, overrides each host's reference data label
+	split     bool   // when set, write one HTML file per report, e.g., Configuration, Profile
+	dimmGrid  int    // when set, render the DIMM Population of hosts with more DIMM slots as a grid
+	messages  *MessageCatalog
 }
 
-func newReportGeneratorHTML(outputDir string, CPUdb cpudb.CPUDB, configurationData *Report, insightData *Report, profileData *Report, benchmarkData *Report, analyzeData *Report) (rpt *ReportGeneratorHTML) {
+func newReportGeneratorHTML(outputDir string, CPUdb cpudb.CPUDB, embedRaw bool, theme string, refLabel string, split bool, dimmGrid int, messages *MessageCatalog, configurationData *Report, insightData *Report, profileData *Report, benchmarkData *Report, analyzeData *Report) (rpt *ReportGeneratorHTML) {
 	rpt = &ReportGeneratorHTML{
 		reports:   []*Report{configurationData, benchmarkData, profileData, analyzeData, insightData}, // order matches const indexes defined above
 		outputDir: outputDir,
 		CPUdb:     CPUdb,
+		embedRaw:  embedRaw,
+		theme:     theme,
+		refLabel:  refLabel,
+		split:     split,
+		dimmGrid:  dimmGrid,
+		messages:  messages,
+	}
+	return
+}
+
+// RawData - a host's raw data, embedded in the HTML report as a downloadable data URI
+type RawData struct {
+	Name     string
+	FileName string
+	URI      template.URL
+}
+
+// getRawData returns the embeddable raw data for the specified hosts
+func (r *ReportGeneratorHTML) getRawData(hostIndices []int) (rawData []RawData, err error) {
+	if !r.embedRaw {
+		return
+	}
+	for _, hostIndex := range hostIndices {
+		source := r.reports[0].Sources[hostIndex]
+		var rawJSON []byte
+		if rawJSON, err = source.getRawJSON(); err != nil {
+			return
+		}
+		rawData = append(rawData, RawData{
+			Name:     source.getHostname(),
+			FileName: source.getHostname() + ".raw.json",
+			URI:      template.URL("data:application/json;base64," + base64.StdEncoding.EncodeToString(rawJSON)),
+		})
 	}
 	return
 }
@@ -57,22 +99,45 @@ type ReportWithMore struct {
 	RefData []*HostReferenceData
 }
 
+// Page - a link to one of the HTML files of a split report
+type Page struct {
+	Name     string
+	FileName string
+	Current  bool
+}
+
 // ReportGen - struct used within the HTML template
 type ReportGen struct {
 	HostIndices []int
 	Reports     []*ReportWithMore
+	RawData     []RawData
+	Theme       string // "light" or "dark"
+	Pages       []Page // links to the other files of a split report, replaces the tabs when not empty
+	Messages    *MessageCatalog
+	DIMMGrid    int // DIMM slot count above which the DIMM Population is rendered as a grid, 0 for never
 }
 
-func newReportGen(reportsData []*Report, hostIndices []int, hostsReferenceData []*HostReferenceData) (gen *ReportGen) {
+func newReportGen(reportsData []*Report, hostIndices []int, hostsReferenceData []*HostReferenceData, rawData []RawData, theme string, messages *MessageCatalog, dimmGrid int) (gen *ReportGen) {
 	namedReports := []*ReportWithMore{}
 	namedReports = append(namedReports, &ReportWithMore{Report: *reportsData[configurationDataIndex], Name: "Configuration", Notes: []string{""}})
 	namedReports = append(namedReports, &ReportWithMore{Report: *reportsData[benchmarkDataIndex], Name: "Benchmark", Notes: []string{"Use the \"-benchmark all\" option to collect all micro-benchmarking data. See \"-help\" for finer control."}, RefData: hostsReferenceData})
 	namedReports = append(namedReports, &ReportWithMore{Report: *reportsData[profileDataIndex], Name: "Profile", Notes: []string{"Use the \"-profile all\" option to collect all system profiling data. See \"-help\" for finer control."}})
-	namedReports = append(namedReports, &ReportWithMore{Report: *reportsData[analyzeDataIndex], Name: "Analyze", Notes: []string{"Use the \"-analyze all\" option to collect all analysis data. See \"-help\" for finer control.", "Note: Perl is required on the target machine to collapse the call stacks used to produce System Flame Graphs."}})
+	namedReports = append(namedReports, &ReportWithMore{Report: *reportsData[analyzeDataIndex], Name: "Analyze", Notes: []string{"Use the \"-analyze all\" option to collect all analysis data. See \"-help\" for finer control.", "Note: Perl is required on the target machine to collapse the call stacks used to produce System Flame Graphs.", "Use the \"-c2c\" option to collect cache line contention data."}})
 	namedReports = append(namedReports, &ReportWithMore{Report: *reportsData[insightDataIndex], Name: "Insights", Notes: []string{"Insights are derived from data collected by Intel® System Health Inspector. They are provided for consideration but may not always be relevant."}})
+	// skip the reports that weren't requested, i.e., the empty reports, but always show configuration
+	var reports []*ReportWithMore
+	for i, report := range namedReports {
+		if i == configurationDataIndex || len(report.Tables) > 0 {
+			reports = append(reports, report)
+		}
+	}
 	gen = &ReportGen{
 		HostIndices: hostIndices,
-		Reports:     namedReports,
+		Reports:     reports,
+		RawData:     rawData,
+		Theme:       theme,
+		Messages:    messages,
+		DIMMGrid:    dimmGrid,
 	}
 	return
 }
@@ -96,14 +161,9 @@ func newReferenceData() (data *ReferenceData) {
 
 func (r *ReportGeneratorHTML) getRefLabel(hostIndex int) (refLabel string) {
 	source := r.reports[0].Sources[hostIndex]
-	family := source.valFromRegexSubmatch("lscpu", `^CPU family.*:\s*([0-9]+)$`)
-	model := source.valFromRegexSubmatch("lscpu", `^Model.*:\s*([0-9]+)$`)
-	stepping := source.valFromRegexSubmatch("lscpu", `^Stepping.*:\s*(.+)$`)
 	sockets := source.valFromRegexSubmatch("lscpu", `^Socket\(.*:\s*(.+?)$`)
-	capid4 := source.valFromRegexSubmatch("lspci bits", `^([0-9a-fA-F]+)`)
-	devices := source.valFromRegexSubmatch("lspci devices", `^([0-9]+)`)
 	var uarch string
-	cpu, err := r.CPUdb.GetCPU(family, model, stepping, capid4, sockets, devices)
+	cpu, err := getCPU(r.CPUdb, source)
 	if err != nil {
 		log.Printf("%v", err)
 		return
@@ -115,7 +175,10 @@ func (r *ReportGeneratorHTML) getRefLabel(hostIndex int) (refLabel string) {
 }
 
 func (r *ReportGeneratorHTML) loadHostReferenceData(hostIndex int, referenceData *ReferenceData) (data *HostReferenceData) {
-	refLabel := r.getRefLabel(hostIndex)
+	refLabel := r.refLabel
+	if refLabel == "" {
+		refLabel = r.getRefLabel(hostIndex)
+	}
 	if refLabel == "" {
 		log.Printf("No reference data found for host %d", hostIndex)
 		return
@@ -129,15 +192,33 @@ func (r *ReportGeneratorHTML) loadHostReferenceData(hostIndex int, referenceData
 	return
 }
 
+// tableAnchor returns a URL-safe link to the table's heading, see RenderDataTable
+func tableAnchor(tableName string) string {
+	return "#" + html.EscapeString(url.PathEscape(tableName))
+}
+
+// RenderMenuItems renders a nested list with a link to each category and, below each
+// category, a link to each of its tables
 func (r *ReportGen) RenderMenuItems(reportData *ReportWithMore) template.HTML {
 	var out string
 	category := NoCategory
-	for _, table := range reportData.Tables {
-		if table.Category != category {
-			out += fmt.Sprintf(`<a href="#%s">%s</a>`, table.Name, TableCategoryLabels[table.Category])
+	out += `<ul>`
+	for tableIdx, table := range reportData.Tables {
+		if table.Category != category || tableIdx == 0 {
+			if tableIdx != 0 && category != NoCategory {
+				out += `</ul></li>`
+			}
 			category = table.Category
+			if category != NoCategory {
+				out += fmt.Sprintf(`<li><a href="%s">%s</a><ul>`, tableAnchor(table.Name), html.EscapeString(r.Messages.Category(category)))
+			}
 		}
+		out += fmt.Sprintf(`<li><a class="menutable" href="%s">%s</a></li>`, tableAnchor(table.Name), html.EscapeString(r.Messages.Table(table.Name)))
 	}
+	if len(reportData.Tables) > 0 && category != NoCategory {
+		out += `</ul></li>`
+	}
+	out += `</ul>`
 	return template.HTML(out)
 }
 
@@ -217,7 +298,7 @@ func (r *ReportGen) renderSingleValueTable(table *Table, refData []*HostReferenc
 	for valueIndex, valueName := range table.AllHostValues[r.HostIndices[0]].ValueNames {
 		var rowValues []string
 		// first column in row is the value name
-		rowValues = append(rowValues, valueName)
+		rowValues = append(rowValues, r.valueLabel(valueName))
 		// include only the hosts in HostIndices
 		for _, hostIndex := range r.HostIndices {
 			hv := table.AllHostValues[hostIndex]
@@ -279,17 +360,32 @@ func (r *ReportGen) renderSingleValueTable(table *Table, refData []*HostReferenc
  *	value			value			value		value
  *	value			value			value		value
  */
+// sortableTables are the multi-value tables, often with many rows, that are rendered with
+// client-side sorting (click a column header) and a filter box
+var sortableTables = []string{"Process", "Sensor", "Filesystem"}
+
 func (r *ReportGen) renderMultiValueTable(table *Table) (out string) {
+	sortable := slices.Contains(sortableTables, table.Name)
 	// include only the host in HostIndices
 	for _, hostIndex := range r.HostIndices {
 		// hostname above table if more than one hostname
 		if len(r.HostIndices) > 1 {
 			out += `<h3>` + table.AllHostValues[hostIndex].Name + `</h3>`
 		}
+		class := "pure-table pure-table-striped"
+		if sortable && len(table.AllHostValues[hostIndex].Values) > 0 {
+			// filter must immediately precede the table, see filterTable() in the template
+			out += `<input class="table-filter" type="text" placeholder="Filter rows...">`
+			class += " sortable"
+		}
+		var valueLabels []string
+		for _, valueName := range table.AllHostValues[hostIndex].ValueNames {
+			valueLabels = append(valueLabels, r.valueLabel(valueName))
+		}
 		out += renderHTMLTable(
-			table.AllHostValues[hostIndex].ValueNames,
+			valueLabels,
 			table.AllHostValues[hostIndex].Values,
-			"pure-table pure-table-striped",
+			class,
 			[][]string{},
 		)
 	}
@@ -399,7 +495,7 @@ func (r *ReportGen) renderFrequencyChart(table *Table) (out string) {
 				}{
 					Label: "non-avx:spec",
 					Data:  specValues,
-					Color: getColor(0),
+					Color: r.getColor(0),
 				})
 				if err != nil {
 					return
@@ -424,7 +520,7 @@ func (r *ReportGen) renderFrequencyChart(table *Table) (out string) {
 				}{
 					Label: "non-avx",
 					Data:  measuredValues,
-					Color: getColor(1),
+					Color: r.getColor(1),
 				})
 				if err != nil {
 					return
@@ -527,7 +623,7 @@ func (r *ReportGen) renderAverageCPUUtilizationChart(table *Table) (out string)
 					}{
 						Label: stat,
 						Data:  specValues,
-						Color: getColor(statIdx - 1),
+						Color: r.getColor(statIdx - 1),
 					})
 					if err != nil {
 						return
@@ -597,7 +693,7 @@ func (r *ReportGen) renderCPUUtilizationChart(table *Table) (out string) {
 				keys = append(keys, cpu)
 			}
 			sort.Ints(keys)
-			for cpu := range keys {
+			for _, cpu := range keys {
 				stats := cpuBusyStats[cpu]
 				formattedPoints := []string{}
 				for statIdx, stat := range stats {
@@ -614,7 +710,7 @@ func (r *ReportGen) renderCPUUtilizationChart(table *Table) (out string) {
 					}{
 						Label: fmt.Sprintf("CPU %d", cpu),
 						Data:  specValues,
-						Color: getColor(cpu),
+						Color: r.getColor(cpu),
 					})
 					if err != nil {
 						return
@@ -672,18 +768,24 @@ func (r *ReportGen) renderIRQRateChart(table *Table) (out string) {
 				timeStamp := table.AllHostValues[hostIndex].Values[0][0] // timestamp off of first sample
 				total := 0.0
 				xVal := 0
+				parsed := false
 				for _, point := range table.AllHostValues[hostIndex].Values {
 					statVal, err := strconv.ParseFloat(point[statIdx], 64)
 					if err != nil {
 						continue
 					}
-					total += statVal
 					if timeStamp != point[0] {
 						formattedPoints = append(formattedPoints, fmt.Sprintf("{x: %d, y: %0.2f}", xVal, total))
 						timeStamp = point[0]
 						total = 0.0
 						xVal += 1
 					}
+					total += statVal
+					parsed = true
+				}
+				// the last sample
+				if parsed {
+					formattedPoints = append(formattedPoints, fmt.Sprintf("{x: %d, y: %0.2f}", xVal, total))
 				}
 				if len(formattedPoints) > 0 {
 					specValues := strings.Join(formattedPoints, ",")
@@ -696,7 +798,7 @@ func (r *ReportGen) renderIRQRateChart(table *Table) (out string) {
 					}{
 						Label: stat,
 						Data:  specValues,
-						Color: getColor(statIdx - 1),
+						Color: r.getColor(statIdx - 1),
 					})
 					if err != nil {
 						return
@@ -776,7 +878,7 @@ func (r *ReportGen) renderDriveStatsChart(table *Table) (out string) {
 						}{
 							Label: hv.ValueNames[valIdx+1],
 							Data:  specValues,
-							Color: getColor(valIdx),
+							Color: r.getColor(valIdx),
 						})
 						if err != nil {
 							return
@@ -857,7 +959,7 @@ func (r *ReportGen) renderNetworkStatsChart(table *Table) (out string) {
 						}{
 							Label: hv.ValueNames[valIdx+2],
 							Data:  specValues,
-							Color: getColor(valIdx),
+							Color: r.getColor(valIdx),
 						})
 						if err != nil {
 							return
@@ -926,7 +1028,7 @@ func (r *ReportGen) renderMemoryStatsChart(table *Table) (out string) {
 					}{
 						Label: stat,
 						Data:  specValues,
-						Color: getColor(statIdx - 1),
+						Color: r.getColor(statIdx - 1),
 					})
 					if err != nil {
 						return
@@ -991,7 +1093,7 @@ func (r *ReportGen) renderPowerStatsChart(table *Table) (out string) {
 					}{
 						Label: stat,
 						Data:  specValues,
-						Color: getColor(statIdx),
+						Color: r.getColor(statIdx),
 					})
 					if err != nil {
 						return
@@ -1028,6 +1130,75 @@ func (r *ReportGen) renderPowerStatsChart(table *Table) (out string) {
 	return
 }
 
+func (r *ReportGen) renderCStateResidencyChart(table *Table) (out string) {
+	// one chart per host
+	for _, hostIndex := range r.HostIndices {
+		// add hostname only if more than one host
+		hostnameHeader := len(r.HostIndices) > 1
+		if hostnameHeader {
+			out += `<h3>` + table.AllHostValues[hostIndex].Name + `</h3>`
+		}
+		hv := table.AllHostValues[hostIndex]
+		// need at least one set of values
+		if len(hv.Values) > 0 {
+			var datasets []string
+			for statIdx, stat := range hv.ValueNames[1:] { // 1 data set per residency, e.g., CPU%c1, CPU%c6
+				formattedPoints := []string{}
+				for _, point := range hv.Values {
+					// package residencies are only reported on the first CPU of each package
+					if point[statIdx+1] == "" {
+						continue
+					}
+					formattedPoints = append(formattedPoints, fmt.Sprintf("{x: %s, y: %s}", point[0], point[statIdx+1]))
+				}
+				if len(formattedPoints) > 0 {
+					specValues := strings.Join(formattedPoints, ",")
+					dst := texttemplate.Must(texttemplate.New("datasetTemplate").Parse(datasetTemplate))
+					buf := new(bytes.Buffer)
+					err := dst.Execute(buf, struct {
+						Label string
+						Data  string
+						Color string
+					}{
+						Label: stat,
+						Data:  specValues,
+						Color: r.getColor(statIdx),
+					})
+					if err != nil {
+						return
+					}
+					datasets = append(datasets, buf.String())
+				}
+			}
+			if len(datasets) > 0 {
+				sct := texttemplate.Must(texttemplate.New("scatterChartTemplate").Parse(scatterChartTemplate))
+				buf := new(bytes.Buffer)
+				err := sct.Execute(buf, scatterChartTemplateStruct{
+					ID:            "cstateresidency" + fmt.Sprintf("%d", hostIndex),
+					Datasets:      strings.Join(datasets, ","),
+					XaxisText:     "CPU",
+					YaxisText:     "% Residency",
+					TitleText:     "",
+					DisplayTitle:  "false",
+					DisplayLegend: "true",
+					AspectRatio:   "2",
+					YaxisZero:     "true",
+				})
+				if err != nil {
+					return
+				}
+				out += buf.String()
+				out += "\n"
+			} else {
+				out += noDataFound
+			}
+		} else {
+			out += noDataFound
+		}
+	}
+	return
+}
+
 const flameGraphTemplate = `
 <div id="chart{{.ID}}"></div>
 <script type="text/javascript">
@@ -1171,9 +1342,13 @@ func (r *ReportGen) renderCodePathFrequency(table *Table) (out string) {
 	return
 }
 
-func getColor(idx int) string {
+func (r *ReportGen) getColor(idx int) string {
 	// color-blind safe palette from here: http://mkweb.bcgsc.ca/colorblind/palettes.mhtml#page-container
 	colors := []string{"#9F0162", "#009F81", "#FF5AAF", "#00FCCF", "#8400CD", "#008DF9", "#00C2F9", "#FFB2FD", "#A40122", "#E20134", "#FF6E3A", "#FFC33B"}
+	if r.Theme == "dark" {
+		// the brighter colors from the same palette, the darkest are hard to see on a dark background
+		colors = []string{"#00FCCF", "#FF5AAF", "#00C2F9", "#FFC33B", "#FFB2FD", "#FF6E3A", "#008DF9", "#E20134", "#009F81"}
+	}
 	return colors[idx%len(colors)]
 }
 
@@ -1199,7 +1374,7 @@ func (r *ReportGen) renderBandwidthLatencyChart(table *Table, refData []*HostRef
 			}{
 				Label: hv.Name,
 				Data:  data,
-				Color: getColor(colorIdx),
+				Color: r.getColor(colorIdx),
 			})
 			if err != nil {
 				return
@@ -1229,7 +1404,7 @@ func (r *ReportGen) renderBandwidthLatencyChart(table *Table, refData []*HostRef
 					}{
 						Label: hostname,
 						Data:  data,
-						Color: getColor(colorIdx),
+						Color: r.getColor(colorIdx),
 					})
 					if err != nil {
 						return
@@ -1296,10 +1471,11 @@ func (r *ReportGen) renderNumaBandwidthTable(table *Table, refData []*HostRefere
 			}
 		}
 		tableHeaders = append(tableHeaders, "Node")
-		for nodeIdx, node := range table.AllHostValues[hostIndex].Values {
-			tableHeaders = append(tableHeaders, fmt.Sprintf("%d", nodeIdx))
+		// label rows and columns with the node numbers reported by MLC, they may not start at 0
+		for _, node := range table.AllHostValues[hostIndex].Values {
+			tableHeaders = append(tableHeaders, node[0])
 			var rowValues []string
-			rowValues = append(rowValues, fmt.Sprintf("%d", nodeIdx))
+			rowValues = append(rowValues, node[0])
 			bandwidths := strings.Split(node[1], ",")
 			rowValues = append(rowValues, bandwidths...)
 			tableValues = append(tableValues, rowValues)
@@ -1362,8 +1538,33 @@ func dimmDetails(dimm []string) (details string) {
 	return
 }
 
+// dimmColors are the background colors of the populated DIMM slots, identical modules have the
+// same color, the last color is shared when there are more distinct modules than colors
+var dimmColors = []string{"lightgreen", "orange", "aqua", "lime", "yellow", "beige", "magenta", "violet", "salmon", "pink"}
+
+// dimmSlotStyle returns the style of the slot with the DIMM details, adding the details to
+// colorIndices when first seen
+func dimmSlotStyle(details string, colorIndices map[string]int) string {
+	if details == "No Module Installed" {
+		return "background-color:silver"
+	}
+	if _, ok := colorIndices[details]; !ok {
+		colorIndices[details] = int(math.Min(float64(len(colorIndices)), float64(len(dimmColors)-1)))
+	}
+	return "background-color:" + dimmColors[colorIndices[details]]
+}
+
+// sortedChannels returns the socket's channels in numeric order
+func sortedChannels(socketMap map[string]map[string]string) (channelKeys []int) {
+	for k := range socketMap {
+		channel, _ := strconv.Atoi(k)
+		channelKeys = append(channelKeys, channel)
+	}
+	sort.Ints(channelKeys)
+	return
+}
+
 func (r *ReportGen) renderDIMMPopulationTable(table *Table) (out string) {
-	htmlColors := []string{"lightgreen", "orange", "aqua", "lime", "yellow", "beige", "magenta", "violet", "salmon", "pink"}
 	// a DIMM Population table for every host
 	for _, hostIndex := range r.HostIndices {
 		var slotColorIndices = make(map[string]int)
@@ -1382,25 +1583,23 @@ func (r *ReportGen) renderDIMMPopulationTable(table *Table) (out string) {
 			}
 			dimms[vals[DerivedSocketIdx]][vals[DerivedChannelIdx]][vals[DerivedSlotIdx]] = dimmDetails(vals)
 		}
-		var socketTableHeaders = []string{"Socket", ""}
-		var socketTableValues [][]string
 		var socketKeys []string
 		for k := range dimms {
 			socketKeys = append(socketKeys, k)
 		}
 		sort.Strings(socketKeys)
+		if r.DIMMGrid > 0 && len(table.AllHostValues[hostIndex].Values) > r.DIMMGrid {
+			out += renderDIMMPopulationGrid(dimms, socketKeys)
+			continue
+		}
+		var socketTableHeaders = []string{"Socket", ""}
+		var socketTableValues [][]string
 		for _, socket := range socketKeys {
 			socketMap := dimms[socket]
 			socketTableValues = append(socketTableValues, []string{})
 			var channelTableHeaders = []string{"Channel", "Slots"}
 			var channelTableValues [][]string
-			var channelKeys []int
-			for k := range socketMap {
-				channel, _ := strconv.Atoi(k)
-				channelKeys = append(channelKeys, channel)
-			}
-			sort.Ints(channelKeys)
-			for _, channel := range channelKeys {
+			for _, channel := range sortedChannels(socketMap) {
 				channelMap := socketMap[strconv.Itoa(channel)]
 				channelTableValues = append(channelTableValues, []string{})
 				var slotTableHeaders []string
@@ -1416,16 +1615,7 @@ func (r *ReportGen) renderDIMMPopulationTable(table *Table) (out string) {
 				for _, slot := range slotKeys {
 					dimmDetails := channelMap[slot]
 					slotTableValues[0] = append(slotTableValues[0], dimmDetails)
-					var slotColor string
-					if dimmDetails == "No Module Installed" {
-						slotColor = "background-color:silver"
-					} else {
-						if _, ok := slotColorIndices[dimmDetails]; !ok {
-							slotColorIndices[dimmDetails] = int(math.Min(float64(len(slotColorIndices)), float64(len(htmlColors)-1)))
-						}
-						slotColor = "background-color:" + htmlColors[slotColorIndices[dimmDetails]]
-					}
-					slotTableValuesStyles[0] = append(slotTableValuesStyles[0], slotColor)
+					slotTableValuesStyles[0] = append(slotTableValuesStyles[0], dimmSlotStyle(dimmDetails, slotColorIndices))
 				}
 				slotTable := renderHTMLTable(slotTableHeaders, slotTableValues, "pure-table pure-table-bordered", slotTableValuesStyles)
 				// channel number
@@ -1445,6 +1635,93 @@ func (r *ReportGen) renderDIMMPopulationTable(table *Table) (out string) {
 	return
 }
 
+/* DIMM Population Grid is rendered like this:
+ *
+ *	Socket	Channel	Slot 0	Slot 1	...	Slot N
+ *	0		0		A		-
+ *	0		1		A		B
+ *	...
+ *
+ *	Module	Slots	Details
+ *	A		2		64 GB @4800 MT/s DDR5 ...
+ *	B		1		...
+ *	-		1		No Module Installed
+ *
+ * each slot's cell, and its module in the legend, has the module's color
+ */
+func renderDIMMPopulationGrid(dimms map[string]map[string]map[string]string, socketKeys []string) (out string) {
+	var slotColorIndices = make(map[string]int)
+	// the columns, the slots of all channels
+	var slotKeys []string
+	for _, socketMap := range dimms {
+		for _, channelMap := range socketMap {
+			for slot := range channelMap {
+				if !slices.Contains(slotKeys, slot) {
+					slotKeys = append(slotKeys, slot)
+				}
+			}
+		}
+	}
+	sort.Strings(slotKeys)
+	gridHeaders := []string{"Socket", "Channel"}
+	for _, slot := range slotKeys {
+		gridHeaders = append(gridHeaders, "Slot "+slot)
+	}
+	// a short label for each distinct module, in order of first appearance
+	var moduleDetails []string
+	moduleLabels := map[string]string{"No Module Installed": "-"}
+	moduleCounts := make(map[string]int)
+	var gridValues [][]string
+	var gridValuesStyles [][]string
+	for _, socket := range socketKeys {
+		socketMap := dimms[socket]
+		for _, channel := range sortedChannels(socketMap) {
+			channelMap := socketMap[strconv.Itoa(channel)]
+			rowValues := []string{socket, strconv.Itoa(channel)}
+			rowStyles := []string{"font-weight:bold", "font-weight:bold"}
+			for _, slot := range slotKeys {
+				details, ok := channelMap[slot]
+				if !ok {
+					rowValues = append(rowValues, "")
+					rowStyles = append(rowStyles, "")
+					continue
+				}
+				if _, ok := moduleLabels[details]; !ok {
+					moduleLabels[details] = dimmModuleLabel(len(moduleDetails))
+					moduleDetails = append(moduleDetails, details)
+				}
+				moduleCounts[details]++
+				rowValues = append(rowValues, moduleLabels[details])
+				rowStyles = append(rowStyles, "text-align:center;"+dimmSlotStyle(details, slotColorIndices))
+			}
+			gridValues = append(gridValues, rowValues)
+			gridValuesStyles = append(gridValuesStyles, rowStyles)
+		}
+	}
+	out += renderHTMLTable(gridHeaders, gridValues, "pure-table pure-table-bordered", gridValuesStyles)
+	// legend
+	if moduleCounts["No Module Installed"] > 0 {
+		moduleDetails = append(moduleDetails, "No Module Installed")
+	}
+	var legendValues [][]string
+	var legendValuesStyles [][]string
+	for _, details := range moduleDetails {
+		legendValues = append(legendValues, []string{moduleLabels[details], strconv.Itoa(moduleCounts[details]), details})
+		legendValuesStyles = append(legendValuesStyles, []string{"text-align:center;" + dimmSlotStyle(details, slotColorIndices)})
+	}
+	out += `<br>`
+	out += renderHTMLTable([]string{"Module", "Slots", "Details"}, legendValues, "pure-table pure-table-striped", legendValuesStyles)
+	return
+}
+
+// dimmModuleLabel returns the grid label of the index'th distinct module: A-Z, then numbers
+func dimmModuleLabel(index int) string {
+	if index < 26 {
+		return string(rune('A' + index))
+	}
+	return strconv.Itoa(index + 1)
+}
+
 // if there's one value per value name
 //
 //	and
@@ -1466,6 +1743,11 @@ func isSingleValueTable(table *Table) bool {
 	return true
 }
 
+// valueLabel returns the translated label of an HTML escaped value name, see HTMLEscapeTable
+func (r *ReportGen) valueLabel(escapedName string) string {
+	return html.EscapeString(r.Messages.Value(html.UnescapeString(escapedName)))
+}
+
 // HTMLEscapeTable - escape value names and values
 func HTMLEscapeTable(table *Table) (safeTable Table) {
 	safeTable.Name = table.Name
@@ -1491,7 +1773,7 @@ func HTMLEscapeTable(table *Table) (safeTable Table) {
 func (r *ReportGen) RenderDataTable(unsafeTable *Table, refData []*HostReferenceData) template.HTML {
 	t := HTMLEscapeTable(unsafeTable)
 	table := &t
-	out := fmt.Sprintf("<h2 id=%s>%s</h2>\n", "\""+table.Name+"\"", table.Name)
+	out := fmt.Sprintf("<h2 id=%s>%s</h2>\n", "\""+table.Name+"\"", html.EscapeString(r.Messages.Table(unsafeTable.Name)))
 	if table.Name == "Core Frequency" {
 		out += r.renderFrequencyChart(table)
 	} else if table.Name == "Memory Bandwidth and Latency" {
@@ -1516,6 +1798,8 @@ func (r *ReportGen) RenderDataTable(unsafeTable *Table, refData []*HostReference
 		out += r.renderCodePathFrequency(table)
 	} else if table.Name == "Power Stats" {
 		out += r.renderPowerStatsChart(table)
+	} else if table.Name == "C-State Residency" {
+		out += r.renderCStateResidencyChart(table)
 	} else if isSingleValueTable(table) {
 		out += r.renderSingleValueTable(table, refData)
 	} else {
@@ -1542,19 +1826,17 @@ func (r *ReportGeneratorHTML) generate() (reportFilePaths []string, err error) {
 		if hostReferenceData != nil {
 			hostsReferenceData = append(hostsReferenceData, hostReferenceData)
 		}
-		fileName := hostname + ".html"
-		reportFilePath := filepath.Join(r.outputDir, fileName)
-		var f *os.File
-		f, err = os.OpenFile(reportFilePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		var rawData []RawData
+		rawData, err = r.getRawData([]int{hostIndex})
 		if err != nil {
 			return
 		}
-		err = t.Execute(f, newReportGen(r.reports, []int{hostIndex}, hostsReferenceData))
-		f.Close()
+		var filePaths []string
+		filePaths, err = r.writeReport(t, hostname, newReportGen(r.reports, []int{hostIndex}, hostsReferenceData, rawData, r.theme, r.messages, r.dimmGrid))
 		if err != nil {
 			return
 		}
-		reportFilePaths = append(reportFilePaths, reportFilePath)
+		reportFilePaths = append(reportFilePaths, filePaths...)
 	}
 	// if more than one host, create a combined report
 	if len(hostnames) > 1 {
@@ -1577,23 +1859,61 @@ func (r *ReportGeneratorHTML) generate() (reportFilePaths []string, err error) {
 				}
 			}
 		}
-		fileName := "all_hosts" + ".html"
-		reportFilePath := filepath.Join(r.outputDir, fileName)
-		var f *os.File
-		f, err = os.OpenFile(reportFilePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-		if err != nil {
-			return
-		}
 		var hostIndices []int
 		for i := 0; i < len(hostnames); i++ {
 			hostIndices = append(hostIndices, i)
 		}
-		err = t.Execute(f, newReportGen(r.reports, hostIndices, hostsReferenceData))
-		f.Close()
+		var rawData []RawData
+		rawData, err = r.getRawData(hostIndices)
 		if err != nil {
 			return
 		}
+		var filePaths []string
+		filePaths, err = r.writeReport(t, "all_hosts", newReportGen(r.reports, hostIndices, hostsReferenceData, rawData, r.theme, r.messages, r.dimmGrid))
+		if err != nil {
+			return
+		}
+		reportFilePaths = append(reportFilePaths, filePaths...)
+	}
+	return
+}
+
+// writeReport executes the template into <baseName>.html or, when splitting, into
+// <baseName>_<report name>.html for each report with links between the files
+func (r *ReportGeneratorHTML) writeReport(t *template.Template, baseName string, gen *ReportGen) (reportFilePaths []string, err error) {
+	if !r.split {
+		reportFilePath := filepath.Join(r.outputDir, baseName+".html")
+		if err = executeTemplateToFile(t, reportFilePath, gen); err != nil {
+			return
+		}
 		reportFilePaths = append(reportFilePaths, reportFilePath)
+		return
+	}
+	var pages []Page
+	for _, report := range gen.Reports {
+		pages = append(pages, Page{Name: report.Name, FileName: baseName + "_" + report.Name + ".html"})
+	}
+	for i, report := range gen.Reports {
+		pageGen := *gen
+		pageGen.Reports = []*ReportWithMore{report}
+		pageGen.Pages = make([]Page, len(pages))
+		copy(pageGen.Pages, pages)
+		pageGen.Pages[i].Current = true
+		reportFilePath := filepath.Join(r.outputDir, pages[i].FileName)
+		if err = executeTemplateToFile(t, reportFilePath, &pageGen); err != nil {
+			return
+		}
+		reportFilePaths = append(reportFilePaths, reportFilePath)
+	}
+	return
+}
+
+func executeTemplateToFile(t *template.Template, filePath string, gen *ReportGen) (err error) {
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return
 	}
+	defer f.Close()
+	err = t.Execute(f, gen)
 	return
 }
@@ -0,0 +1,49 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderDIMMPopulationGrid(t *testing.T) {
+	dimm := func(socket, channel, slot, size, part string) []string {
+		return []string{"", "", "Hynix", part, "", size, "DDR5", "Synchronous", "", "", "4800 MT/s", socket, channel, slot}
+	}
+	table := &Table{
+		Name: "DIMM Population",
+		AllHostValues: []HostValues{
+			{
+				Name: "host",
+				Values: [][]string{
+					dimm("0", "0", "0", "32 GB", "P1"),
+					dimm("0", "0", "1", "No Module Installed", ""),
+					dimm("0", "1", "0", "32 GB", "P1"),
+					dimm("0", "1", "1", "64 GB", "P2"),
+				},
+			},
+		},
+	}
+	// nested tables when the host has no more slots than the threshold
+	r := &ReportGen{HostIndices: []int{0}, DIMMGrid: 4}
+	if out := r.renderDIMMPopulationTable(table); strings.Contains(out, "Slot 0") {
+		t.Error("expected nested tables")
+	}
+	// grid when the host has more slots than the threshold
+	r.DIMMGrid = 3
+	out := r.renderDIMMPopulationTable(table)
+	for _, expected := range []string{
+		"<th>Slot 0</th><th>Slot 1</th>",
+		`<td style="font-weight:bold">0</td><td style="font-weight:bold">0</td><td style="text-align:center;background-color:lightgreen">A</td><td style="text-align:center;background-color:silver">-</td>`,
+		`<td style="text-align:center;background-color:lightgreen">A</td><td>2</td><td>32 GB @4800 MT/s DDR5 Synchronous Hynix P1</td>`,
+		`<td style="text-align:center;background-color:orange">B</td><td>1</td><td>64 GB @4800 MT/s DDR5 Synchronous Hynix P2</td>`,
+		`<td style="text-align:center;background-color:silver">-</td><td>1</td><td>No Module Installed</td>`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %s in %s", expected, out)
+		}
+	}
+}
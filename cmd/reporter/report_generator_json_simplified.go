@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strconv"
 )
 
 type ReportGeneratorJSONSimplified struct {
@@ -23,7 +24,7 @@ func newReportGeneratorJSONSimplified(outputDir string, configurationReport *Rep
 	return
 }
 
-type SimpleRow map[string]string         //valuename->value
+type SimpleRow map[string]interface{}    //valuename->value
 type SimpleTable map[string][]SimpleRow  //tablename->[]rows
 type SimpleReport map[string]SimpleTable //reportname->tables
 type SimpleHosts map[string]SimpleReport //hostname->reports
@@ -33,6 +34,9 @@ func convertToSimple(hostNames []string, reportsData []*Report) (simpleHosts Sim
 	for hostIndex, hostName := range hostNames {
 		simpleReport := make(SimpleReport)
 		for _, report := range reportsData {
+			if len(report.Tables) == 0 { // skip the reports that weren't requested
+				continue
+			}
 			simpleTable := make(SimpleTable)
 			for _, table := range report.Tables {
 				hostValues := table.AllHostValues[hostIndex]
@@ -44,6 +48,7 @@ func convertToSimple(hostNames []string, reportsData []*Report) (simpleHosts Sim
 					simpleTable[table.Name] = append(simpleTable[table.Name], simpleRow)
 				}
 			}
+			addMemoryChannelPopulation(simpleTable)
 			simpleReport[report.InternalName] = simpleTable
 		}
 		simpleHosts[hostName] = simpleReport
@@ -51,6 +56,44 @@ func convertToSimple(hostNames []string, reportsData []*Report) (simpleHosts Sim
 	return
 }
 
+// addMemoryChannelPopulation makes the CPU table's "Memory Channels" a number, or "Unknown" when
+// the CPU isn't recognized, and adds "fully_populated" to the Memory table when the number of
+// populated channels can be compared to the number of channels the CPUs support
+func addMemoryChannelPopulation(simpleTable SimpleTable) {
+	cpuRows := simpleTable["CPU"]
+	if len(cpuRows) == 0 {
+		return
+	}
+	if _, ok := cpuRows[0]["Memory Channels"]; !ok {
+		return
+	}
+	channels, ok := getSimpleRowInt(cpuRows[0], "Memory Channels")
+	if !ok {
+		cpuRows[0]["Memory Channels"] = "Unknown"
+		return
+	}
+	cpuRows[0]["Memory Channels"] = channels
+	sockets, ok := getSimpleRowInt(cpuRows[0], "Sockets")
+	if !ok {
+		return
+	}
+	for _, memoryRow := range simpleTable["Memory"] {
+		if populated, ok := getSimpleRowInt(memoryRow, "Populated Memory Channels"); ok {
+			memoryRow["fully_populated"] = populated >= channels*sockets
+		}
+	}
+}
+
+func getSimpleRowInt(row SimpleRow, valueName string) (val int, ok bool) {
+	str, ok := row[valueName].(string)
+	if !ok {
+		return
+	}
+	val, err := strconv.Atoi(str)
+	ok = err == nil
+	return
+}
+
 func (r *ReportGeneratorJSONSimplified) generate() (reportFilePaths []string, err error) {
 	var hostnames []string
 	for _, values := range r.reports[0].Tables[0].AllHostValues {
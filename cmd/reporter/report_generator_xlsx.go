@@ -19,13 +19,15 @@ type ReportGeneratorXLSX struct {
 	reports    []*Report
 	sheetNames []string
 	outputDir  string
+	messages   *MessageCatalog
 }
 
-func newReportGeneratorXLSX(outputDir string, configurationReport *Report, briefReport *Report, insightReport *Report, profileReport *Report, benchmarkReport *Report, analyzeReport *Report) (rpt *ReportGeneratorXLSX) {
+func newReportGeneratorXLSX(outputDir string, messages *MessageCatalog, configurationReport *Report, briefReport *Report, insightReport *Report, profileReport *Report, benchmarkReport *Report, analyzeReport *Report) (rpt *ReportGeneratorXLSX) {
 	rpt = &ReportGeneratorXLSX{
 		reports:    []*Report{configurationReport, briefReport, benchmarkReport, profileReport, analyzeReport, insightReport}, // this is the order the tabs will appear in the spreadsheet
 		sheetNames: []string{"Configuration", "Brief", "Benchmark", "Profile", "Analyze", "Insights"},
 		outputDir:  outputDir,
+		messages:   messages,
 	}
 	return
 }
@@ -42,6 +44,26 @@ func cellName(col int, row int) (name string) {
 	return
 }
 
+// excelCellString removes characters that aren't allowed in XML 1.0, e.g., the control
+// characters (0x00-0x08, 0x0B, 0x0C, 0x0E-0x1F) found in dmesg output and folded stacks. Excel
+// reports a worksheet that includes them as corrupt. Invalid UTF-8 is replaced with U+FFFD.
+func excelCellString(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' ||
+			(r >= 0x20 && r <= 0xD7FF) ||
+			(r >= 0xE000 && r <= 0xFFFD) ||
+			(r >= 0x10000 && r <= 0x10FFFF) {
+			return r
+		}
+		return -1
+	}, value)
+}
+
+// setCellStr sets the cell's value after removing characters that would corrupt the worksheet
+func setCellStr(f *excelize.File, sheetName string, cell string, value string) {
+	f.SetCellStr(sheetName, cell, excelCellString(value))
+}
+
 func renderExcelTable(tableHeaders []string, tableValues [][]string, f *excelize.File, reportSheetName string, originRow int, originCol int, boldFirstCol bool) int {
 	row := originRow
 	col := originCol
@@ -72,7 +94,7 @@ func renderExcelTable(tableHeaders []string, tableValues [][]string, f *excelize
 					f.SetCellFloat(reportSheetName, cellName(col, row), floatValue, 1, 64)
 					f.SetCellStyle(reportSheetName, cellName(col, row), cellName(col, row), boldAlignLeft)
 				} else {
-					f.SetCellStr(reportSheetName, cellName(col, row), header)
+					setCellStr(f, reportSheetName, cellName(col, row), header)
 					f.SetCellStyle(reportSheetName, cellName(col, row), cellName(col, row), bold)
 				}
 				col += 1
@@ -92,17 +114,17 @@ func renderExcelTable(tableHeaders []string, tableValues [][]string, f *excelize
 						if rowIdx == 0 && boldFirstCol {
 							f.SetCellStyle(reportSheetName, cellName(col, row), cellName(col, row), bold)
 						}
-						f.SetCellStr(reportSheetName, cellName(col, row), value)
+						setCellStr(f, reportSheetName, cellName(col, row), value)
 					}
 					col += 1
 				}
 			} else {
-				f.SetCellStr(reportSheetName, cellName(col, row), "")
+				setCellStr(f, reportSheetName, cellName(col, row), "")
 			}
 			row += 1
 		}
 	} else {
-		f.SetCellStr(reportSheetName, cellName(col, row), "No data found.")
+		setCellStr(f, reportSheetName, cellName(col, row), "No data found.")
 		row += 1
 	}
 	return row
@@ -128,7 +150,7 @@ func (r *ReportGeneratorXLSX) renderSingleValueTable(allHostValues []HostValues,
 	}
 	for valueIndex, valueName := range valueNames {
 		var rowValues []string
-		rowValues = append(rowValues, valueName)
+		rowValues = append(rowValues, r.messages.Value(valueName))
 		for _, hv := range allHostValues {
 			if len(hv.Values) > 0 && len(hv.Values[0]) > valueIndex {
 				rowValues = append(rowValues, hv.Values[0][valueIndex])
@@ -162,7 +184,7 @@ func (r *ReportGeneratorXLSX) renderMultiValueTable(allHostValues []HostValues,
 	for idx, hv := range allHostValues {
 		// if more than one host, put hostname above table
 		if len(allHostValues) > 1 {
-			f.SetCellStr(reportSheetName, cellName(2, row), hv.Name)
+			setCellStr(f, reportSheetName, cellName(2, row), hv.Name)
 			headerStyle, _ := f.NewStyle(&excelize.Style{
 				Font: &excelize.Font{
 					Bold: true,
@@ -171,7 +193,7 @@ func (r *ReportGeneratorXLSX) renderMultiValueTable(allHostValues []HostValues,
 			f.SetCellStyle(reportSheetName, cellName(2, row), cellName(2, row), headerStyle)
 			row += 1
 		}
-		row = renderExcelTable(hv.ValueNames, hv.Values, f, reportSheetName, row, col, false)
+		row = renderExcelTable(r.messages.ValueList(hv.ValueNames), hv.Values, f, reportSheetName, row, col, false)
 		if idx < len(allHostValues)-1 {
 			row += 1
 		}
@@ -184,7 +206,7 @@ func (r *ReportGeneratorXLSX) renderNumaBandwidthTable(allHostValues []HostValue
 	for idx, hv := range allHostValues {
 		// if more than one host, put hostname above table
 		if len(allHostValues) > 1 {
-			f.SetCellStr(reportSheetName, cellName(2, row), hv.Name)
+			setCellStr(f, reportSheetName, cellName(2, row), hv.Name)
 			headerStyle, _ := f.NewStyle(&excelize.Style{
 				Font: &excelize.Font{
 					Bold: true,
@@ -195,7 +217,7 @@ func (r *ReportGeneratorXLSX) renderNumaBandwidthTable(allHostValues []HostValue
 		}
 		var tableHeaders []string
 		var tableValues [][]string
-		tableHeaders = append(tableHeaders, "Node")
+		tableHeaders = append(tableHeaders, r.messages.Value("Node"))
 		for nodeIdx, node := range hv.Values {
 			tableHeaders = append(tableHeaders, fmt.Sprintf("%d", nodeIdx))
 			rowValues := []string{node[0]}
@@ -216,7 +238,7 @@ func (r *ReportGeneratorXLSX) renderDIMMPopulationTable(allHostValues []HostValu
 	for idx, hv := range allHostValues {
 		// if more than one host, put hostname above table
 		if len(allHostValues) > 1 {
-			f.SetCellStr(reportSheetName, cellName(2, row), hv.Name)
+			setCellStr(f, reportSheetName, cellName(2, row), hv.Name)
 			headerStyle, _ := f.NewStyle(&excelize.Style{
 				Font: &excelize.Font{
 					Bold: true,
@@ -225,7 +247,7 @@ func (r *ReportGeneratorXLSX) renderDIMMPopulationTable(allHostValues []HostValu
 			f.SetCellStyle(reportSheetName, cellName(2, row), cellName(2, row), headerStyle)
 			row += 1
 		}
-		var tableHeaders = []string{"Socket", "Channel", "Slot", "Details"}
+		var tableHeaders = r.messages.ValueList([]string{"Socket", "Channel", "Slot", "Details"})
 		var tableValues [][]string
 		for _, dimm := range hv.Values {
 			tableValues = append(tableValues, []string{dimm[DerivedSocketIdx], dimm[DerivedChannelIdx], dimm[DerivedSlotIdx], dimmDetails(dimm)})
@@ -264,7 +286,7 @@ func (r *ReportGeneratorXLSX) fillSheet(f *excelize.File, reportSheetName string
 		}
 		col = 1
 		if !briefReport { // no table names in brief report
-			f.SetCellStr(reportSheetName, cellName(col, row), table.Name)
+			setCellStr(f, reportSheetName, cellName(col, row), r.messages.Table(table.Name))
 			f.SetCellStyle(reportSheetName, cellName(col, row), cellName(col, row), headerStyle)
 			col++
 		}
@@ -302,6 +324,9 @@ func (r *ReportGeneratorXLSX) generate() (reportFilePaths []string, err error) {
 		reportFilePath := filepath.Join(r.outputDir, fileName)
 		f := excelize.NewFile()
 		for reportIndex, reportData := range r.reports {
+			if len(reportData.Tables) == 0 { // skip the reports that weren't requested
+				continue
+			}
 			if reportIndex == 0 {
 				f.SetSheetName("Sheet1", r.sheetNames[reportIndex])
 			} else {
@@ -327,6 +352,9 @@ func (r *ReportGeneratorXLSX) generate() (reportFilePaths []string, err error) {
 		reportFilePath := filepath.Join(r.outputDir, fileName)
 		f := excelize.NewFile()
 		for reportIndex, reportData := range r.reports {
+			if len(reportData.Tables) == 0 { // skip the reports that weren't requested
+				continue
+			}
 			if reportIndex == 0 {
 				f.SetSheetName("Sheet1", r.sheetNames[reportIndex])
 			} else {
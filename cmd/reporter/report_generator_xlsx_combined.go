@@ -0,0 +1,156 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xuri/excelize/v2"
+)
+
+// ReportGeneratorXLSXCombined writes one Excel workbook for all hosts where each table is a
+// worksheet. Single-value tables have one column per host. Multi-value tables have one row per
+// host value, with the host in the first column, so that the fleet can be sorted and filtered.
+type ReportGeneratorXLSXCombined struct {
+	reports   []*Report
+	outputDir string
+	messages  *MessageCatalog
+}
+
+func newReportGeneratorXLSXCombined(outputDir string, messages *MessageCatalog, configurationReport *Report, insightReport *Report, profileReport *Report, benchmarkReport *Report, analyzeReport *Report) (rpt *ReportGeneratorXLSXCombined) {
+	rpt = &ReportGeneratorXLSXCombined{
+		reports:   []*Report{configurationReport, benchmarkReport, profileReport, analyzeReport, insightReport}, // brief report tables are copies of configuration report tables
+		outputDir: outputDir,
+		messages:  messages,
+	}
+	return
+}
+
+// getSheetName returns a valid worksheet name for the table that is not already in use.
+// Excel limits names to 31 characters, disallows some characters, and ignores case when
+// comparing names.
+func getSheetName(tableName string, usedNames map[string]bool) (name string) {
+	base := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '-'
+		}
+		return r
+	}, tableName)
+	base = strings.Trim(base, "'")
+	if base == "" {
+		base = "Table"
+	}
+	for i := 1; ; i++ {
+		suffix := ""
+		if i > 1 {
+			suffix = fmt.Sprintf(" (%d)", i)
+		}
+		runes := []rune(base)
+		if len(runes)+len(suffix) > excelize.MaxSheetNameLength {
+			runes = runes[:excelize.MaxSheetNameLength-len(suffix)]
+		}
+		name = string(runes) + suffix
+		if !usedNames[strings.ToLower(name)] {
+			usedNames[strings.ToLower(name)] = true
+			return
+		}
+	}
+}
+
+// haveSameValueNames returns true if all hosts with values have the same value names
+func haveSameValueNames(allHostValues []HostValues) bool {
+	var valueNames []string
+	for _, hv := range allHostValues {
+		if len(hv.ValueNames) == 0 {
+			continue
+		}
+		if valueNames == nil {
+			valueNames = hv.ValueNames
+		} else if !cmp.Equal(hv.ValueNames, valueNames) {
+			return false
+		}
+	}
+	return true
+}
+
+// renderHostRowsTable renders all hosts' values in one table with the host name in the first column
+func (r *ReportGeneratorXLSXCombined) renderHostRowsTable(allHostValues []HostValues, f *excelize.File, sheetName string, row int) int {
+	var valueNames []string
+	for _, hv := range allHostValues {
+		if len(hv.ValueNames) > 0 {
+			valueNames = hv.ValueNames
+			break
+		}
+	}
+	tableHeaders := r.messages.ValueList(append([]string{"Host"}, valueNames...))
+	var tableValues [][]string
+	for _, hv := range allHostValues {
+		for _, values := range hv.Values {
+			tableValues = append(tableValues, append([]string{hv.Name}, values...))
+		}
+	}
+	nextRow := renderExcelTable(tableHeaders, tableValues, f, sheetName, row, 1, true)
+	if len(tableValues) > 0 {
+		f.AutoFilter(sheetName, cellName(1, row)+":"+cellName(len(tableHeaders), nextRow-1), nil)
+	}
+	return nextRow
+}
+
+func (r *ReportGeneratorXLSXCombined) fillSheet(f *excelize.File, sheetName string, table *Table) {
+	// the existing generator's table-specific layouts are one table per host
+	xlsxGenerator := &ReportGeneratorXLSX{messages: r.messages}
+	f.SetColWidth(sheetName, "A", "A", 25)
+	f.SetColWidth(sheetName, "B", "Z", 20)
+	if table.Name == "Memory NUMA Bandwidth" {
+		xlsxGenerator.renderNumaBandwidthTable(table.AllHostValues, f, sheetName, 1)
+	} else if table.Name == "DIMM Population" {
+		xlsxGenerator.renderDIMMPopulationTable(table.AllHostValues, f, sheetName, 1)
+	} else if isSingleValueTable(table) {
+		xlsxGenerator.renderSingleValueTable(table.AllHostValues, f, sheetName, 1, 1, false)
+	} else if haveSameValueNames(table.AllHostValues) {
+		r.renderHostRowsTable(table.AllHostValues, f, sheetName, 1)
+	} else {
+		xlsxGenerator.renderMultiValueTable(table.AllHostValues, f, sheetName, 1, 1)
+	}
+}
+
+func (r *ReportGeneratorXLSXCombined) generate() (reportFilePaths []string, err error) {
+	fileName := "all_hosts_combined.xlsx"
+	reportFilePath := filepath.Join(r.outputDir, fileName)
+	f := excelize.NewFile()
+	usedNames := make(map[string]bool)
+	firstSheet := true
+	for _, reportData := range r.reports {
+		for _, table := range reportData.Tables {
+			if table == nil {
+				continue
+			}
+			sheetName := getSheetName(r.messages.Table(table.Name), usedNames)
+			if firstSheet {
+				f.SetSheetName("Sheet1", sheetName)
+				firstSheet = false
+			} else if _, err = f.NewSheet(sheetName); err != nil {
+				return
+			}
+			r.fillSheet(f, sheetName, table)
+		}
+	}
+	var outFile *os.File
+	outFile, err = os.OpenFile(reportFilePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return
+	}
+	_, err = f.WriteTo(outFile)
+	outFile.Close()
+	if err != nil {
+		return
+	}
+	reportFilePaths = append(reportFilePaths, reportFilePath)
+	return
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestExcelCellString(t *testing.T) {
+	for in, expected := range map[string]string{
+		"no change":              "no change",
+		"tab\tnewline\ncr\r":     "tab\tnewline\ncr\r",
+		"null\x00bell\x07":       "nullbell",
+		"vt\x0bff\x0cesc\x1b[0m": "vtffesc[0m",
+		"unit sep\x1f":           "unit sep",
+		"non-char\uffff":         "non-char",
+		"invalid utf-8 \xff":     "invalid utf-8 \ufffd",
+	} {
+		if out := excelCellString(in); out != expected {
+			t.Errorf("%q: expected %q, got %q", in, expected, out)
+		}
+	}
+}
+
+func TestXLSXWithControlCharacters(t *testing.T) {
+	var badBytes []byte
+	for b := byte(0x00); b < 0x20; b++ {
+		badBytes = append(badBytes, b)
+	}
+	value := "before" + string(badBytes) + "after"
+	allHostValues := []HostValues{
+		{
+			Name:       "host\x01",
+			ValueNames: []string{"Name\x0b", "Value"},
+			Values:     [][]string{{"dmesg", value}},
+		},
+	}
+	f := excelize.NewFile()
+	sheetName := "Sheet1"
+	r := &ReportGeneratorXLSX{}
+	r.renderMultiValueTable(allHostValues, f, sheetName, 1, 1)
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	// every XML part in the workbook must be well-formed
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, zf := range zr.File {
+		if !strings.HasSuffix(zf.Name, ".xml") {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoder := xml.NewDecoder(rc)
+		for {
+			if _, err = decoder.Token(); err != nil {
+				break
+			}
+		}
+		rc.Close()
+		if err != io.EOF {
+			t.Fatalf("%s is not valid XML: %v", zf.Name, err)
+		}
+	}
+	// the workbook opens and has the sanitized values
+	wb, err := excelize.OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cell, _ := wb.GetCellValue(sheetName, "A1"); cell != "Name" {
+		t.Errorf("unexpected header: %q", cell)
+	}
+	if cell, _ := wb.GetCellValue(sheetName, "B2"); cell != "before\t\n\rafter" {
+		t.Errorf("unexpected value: %q", cell)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ReportGeneratorYAML writes the same structure as the simplified JSON report, i.e.,
+// hosts -> reports -> tables -> rows, in YAML format
+type ReportGeneratorYAML struct {
+	reports   []*Report
+	outputDir string
+}
+
+func newReportGeneratorYAML(outputDir string, configurationReport *Report, briefReport *Report, insightReport *Report, profileReport *Report, benchmarkReport *Report, analyzeReport *Report) (rpt *ReportGeneratorYAML) {
+	rpt = &ReportGeneratorYAML{
+		reports:   []*Report{configurationReport, briefReport, insightReport, profileReport, benchmarkReport, analyzeReport},
+		outputDir: outputDir,
+	}
+	return
+}
+
+func (r *ReportGeneratorYAML) generate() (reportFilePaths []string, err error) {
+	var hostnames []string
+	for _, values := range r.reports[0].Tables[0].AllHostValues {
+		hostnames = append(hostnames, values.Name)
+	}
+	allHosts, err := convertToSimple(hostnames, r.reports)
+	if err != nil {
+		return
+	}
+	// one yaml report per host
+	for hostName, host := range allHosts {
+		fileName := hostName + ".yaml"
+		reportFilePath := filepath.Join(r.outputDir, fileName)
+		var yamlData []byte
+		yamlData, err = yaml.Marshal(host)
+		if err != nil {
+			return
+		}
+		err = os.WriteFile(reportFilePath, yamlData, 0644)
+		if err != nil {
+			return
+		}
+		reportFilePaths = append(reportFilePaths, reportFilePath)
+	}
+	// combined, all-host yaml report, if more than one host
+	if len(hostnames) > 1 {
+		fileName := "all_hosts.yaml"
+		reportFilePath := filepath.Join(r.outputDir, fileName)
+		var yamlData []byte
+		yamlData, err = yaml.Marshal(allHosts)
+		if err != nil {
+			return
+		}
+		err = os.WriteFile(reportFilePath, yamlData, 0644)
+		if err != nil {
+			return
+		}
+		reportFilePaths = append(reportFilePaths, reportFilePath)
+	}
+	return
+}
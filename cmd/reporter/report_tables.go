@@ -9,7 +9,9 @@ package main
 import (
 	"fmt"
 	"log"
+	"os"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -120,9 +122,20 @@ func newMemoryNUMABandwidthTable(sources []*Source, category TableCategory) (tab
 			},
 			Values: [][]string{},
 		}
-		nodeBandwidthsPairs := source.valsArrayFromRegexSubmatch("Memory MLC Bandwidth", `^(\d)\s+(\d.*)`)
+		// node numbers may have more than one digit and columns may be separated by tabs or spaces
+		nodeBandwidthsPairs := source.valsArrayFromRegexSubmatch("Memory MLC Bandwidth", `^(\d+)\s+(\d.*)`)
+		scale := source.getMLCBandwidthScale("Memory MLC Bandwidth")
 		for _, nodeBandwidthsPair := range nodeBandwidthsPairs {
-			bandwidths := strings.Split(strings.TrimSpace(nodeBandwidthsPair[1]), "\t")
+			var bandwidths []string
+			for _, field := range strings.Fields(nodeBandwidthsPair[1]) {
+				bandwidth, err := strconv.ParseFloat(field, 64)
+				if err != nil {
+					log.Printf("Unable to convert bandwidth to float: %s", field)
+					bandwidths = append(bandwidths, field)
+					continue
+				}
+				bandwidths = append(bandwidths, fmt.Sprintf("%.1f", bandwidth*scale))
+			}
 			hostValues.Values = append(hostValues.Values, []string{nodeBandwidthsPair[0], strings.Join(bandwidths, ",")})
 		}
 		table.AllHostValues = append(table.AllHostValues, hostValues)
@@ -144,6 +157,7 @@ func newMemoryBandwidthLatencyTable(sources []*Source, category TableCategory) (
 	 00002	261.63	 225040.5
 	 00008	261.54	 225073.3
 	 ...
+	   note: bandwidth may be reported in GB/s, see getMLCBandwidthScale
 	*/
 	for _, source := range sources {
 		var hostValues = HostValues{
@@ -154,7 +168,8 @@ func newMemoryBandwidthLatencyTable(sources []*Source, category TableCategory) (
 			},
 			Values: [][]string{},
 		}
-		latencyBandwidthPairs := source.valsArrayFromRegexSubmatch("Memory MLC Loaded Latency Test", `^[0-9]*\s*([0-9]*\.[0-9]+)\s*([0-9]*\.[0-9]+)`)
+		latencyBandwidthPairs := source.valsArrayFromRegexSubmatch("Memory MLC Loaded Latency Test", `^[0-9]+\s+([0-9]*\.?[0-9]+)\s+([0-9]*\.?[0-9]+)`)
+		scale := source.getMLCBandwidthScale("Memory MLC Loaded Latency Test")
 		for _, latencyBandwidth := range latencyBandwidthPairs {
 			latency := latencyBandwidth[0]
 			bandwidth, err := strconv.ParseFloat(latencyBandwidth[1], 32)
@@ -162,7 +177,7 @@ func newMemoryBandwidthLatencyTable(sources []*Source, category TableCategory) (
 				log.Printf("Unable to convert bandwidth to float: %s", latencyBandwidth[1])
 				continue
 			}
-			bandwidth = bandwidth / 1000
+			bandwidth = bandwidth * scale / 1000
 			// insert into beginning of array (reverse order)
 			vals := []string{latency, fmt.Sprintf("%.3f", bandwidth)}
 			hostValues.Values = append([][]string{vals}, hostValues.Values...)
@@ -184,8 +199,7 @@ func newNetworkIRQTable(sources []*Source, category TableCategory) (table *Table
 			ValueNames: []string{"Interface", "CPU:IRQs CPU:IRQs ..."},
 			Values:     [][]string{},
 		}
-		nics := source.valsFromRegexSubmatch("lshw", `^pci.*? (\S+)\s+network\s+\S.*?\s+\[\w+:\w+]$`)
-		nics = append(nics, source.valsFromRegexSubmatch("lshw", `^usb.*? (\S+)\s+network\s+\S.*?$`)...)
+		nics := source.getNICs()
 		for _, nic := range nics {
 			cmdout := source.valFromOutputRegexSubmatch("nic info", fmt.Sprintf(`CPU AFFINITY %s: (.*)\n`, nic))
 			// command output is formatted like this: 200:1;201:1-17,36-53;202:44
@@ -233,6 +247,31 @@ func newNetworkIRQTable(sources []*Source, category TableCategory) (table *Table
 	return
 }
 
+func newNICQueueStatsTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "NIC Queue Stats",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"Interface",
+				"Queue",
+				"RX Packets",
+				"RX Drops",
+				"TX Packets",
+				"TX Drops",
+				"Status",
+			},
+			Values: source.getNICQueueStats(),
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
 func newFrequencyTable(sources []*Source, CPUdb cpudb.CPUDB, category TableCategory) (table *Table) {
 	table = &Table{
 		Name:          "Core Frequency",
@@ -240,14 +279,8 @@ func newFrequencyTable(sources []*Source, CPUdb cpudb.CPUDB, category TableCateg
 		AllHostValues: []HostValues{},
 	}
 	for _, source := range sources {
-		family := source.valFromRegexSubmatch("lscpu", `^CPU family.*:\s*([0-9]+)$`)
-		model := source.valFromRegexSubmatch("lscpu", `^Model.*:\s*([0-9]+)$`)
-		stepping := source.valFromRegexSubmatch("lscpu", `^Stepping.*:\s*(.+)$`)
-		sockets := source.valFromRegexSubmatch("lscpu", `^Socket\(.*:\s*(.+?)$`)
-		capid4 := source.valFromRegexSubmatch("lspci bits", `^([0-9a-fA-F]+)`)
-		devices := source.valFromRegexSubmatch("lspci devices", `^([0-9]+)`)
 		var microarchitecture string
-		cpu, err := CPUdb.GetCPU(family, model, stepping, capid4, sockets, devices)
+		cpu, err := getCPU(CPUdb, source)
 		if err != nil {
 			log.Print("failed to find cpu in CPU db")
 		} else {
@@ -363,11 +396,17 @@ func newHostTable(sources []*Source, category TableCategory) (table *Table) {
 			ValueNames: []string{
 				"Name",
 				"Time",
+				"Clocksource",
+				"Available Clocksources",
+				"TSC",
 			},
 			Values: [][]string{
 				{
 					source.valFromRegexSubmatch("uname -a", `^Linux (\S+) \S+`),
 					source.valFromRegexSubmatch("date -u", `^(.*UTC\s*[0-9]*)$`),
+					source.getCommandOutputLine("current_clocksource"),
+					source.getCommandOutputLine("available_clocksource"),
+					source.getTSCStability(),
 				},
 			},
 		}
@@ -577,6 +616,36 @@ func newBIOSTable(sources []*Source, category TableCategory) (table *Table) {
 	return
 }
 
+func newBIOSSettingsTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "BIOS Settings",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"Name",
+				"Display Name",
+				"Value",
+			},
+			Values: [][]string{},
+		}
+		// no output on platforms that don't expose their BIOS attributes through the
+		// firmware-attributes class, e.g., name|display name|value
+		for _, line := range source.getCommandOutputLines("bios settings") {
+			fields := strings.SplitN(line, "|", 3)
+			if len(fields) != 3 {
+				continue
+			}
+			hostValues.Values = append(hostValues.Values, fields)
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
 func newBIOSSummaryTable(tableBIOS *Table, category TableCategory) (table *Table) {
 	table = &Table{
 		Name:          "BIOS",
@@ -699,6 +768,134 @@ func newSoftwareTable(sources []*Source, category TableCategory) (table *Table)
 	return
 }
 
+func newServicesTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "Services",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"Name",
+				"Load",
+				"Active",
+				"Sub",
+				"Description",
+			},
+			Values: [][]string{},
+		}
+		// no output on systems without systemd
+		for _, line := range source.getCommandOutputLines("systemctl services") {
+			// failed units may be marked with a leading bullet
+			fields := strings.Fields(strings.TrimPrefix(line, "●"))
+			if len(fields) < 4 {
+				continue
+			}
+			hostValues.Values = append(hostValues.Values, []string{
+				fields[0],
+				fields[1],
+				fields[2],
+				fields[3],
+				strings.Join(fields[4:], " "),
+			})
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
+func newContainersTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "Containers",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"Runtime",
+				"Container ID",
+				"Image",
+				"State",
+				"Names",
+			},
+			// empty when neither docker nor crictl is installed
+			Values: source.getContainers(),
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
+func newKernelModulesTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "Kernel Modules",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	// parameters are listed for the modules of interest, only
+	var modulesOfInterest []string
+	yamlBytes, err := resources.ReadFile("resources/kernel_modules.yaml")
+	if err != nil {
+		log.Printf("failed to read kernel_modules.yaml: %v", err)
+	} else if err = yaml.UnmarshalStrict(yamlBytes, &modulesOfInterest); err != nil {
+		log.Printf("failed to parse kernel_modules.yaml: %v", err)
+	}
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"Name",
+				"Size",
+				"Used By",
+				"Parameters",
+			},
+			Values: source.getKernelModules(modulesOfInterest),
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
+// loadTunedProfiles reads the tuned profiles expected for each workload class
+func loadTunedProfiles() (profiles map[string][]string, err error) {
+	yamlBytes, err := resources.ReadFile("resources/tuned_profiles.yaml")
+	if err != nil {
+		return
+	}
+	err = yaml.UnmarshalStrict(yamlBytes, &profiles)
+	return
+}
+
+// newTunedTable lists the active tuned profile and the kernel's scheduler sysctl settings
+func newTunedTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "Tuned",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name:       source.getHostname(),
+			ValueNames: []string{"Active Profile"},
+			Values:     [][]string{{source.getTunedProfile()}},
+		}
+		for _, line := range source.getCommandOutputLines("sysctl sched") {
+			name, value, found := strings.Cut(line, "=")
+			if !found {
+				continue
+			}
+			hostValues.ValueNames = append(hostValues.ValueNames, strings.TrimSpace(name))
+			hostValues.Values[0] = append(hostValues.Values[0], strings.TrimSpace(value))
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
 func newUncoreTable(sources []*Source, CPUdb cpudb.CPUDB, category TableCategory) (table *Table) {
 	table = &Table{
 		Name:          "Uncore",
@@ -706,14 +903,8 @@ func newUncoreTable(sources []*Source, CPUdb cpudb.CPUDB, category TableCategory
 		AllHostValues: []HostValues{},
 	}
 	for _, source := range sources {
-		family := source.valFromRegexSubmatch("lscpu", `^CPU family.*:\s*([0-9]+)$`)
-		model := source.valFromRegexSubmatch("lscpu", `^Model.*:\s*([0-9]+)$`)
-		stepping := source.valFromRegexSubmatch("lscpu", `^Stepping.*:\s*(.+)$`)
-		sockets := source.valFromRegexSubmatch("lscpu", `^Socket\(.*:\s*(.+?)$`)
-		capid4 := source.valFromRegexSubmatch("lspci bits", `^([0-9a-fA-F]+)`)
-		devices := source.valFromRegexSubmatch("lspci devices", `^([0-9]+)`)
 		var microarchitecture string
-		cpu, err := CPUdb.GetCPU(family, model, stepping, capid4, sockets, devices)
+		cpu, err := getCPU(CPUdb, source)
 		if err != nil {
 			log.Print("failed to find cpu in CPU db")
 		} else {
@@ -750,13 +941,11 @@ func newCPUTable(sources []*Source, CPUdb cpudb.CPUDB, category TableCategory) (
 		model := source.valFromRegexSubmatch("lscpu", `^Model.*:\s*([0-9]+)$`)
 		stepping := source.valFromRegexSubmatch("lscpu", `^Stepping.*:\s*(.+)$`)
 		sockets := source.valFromRegexSubmatch("lscpu", `^Socket\(.*:\s*(.+?)$`)
-		capid4 := source.valFromRegexSubmatch("lspci bits", `^([0-9a-fA-F]+)`)
-		devices := source.valFromRegexSubmatch("lspci devices", `^([0-9]+)`)
 		coresPerSocket := source.valFromRegexSubmatch("lscpu", `^Core\(s\) per socket.*:\s*(.+?)$`)
 		cpus := source.valFromRegexSubmatch("lscpu", `^CPU\(.*:\s*(.+?)$`)
 		var microarchitecture string
 		var channels string
-		cpu, err := CPUdb.GetCPU(family, model, stepping, capid4, sockets, devices)
+		cpu, err := getCPU(CPUdb, source)
 		if err == nil {
 			microarchitecture = cpu.Architecture
 			channels = fmt.Sprintf("%d", cpu.Channels)
@@ -805,8 +994,8 @@ func newCPUTable(sources []*Source, CPUdb cpudb.CPUDB, category TableCategory) (
 					source.getAllCoreMaxFrequency(microarchitecture),
 					cpus,
 					source.valFromRegexSubmatch("lscpu", `^On-line CPU.*:\s*(.+?)$`),
-					getHyperthreading(CPUdb, family, model, stepping, sockets, cpus, coresPerSocket),
-					coresPerSocket,
+					getHyperthreading(CPUdb, source, sockets, cpus, coresPerSocket),
+					source.getCoresPerSocket(coresPerSocket),
 					sockets,
 					source.valFromRegexSubmatch("lscpu", `^NUMA node\(.*:\s*(.+?)$`),
 					source.getNUMACPUList(),
@@ -992,21 +1181,36 @@ func newPowerTable(sources []*Source, category TableCategory) (table *Table) {
 		AllHostValues: []HostValues{},
 	}
 	for _, source := range sources {
+		pl1, pl1Window, pl2, pl2Window := source.getRAPLPowerLimits()
 		var hostValues = HostValues{
 			Name: source.getHostname(),
 			ValueNames: []string{
 				"TDP",
+				"PL1",
+				"PL1 Time Window",
+				"PL2",
+				"PL2 Time Window",
 				"Power & Perf Policy",
 				"Frequency Governor",
 				"Frequency Driver",
+				"Frequency Driver Status",
+				"HWP",
+				"EPP",
 				"Max C-State",
 			},
 			Values: [][]string{
 				{
 					source.getTDP(),
+					pl1,
+					pl1Window,
+					pl2,
+					pl2Window,
 					source.getPowerPerfPolicy(),
 					source.getCommandOutputLine("cpu_freq_governor"),
 					source.getCommandOutputLine("cpu_freq_driver"),
+					source.getCommandOutputLine("intel_pstate status"),
+					source.getHWP(),
+					source.getEnergyPerformancePreference(),
 					source.getCommandOutputLine("max_cstate"),
 				},
 			},
@@ -1032,6 +1236,22 @@ func newEfficiencyLatencyControlTable(sources []*Source, category TableCategory)
 	return
 }
 
+func newSSTTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "SST-PP",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+		}
+		hostValues.ValueNames, hostValues.Values = source.getSSTPerfProfiles()
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
 func newEfficiencyLatencyControlSummaryTable(tableELC *Table, category TableCategory) (table *Table) {
 	table = &Table{
 		Name:          "Efficiency Latency Control",
@@ -1083,12 +1303,15 @@ func newGPUTable(sources []*Source, category TableCategory) (table *Table) {
 		return
 	}
 	for _, source := range sources {
+		// details from the GPU vendors' tools, when installed, by PCI address
+		details := source.getGPUDetails()
 		// get all GPUs from lshw
 		var gpus [][]string
-		gpusLshw := source.valsArrayFromRegexSubmatch("lshw", `^pci.*?\s+display\s+(\w+).*?\s+\[(\w+):(\w+)]$`)
-		idxMfgName := 0
-		idxMfgID := 1
-		idxDevID := 2
+		gpusLshw := source.valsArrayFromRegexSubmatch("lshw", `^pci@(\S+)\s+.*?\s+display\s+(\w+).*?\s+\[(\w+):(\w+)]$`)
+		idxAddress := 0
+		idxMfgName := 1
+		idxMfgID := 2
+		idxDevID := 3
 		for _, gpu := range gpusLshw {
 			// Find GPU in GPU defs, note the model
 			var model string
@@ -1113,7 +1336,20 @@ func newGPUTable(sources []*Source, category TableCategory) (table *Table) {
 					model = "Unknown"
 				}
 			}
-			gpus = append(gpus, []string{gpu[idxMfgName], model, gpu[idxMfgID] + ":" + gpu[idxDevID]})
+			var driver, firmware, memory string
+			address := normalizePCIAddress(gpu[idxAddress])
+			if i := slices.IndexFunc(details, func(d GPUDetails) bool { return d.PCIAddress == address }); i != -1 {
+				if strings.HasPrefix(model, "Unknown") && details[i].Model != "" {
+					model = details[i].Model
+				}
+				driver, firmware, memory = details[i].Driver, details[i].Firmware, details[i].Memory
+				details = slices.Delete(details, i, i+1)
+			}
+			gpus = append(gpus, []string{gpu[idxMfgName], model, gpu[idxMfgID] + ":" + gpu[idxDevID], driver, firmware, memory})
+		}
+		// GPUs reported by the vendors' tools but not by lshw, e.g., lshw wasn't collected
+		for _, d := range details {
+			gpus = append(gpus, []string{d.Manufacturer, d.Model, "", d.Driver, d.Firmware, d.Memory})
 		}
 		var hostValues = HostValues{
 			Name: source.getHostname(),
@@ -1121,6 +1357,9 @@ func newGPUTable(sources []*Source, category TableCategory) (table *Table) {
 				"Manufacturer",
 				"Model",
 				"PCI ID",
+				"Driver Version",
+				"Firmware",
+				"Memory",
 			},
 			Values: gpus,
 		}
@@ -1378,6 +1617,85 @@ func newMemoryTable(sources []*Source, tableDIMM *Table, tableDIMMPopulation *Ta
 	return
 }
 
+// newDIMMPopulationBalanceTable summarizes the DIMM Population for the DIMMPopulationBalance
+// insight, which also compares the populated channels to the CPU's memory channels
+func newDIMMPopulationBalanceTable(sources []*Source, tableDIMMPopulation *Table, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "DIMM Population Balance",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for sourceIdx, source := range sources {
+		channelsPerSocket, channelCapacities, balanced := getDIMMPopulationBalance(tableDIMMPopulation, sourceIdx)
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"Populated Channels",
+				"Populated Channels per Socket",
+				"Channel Capacity",
+				"Balanced",
+			},
+			Values: [][]string{
+				{
+					getPopulatedMemoryChannels(tableDIMMPopulation, sourceIdx),
+					channelsPerSocket,
+					channelCapacities,
+					balanced,
+				},
+			},
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
+func newHugepagesTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "Hugepages",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"Node",
+				"2M Pages",
+				"1G Pages",
+			},
+			Values: source.getHugepagesPerNode(),
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
+func newNUMAStatsTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "NUMA Stats",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"Node",
+				"NUMA Hit",
+				"NUMA Miss",
+				"Other Node",
+				"Miss %",
+				"Memory Total",
+				"Memory Free",
+				"Memory Used",
+			},
+			Values: source.getNUMAStats(),
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
 func newMemoryBriefTable(tableMemory *Table, category TableCategory) (table *Table) {
 	table = &Table{
 		Name:          "Memory",
@@ -1501,6 +1819,109 @@ func newPCIeSlotsTable(sources []*Source, category TableCategory) (table *Table)
 	return
 }
 
+// parsePCIeLinkSpeed returns the link speed in GT/s, e.g., 16 for "16GT/s"
+func parsePCIeLinkSpeed(speed string) (gts float64, err error) {
+	gts, err = strconv.ParseFloat(strings.TrimSuffix(speed, "GT/s"), 64)
+	return
+}
+
+// parsePCIeLinkWidth returns the number of lanes, e.g., 16 for "x16"
+func parsePCIeLinkWidth(width string) (lanes int, err error) {
+	lanes, err = strconv.Atoi(strings.TrimPrefix(width, "x"))
+	return
+}
+
+func newPCIeLinkTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "PCIe Link",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	reDevice := regexp.MustCompile(`^((?:[0-9a-fA-F]{4}:)?[0-9a-fA-F]{2}:[0-9a-fA-F]{2}\.[0-9a-fA-F])\s+(.+)$`)
+	reLnkCap := regexp.MustCompile(`^LnkCap:.*Speed ([^,\s]+).*, Width (x\d+)`)
+	reLnkSta := regexp.MustCompile(`^LnkSta:\s*Speed ([^,\s]+).*, Width (x\d+)`)
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"Address",
+				"Device",
+				"Capable Speed",
+				"Capable Width",
+				"Negotiated Speed",
+				"Negotiated Width",
+				"Status",
+			},
+			Values: [][]string{},
+		}
+		// device fields: address, device, capable speed, capable width, negotiated speed, negotiated width
+		var device []string
+		addDevice := func() {
+			// only PCIe devices report link capability and status
+			if device != nil && device[2] != "" && device[4] != "" {
+				status := "OK"
+				capSpeed, errCapSpeed := parsePCIeLinkSpeed(device[2])
+				staSpeed, errStaSpeed := parsePCIeLinkSpeed(device[4])
+				capWidth, errCapWidth := parsePCIeLinkWidth(device[3])
+				staWidth, errStaWidth := parsePCIeLinkWidth(device[5])
+				if errCapSpeed != nil || errStaSpeed != nil || errCapWidth != nil || errStaWidth != nil {
+					status = "Unknown"
+				} else if staSpeed < capSpeed || staWidth < capWidth {
+					status = "Degraded"
+				}
+				hostValues.Values = append(hostValues.Values, append(device, status))
+			}
+			device = nil
+		}
+		for _, line := range source.getCommandOutputLines("lspci -vvv") {
+			if match := reDevice.FindStringSubmatch(line); match != nil {
+				addDevice()
+				device = []string{match[1], match[2], "", "", "", ""}
+			} else if device == nil {
+				continue
+			} else if match := reLnkCap.FindStringSubmatch(line); match != nil {
+				device[2] = match[1]
+				device[3] = match[2]
+			} else if match := reLnkSta.FindStringSubmatch(line); match != nil {
+				device[4] = match[1]
+				device[5] = match[2]
+			}
+		}
+		addDevice()
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
+func newPCIeLinkSummaryTable(tablePCIeLink *Table, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "PCIe Link Summary",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	statusValIdx := 6
+	for _, hv := range tablePCIeLink.AllHostValues {
+		var devices, degraded string
+		if len(hv.Values) > 0 {
+			degradedCount := 0
+			for _, device := range hv.Values {
+				if device[statusValIdx] == "Degraded" {
+					degradedCount++
+				}
+			}
+			devices = fmt.Sprintf("%d", len(hv.Values))
+			degraded = fmt.Sprintf("%d", degradedCount)
+		}
+		var summaryHv = HostValues{
+			Name:       hv.Name,
+			ValueNames: []string{"PCIe Devices", "Below Capable Link"},
+			Values:     [][]string{{devices, degraded}},
+		}
+		table.AllHostValues = append(table.AllHostValues, summaryHv)
+	}
+	return
+}
+
 func newDIMMPopulationTable(sources []*Source, dimmTable *Table, CPUdb cpudb.CPUDB, category TableCategory) (table *Table) {
 	table = &Table{
 		Name:          "DIMM Population",
@@ -1520,13 +1941,7 @@ func newDIMMPopulationTable(sources []*Source, dimmTable *Table, CPUdb cpudb.CPU
 			hv.Values[valuesIdx] = append(hv.Values[valuesIdx], []string{"", "", ""}...)
 		}
 		success := false
-		family := source.valFromRegexSubmatch("lscpu", `^CPU family.*:\s*([0-9]+)$`)
-		model := source.valFromRegexSubmatch("lscpu", `^Model.*:\s*([0-9]+)$`)
-		stepping := source.valFromRegexSubmatch("lscpu", `^Stepping.*:\s*(.+)$`)
-		sockets := source.valFromRegexSubmatch("lscpu", `^Socket\(.*:\s*(.+?)$`)
-		capid4 := source.valFromRegexSubmatch("lspci bits", `^([0-9a-fA-F]+)`)
-		devices := source.valFromRegexSubmatch("lspci devices", `^([0-9]+)`)
-		cpu, err := CPUdb.GetCPU(family, model, stepping, capid4, sockets, devices)
+		cpu, err := getCPU(CPUdb, source)
 		if err != nil {
 			log.Printf("Failed to find CPU info: %v", err)
 		} else {
@@ -1568,14 +1983,15 @@ func newDIMMPopulationTable(sources []*Source, dimmTable *Table, CPUdb cpudb.CPU
 	return
 }
 
-func newBenchmarkSummaryTable(sources []*Source, tableMemBandwidthLatency *Table, category TableCategory) (table *Table) {
+func newBenchmarkSummaryTable(sources []*Source, tableMemBandwidthLatency *Table, tableDIMM *Table, tableMemory *Table, category TableCategory) (table *Table) {
 	table = &Table{
 		Name:          "Summary",
 		Category:      category,
 		AllHostValues: []HostValues{},
 	}
-	for _, source := range sources {
+	for sourceIdx, source := range sources {
 		singleCoreTurbo, allCoreTurbo, turboPower, turboTemperature := source.getTurbo()
+		peakBandwidth := source.getPeakBandwidth(tableMemBandwidthLatency)
 		var hostValues = HostValues{
 			Name: source.getHostname(),
 			ValueNames: []string{
@@ -1588,6 +2004,7 @@ func newBenchmarkSummaryTable(sources []*Source, tableMemBandwidthLatency *Table
 				"Memory Peak Bandwidth",
 				"Memory Minimum Latency",
 				"Disk Speed",
+				"Memory Bandwidth Efficiency",
 			},
 			Values: [][]string{
 				{
@@ -1597,9 +2014,10 @@ func newBenchmarkSummaryTable(sources []*Source, tableMemBandwidthLatency *Table
 					turboPower,            // all-core turbo power
 					turboTemperature,      // all-core turbo temperature
 					source.getIdlePower(), // idle power
-					source.getPeakBandwidth(tableMemBandwidthLatency), // peak memory bandwidth
-					source.getMinLatency(tableMemBandwidthLatency),    // minimum memory latency
-					source.getDiskSpeed(),                             // disk speed
+					peakBandwidth,         // peak memory bandwidth
+					source.getMinLatency(tableMemBandwidthLatency), // minimum memory latency
+					source.getDiskSpeed(),                          // disk speed
+					getMemoryBandwidthEfficiency(tableDIMM, tableMemory, sourceIdx, peakBandwidth), // measured vs. theoretical peak memory bandwidth
 				},
 			},
 		}
@@ -1633,6 +2051,7 @@ func newDiskTable(sources []*Source, category TableCategory) (table *Table) {
 				"Link Width",
 				"Max Link Speed",
 				"Max Link Width",
+				"Serial",
 			},
 			Values: [][]string{},
 		}
@@ -1657,6 +2076,7 @@ func newDiskTable(sources []*Source, category TableCategory) (table *Table) {
 			if fields[7] == "" {
 				fields[7] = source.getDiskFwRev(fields[0])
 			}
+			fields = append(fields, source.getDiskSerial(fields[0]))
 			hostValues.Values = append(hostValues.Values, fields)
 		}
 		table.AllHostValues = append(table.AllHostValues, hostValues)
@@ -1738,7 +2158,12 @@ func newFilesystemTable(sources []*Source, category TableCategory) (table *Table
 	return
 }
 
-func newProcessTable(sources []*Source, category TableCategory) (table *Table) {
+// processSortColumns maps the -process-sort keys to the ps output's column names
+var processSortColumns = map[string]string{"cpu": "%CPU", "mem": "%MEM"}
+
+// newProcessTable lists the processes from ps, when top is greater than zero only the top
+// processes by sortKey (cpu or mem) are included
+func newProcessTable(sources []*Source, top int, sortKey string, category TableCategory) (table *Table) {
 	table = &Table{
 		Name:          "Process",
 		Category:      category,
@@ -1767,6 +2192,26 @@ func newProcessTable(sources []*Source, category TableCategory) (table *Table) {
 			}
 			hostValues.Values = append(hostValues.Values, fields)
 		}
+		if top > 0 {
+			if sortIdx, err := findValueIndex(&hostValues, processSortColumns[sortKey]); err == nil {
+				percent := func(record []string) float64 {
+					value, err := strconv.ParseFloat(record[sortIdx], 64)
+					if err != nil {
+						return 0
+					}
+					return value
+				}
+				sort.SliceStable(hostValues.Values, func(i, j int) bool {
+					return percent(hostValues.Values[i]) > percent(hostValues.Values[j])
+				})
+				if len(hostValues.Values) > top {
+					hostValues.Values = hostValues.Values[:top]
+				}
+			} else {
+				// the top processes are unknown, e.g., the column isn't in the ps output
+				log.Printf("Warning: process table not sorted: %v", err)
+			}
+		}
 		table.AllHostValues = append(table.AllHostValues, hostValues)
 	}
 	return
@@ -1814,7 +2259,10 @@ func newPMUTable(sources []*Source, category TableCategory) (table *Table) {
 	return
 }
 
-func newVulnerabilityTable(sources []*Source, category TableCategory) (table *Table) {
+// newVulnerabilityTable lists each host's vulnerability status. When there is a policy, the
+// Compliant value is "Yes" or "No" followed by the vulnerabilities whose status doesn't contain
+// the policy's expected substring, e.g., No (CVE-2017-5753, CVE-2018-3639).
+func newVulnerabilityTable(sources []*Source, policy VulnerabilityPolicy, category TableCategory) (table *Table) {
 	table = &Table{
 		Name:          "Vulnerability",
 		Category:      category,
@@ -1838,6 +2286,25 @@ func newVulnerabilityTable(sources []*Source, category TableCategory) (table *Ta
 			hostValues.ValueNames = append(hostValues.ValueNames, k)
 			values = append(values, vulns[k])
 		}
+		// no status to compare when the vulnerabilities weren't collected
+		if len(values) > 0 && len(policy) > 0 {
+			var policyKeys, nonCompliant []string
+			for k := range policy {
+				policyKeys = append(policyKeys, k)
+			}
+			sort.Strings(policyKeys)
+			for _, k := range policyKeys {
+				if status, ok := vulns[k]; !ok || !strings.Contains(status, policy[k]) {
+					nonCompliant = append(nonCompliant, k)
+				}
+			}
+			compliant := "Yes"
+			if len(nonCompliant) > 0 {
+				compliant = "No"
+			}
+			hostValues.ValueNames = append(hostValues.ValueNames, "Compliant", "Non-Compliant")
+			values = append(values, compliant, strings.Join(nonCompliant, ", "))
+		}
 		if len(values) > 0 {
 			hostValues.Values = append(hostValues.Values, []string{})
 			hostValues.Values[0] = values
@@ -1847,6 +2314,20 @@ func newVulnerabilityTable(sources []*Source, category TableCategory) (table *Ta
 	return
 }
 
+// VulnerabilityPolicy maps each vulnerability, e.g., CVE-2017-5753, to a substring expected in its status,
+// e.g., "OK" or "Mitigation: usercopy/swapgs barriers"
+type VulnerabilityPolicy map[string]string
+
+// loadVulnerabilityPolicy reads the vulnerability policy from a YAML file
+func loadVulnerabilityPolicy(path string) (policy VulnerabilityPolicy, err error) {
+	yamlBytes, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	err = yaml.UnmarshalStrict(yamlBytes, &policy)
+	return
+}
+
 func newVulnerabilitySummaryTable(tableVuln *Table, category TableCategory) (table *Table) {
 	table = &Table{
 		Name:          "Vulnerability",
@@ -1857,6 +2338,10 @@ func newVulnerabilitySummaryTable(tableVuln *Table, category TableCategory) (tab
 	for _, hv := range tableVuln.AllHostValues {
 		var vulns []string
 		for valIdx, valueName := range hv.ValueNames {
+			// the policy compliance isn't a vulnerability
+			if valueName == "Compliant" || valueName == "Non-Compliant" {
+				continue
+			}
 			longValue := hv.Values[0][valIdx]
 			match := re.FindStringSubmatch(longValue)
 			if match != nil {
@@ -1902,6 +2387,67 @@ func newSensorTable(sources []*Source, category TableCategory) (table *Table) {
 	return
 }
 
+func newBMCTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "BMC",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"Manager",
+				"Model",
+				"Firmware Version",
+				"Interface",
+				"MAC Address",
+				"Host Name",
+				"IPv4 Address",
+				"Subnet Mask",
+				"Gateway",
+				"Address Origin",
+			},
+			Values: [][]string{},
+		}
+		// one row per BMC network interface, or one row per BMC if it has no interfaces
+		for _, manager := range source.getRedfishManagers() {
+			if len(manager.EthernetInterfaces) == 0 {
+				hostValues.Values = append(hostValues.Values, []string{
+					manager.Manager.ID,
+					manager.Manager.Model,
+					manager.Manager.FirmwareVersion,
+					"", "", "", "", "", "", "",
+				})
+				continue
+			}
+			for _, iface := range manager.EthernetInterfaces {
+				var address, subnetMask, gateway, addressOrigin string
+				if len(iface.IPv4Addresses) > 0 {
+					address = iface.IPv4Addresses[0].Address
+					subnetMask = iface.IPv4Addresses[0].SubnetMask
+					gateway = iface.IPv4Addresses[0].Gateway
+					addressOrigin = iface.IPv4Addresses[0].AddressOrigin
+				}
+				hostValues.Values = append(hostValues.Values, []string{
+					manager.Manager.ID,
+					manager.Manager.Model,
+					manager.Manager.FirmwareVersion,
+					iface.ID,
+					iface.MACAddress,
+					iface.HostName,
+					address,
+					subnetMask,
+					gateway,
+					addressOrigin,
+				})
+			}
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
 func newChassisStatusTable(sources []*Source, category TableCategory) (table *Table) {
 	table = &Table{
 		Name:          "Chassis Status",
@@ -1973,7 +2519,8 @@ func newSystemEventLogTable(sources []*Source, category TableCategory) (table *T
 	return
 }
 
-func newKernelLogTable(sources []*Source, category TableCategory) (table *Table) {
+// newKernelLogTable includes only the last maxLines lines of the kernel log, or all lines if maxLines is 0
+func newKernelLogTable(sources []*Source, maxLines int, category TableCategory) (table *Table) {
 	table = &Table{
 		Name:          "Kernel Log",
 		Category:      category,
@@ -1987,7 +2534,11 @@ func newKernelLogTable(sources []*Source, category TableCategory) (table *Table)
 			},
 			Values: [][]string{},
 		}
-		for _, line := range source.getCommandOutputLines("dmesg") {
+		lines := source.getCommandOutputLines("dmesg")
+		if maxLines > 0 && len(lines) > maxLines {
+			lines = lines[len(lines)-maxLines:]
+		}
+		for _, line := range lines {
 			hostValues.Values = append(hostValues.Values, []string{line})
 		}
 		table.AllHostValues = append(table.AllHostValues, hostValues)
@@ -2239,6 +2790,78 @@ func newPowerStatsTable(sources []*Source, category TableCategory) (table *Table
 	}
 	return
 }
+
+func newCStateResidencyTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "C-State Residency",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	residencyNames := []string{"CPU%c1", "CPU%c6", "CPU%c7", "Pkg%pc2", "Pkg%pc6"}
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name:       source.getHostname(),
+			ValueNames: append([]string{"CPU"}, residencyNames...),
+			Values:     [][]string{},
+		}
+		// turbostat prints a header line, a summary line ("-"), and then one line per CPU for
+		// each sample. Columns that aren't supported by the CPU aren't printed and package
+		// columns are only printed on the first CPU of each package, so map fields by header.
+		var header []string
+		var cpus []string
+		sums := make(map[string][]float64) // CPU: sum of each residency
+		counts := make(map[string][]int)   // CPU: count of each residency
+		for _, line := range source.getProfileLines("cstate") {
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			if fields[0] == "CPU" {
+				header = fields
+				continue
+			}
+			if header == nil {
+				continue
+			}
+			cpu := fields[0]
+			if _, err := strconv.Atoi(cpu); err != nil {
+				continue // skip summary line
+			}
+			if _, ok := sums[cpu]; !ok {
+				cpus = append(cpus, cpu)
+				sums[cpu] = make([]float64, len(residencyNames))
+				counts[cpu] = make([]int, len(residencyNames))
+			}
+			for fieldIdx := 1; fieldIdx < len(fields) && fieldIdx < len(header); fieldIdx++ {
+				for nameIdx, name := range residencyNames {
+					if header[fieldIdx] != name {
+						continue
+					}
+					val, err := strconv.ParseFloat(fields[fieldIdx], 64)
+					if err != nil {
+						break
+					}
+					sums[cpu][nameIdx] += val
+					counts[cpu][nameIdx]++
+				}
+			}
+		}
+		for _, cpu := range cpus {
+			values := []string{cpu}
+			for nameIdx := range residencyNames {
+				if counts[cpu][nameIdx] > 0 {
+					values = append(values, fmt.Sprintf("%.2f", sums[cpu][nameIdx]/float64(counts[cpu][nameIdx])))
+				} else {
+					values = append(values, "")
+				}
+			}
+			hostValues.Values = append(hostValues.Values, values)
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
 func newProfileSummaryTable(sources []*Source, category TableCategory, averageCPUUtilizationTable, driveStatsTable, netStatsTable, memStatsTable, PMUMetricsTable, powerStatsTable *Table) (table *Table) {
 	table = &Table{
 		Name:          "Summary",
@@ -2323,6 +2946,141 @@ func newCXLDeviceTable(sources []*Source, category TableCategory) (table *Table)
 	return
 }
 
+// newCXLMemoryDeviceTable lists the CXL memory devices reported by cxl-cli, the table is empty
+// when cxl-cli isn't installed, see the CXL Device table for the CXL devices found on the PCI bus
+func newCXLMemoryDeviceTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "CXL Memory Device",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"Memdev",
+				"Host",
+				"Serial #",
+				"NUMA Node",
+				"Capacity",
+				"Volatile",
+				"Persistent",
+				"Volatile QoS Class",
+				"Persistent QoS Class",
+			},
+			Values: source.getCXLMemoryDevices(),
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
+// newHardwareChangesTable lists the System, DIMM, Disk, and NIC components that were added,
+// removed, or changed since the baseline snapshot, see -baseline
+func newHardwareChangesTable(tableSystem *Table, tableDIMM *Table, tableDisk *Table, tableNIC *Table, baseline *Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "Hardware Changes",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	baselineSources := []*Source{baseline}
+	baselineComponents := getHardwareComponents(
+		newSystemTable(baselineSources, category),
+		newDIMMTable(baselineSources, category),
+		newDiskTable(baselineSources, category),
+		newNICTable(baselineSources, category),
+		0,
+	)
+	for sourceIdx, hv := range tableSystem.AllHostValues {
+		var hostValues = HostValues{
+			Name: hv.Name,
+			ValueNames: []string{
+				"Component",
+				"Change",
+				"Identifier",
+				"Baseline",
+				"Current",
+			},
+			Values: getHardwareChanges(baselineComponents, getHardwareComponents(tableSystem, tableDIMM, tableDisk, tableNIC, sourceIdx)),
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
+func newIOMMUTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "IOMMU",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for _, source := range sources {
+		status, groups, domainTypes := source.getIOMMUGroups()
+		pfs := source.getSRIOVFunctions()
+		vfs := 0
+		for _, pf := range pfs {
+			if numVFs, err := strconv.Atoi(pf[2]); err == nil {
+				vfs += numVFs
+			}
+		}
+		var units, pfCount, vfCount string
+		if _, ok := source.ParsedData["iommu units"]; ok {
+			units = fmt.Sprintf("%d", len(source.getCommandOutputLines("iommu units")))
+		}
+		if _, ok := source.ParsedData["sriov"]; ok {
+			pfCount = fmt.Sprintf("%d", len(pfs))
+			vfCount = fmt.Sprintf("%d", vfs)
+		}
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"Kernel Parameters",
+				"Status",
+				"Units",
+				"Groups",
+				"Domain Type",
+				"SR-IOV PFs",
+				"VFs Configured",
+			},
+			Values: [][]string{
+				{
+					source.getIOMMUKernelParameters(),
+					status,
+					units,
+					groups,
+					domainTypes,
+					pfCount,
+					vfCount,
+				},
+			},
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
+func newSRIOVTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "SR-IOV",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"PF",
+				"Interface",
+				"VFs",
+				"Total VFs",
+			},
+			Values: source.getSRIOVFunctions(),
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
 func newCodePathTable(sources []*Source, category TableCategory) (table *Table) {
 	table = &Table{
 		Name:          "Code Path Frequency",
@@ -2348,7 +3106,31 @@ func newCodePathTable(sources []*Source, category TableCategory) (table *Table)
 	return
 }
 
-func newInsightTable(configReport, briefReport, profileReport, benchmarkReport *Report, analyzeReport *Report) (table *Table) {
+func newCacheContentionTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "Cache Line Contention",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for _, source := range sources {
+		hv := HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"Index",
+				"Cache Line Address",
+				"Node",
+				"HITM %",
+				"HITM Count",
+				"Symbols",
+			},
+			Values: source.getCacheContention(10),
+		}
+		table.AllHostValues = append(table.AllHostValues, hv)
+	}
+	return
+}
+
+func newInsightTable(configReport, briefReport, profileReport, benchmarkReport *Report, analyzeReport *Report, readinessPolicy BenchmarkReadinessPolicy, workloadClass string) (table *Table) {
 	table = &Table{
 		Name:          "Insight",
 		Category:      NoCategory,
@@ -2358,9 +3140,16 @@ func newInsightTable(configReport, briefReport, profileReport, benchmarkReport *
 	var knowledgeBase *ast.KnowledgeBase
 	var dataContext ast.IDataContext
 	rulesEngineContext := &RulesEngineContext{
-		insightTable: table,
-		reportsData:  []*Report{configReport, briefReport, profileReport, benchmarkReport, analyzeReport},
-		sourceIdx:    0, // will be incremented while looping through sources below
+		insightTable:    table,
+		reportsData:     []*Report{configReport, briefReport, profileReport, benchmarkReport, analyzeReport},
+		sourceIdx:       0, // will be incremented while looping through sources below
+		readinessPolicy: readinessPolicy,
+		workloadClass:   workloadClass,
+	}
+	if profiles, err := loadTunedProfiles(); err != nil {
+		log.Printf("failed to load tuned_profiles.yaml: %v", err)
+	} else {
+		rulesEngineContext.tunedProfiles = profiles[workloadClass]
 	}
 	gruleEngine = &engine.GruleEngine{MaxCycle: 500}
 	rules, err := getInsightsRules()
@@ -2438,6 +3227,53 @@ func newSvrinfoTable(sources []*Source, category TableCategory) (table *Table) {
 	return
 }
 
+func newNVMeHealthTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "NVMe Health",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"Device",
+				"Model",
+				"Critical Warning",
+				"Percentage Used",
+				"Temperature",
+				"Data Units Read",
+				"Data Units Written",
+				"Health",
+			},
+			Values: source.getNVMeHealth(),
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
+func newCollectionTimingTable(sources []*Source, category TableCategory) (table *Table) {
+	table = &Table{
+		Name:          "Collection Timing",
+		Category:      category,
+		AllHostValues: []HostValues{},
+	}
+	for _, source := range sources {
+		var hostValues = HostValues{
+			Name: source.getHostname(),
+			ValueNames: []string{
+				"Command",
+				"Duration (ms)",
+				"Exit Status",
+			},
+			Values: source.getCommandDurations(),
+		}
+		table.AllHostValues = append(table.AllHostValues, hostValues)
+	}
+	return
+}
+
 func newPMUMetricsTable(sources []*Source, category TableCategory) (table *Table) {
 	table = &Table{
 		Name:          "PMU Metrics",
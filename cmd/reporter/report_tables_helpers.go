@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -88,6 +89,166 @@ func getPopulatedMemoryChannels(tableDIMMPopulation *Table, sourceIdx int) strin
 	return ""
 }
 
+// getMemoryBandwidthEfficiency returns the measured peak memory bandwidth as a percentage of the
+// theoretical peak, i.e., populated memory channels x the DIMMs' configured speed x 8 bytes (64
+// bits) per transfer, or an empty string when any of them is unknown. Channels run at the speed of
+// the slowest DIMM.
+func getMemoryBandwidthEfficiency(tableDIMM *Table, tableMemory *Table, sourceIdx int, peakBandwidth string) string {
+	if tableDIMM == nil || tableMemory == nil || peakBandwidth == "" {
+		return ""
+	}
+	channels, err := tableMemory.getValue(sourceIdx, "Populated Memory Channels")
+	if err != nil || channels == "" {
+		return ""
+	}
+	numChannels, err := strconv.Atoi(channels)
+	if err != nil {
+		return ""
+	}
+	var speed float64 // MT/s
+	for _, dimm := range tableDIMM.AllHostValues[sourceIdx].Values {
+		dimmSpeed, err := parseNumeric(dimm[ConfiguredSpeedIdx], "MT/s")
+		if err != nil || dimmSpeed == 0 {
+			continue
+		}
+		if speed == 0 || dimmSpeed < speed {
+			speed = dimmSpeed
+		}
+	}
+	measured, err := parseNumeric(peakBandwidth, "")
+	if err != nil || speed == 0 {
+		return ""
+	}
+	theoretical := float64(numChannels) * speed * 8 / 1000 // GB/s
+	return fmt.Sprintf("%.1f%%", measured/theoretical*100)
+}
+
+// getMixedDIMMChannels returns a description of each derived memory channel with DIMMs of
+// differing type, size, or configured speed, listing the channel's DIMMs, e.g., socket 0 channel 1:
+// DIMM_A1 (DDR5, 32 GB, 4800 MT/s), DIMM_A2 (DDR5, 64 GB, 4400 MT/s)
+func getMixedDIMMChannels(tableDIMMPopulation *Table, sourceIdx int) (mixed []string) {
+	channelDIMMs := make(map[string][][]string) // socket,channel -> DIMMs
+	var channels []string
+	for _, dimm := range tableDIMMPopulation.AllHostValues[sourceIdx].Values {
+		if strings.Contains(dimm[SizeIdx], "No") || dimm[DerivedChannelIdx] == "" {
+			continue
+		}
+		channel := dimm[DerivedSocketIdx] + "," + dimm[DerivedChannelIdx]
+		if _, ok := channelDIMMs[channel]; !ok {
+			channels = append(channels, channel)
+		}
+		channelDIMMs[channel] = append(channelDIMMs[channel], dimm)
+	}
+	for _, channel := range channels {
+		dimms := channelDIMMs[channel]
+		isMixed := false
+		for _, dimm := range dimms[1:] {
+			if dimm[TypeIdx] != dimms[0][TypeIdx] || dimm[SizeIdx] != dimms[0][SizeIdx] || dimm[ConfiguredSpeedIdx] != dimms[0][ConfiguredSpeedIdx] {
+				isMixed = true
+				break
+			}
+		}
+		if !isMixed {
+			continue
+		}
+		var slots []string
+		for _, dimm := range dimms {
+			slots = append(slots, fmt.Sprintf("%s (%s, %s, %s)", dimm[LocatorIdx], dimm[TypeIdx], dimm[SizeIdx], dimm[ConfiguredSpeedIdx]))
+		}
+		socket, channelNumber, _ := strings.Cut(channel, ",")
+		mixed = append(mixed, fmt.Sprintf("socket %s channel %s: %s", socket, channelNumber, strings.Join(slots, ", ")))
+	}
+	return
+}
+
+// getDIMMSizeMB converts a dmidecode DIMM size, e.g., "32 GB", to MB
+func getDIMMSizeMB(size string) (sizeMB int, err error) {
+	re := regexp.MustCompile(`^(\d+)\s*([KMGT]B)$`)
+	match := re.FindStringSubmatch(strings.TrimSpace(size))
+	if match == nil {
+		err = fmt.Errorf("don't recognize DIMM size format: %s", size)
+		return
+	}
+	sizeMB, _ = strconv.Atoi(match[1])
+	switch match[2] {
+	case "KB":
+		sizeMB /= 1024
+	case "GB":
+		sizeMB *= 1024
+	case "TB":
+		sizeMB *= 1024 * 1024
+	}
+	return
+}
+
+// getDIMMPopulationBalance summarizes how evenly DIMMs are distributed across the memory
+// channels. Balanced is "No" when populated channels have different total capacities or
+// when sockets have different numbers of populated channels.
+func getDIMMPopulationBalance(tableDIMMPopulation *Table, sourceIdx int) (channelsPerSocket string, channelCapacities string, balanced string) {
+	channelSizes := make(map[string]int) // socket,channel -> total MB
+	socketChannels := make(map[string]map[string]bool)
+	for _, dimm := range tableDIMMPopulation.AllHostValues[sourceIdx].Values {
+		if strings.Contains(dimm[SizeIdx], "No") {
+			continue
+		}
+		sizeMB, err := getDIMMSizeMB(dimm[SizeIdx])
+		if err != nil {
+			log.Printf("%v", err)
+			return
+		}
+		channelSizes[dimm[DerivedSocketIdx]+","+dimm[DerivedChannelIdx]] += sizeMB
+		if _, ok := socketChannels[dimm[DerivedSocketIdx]]; !ok {
+			socketChannels[dimm[DerivedSocketIdx]] = make(map[string]bool)
+		}
+		socketChannels[dimm[DerivedSocketIdx]][dimm[DerivedChannelIdx]] = true
+	}
+	if len(channelSizes) == 0 {
+		return
+	}
+	var sockets []string
+	for socket := range socketChannels {
+		sockets = append(sockets, socket)
+	}
+	sort.Slice(sockets, func(i, j int) bool {
+		a, _ := strconv.Atoi(sockets[i])
+		b, _ := strconv.Atoi(sockets[j])
+		return a < b
+	})
+	var counts []string
+	for _, socket := range sockets {
+		counts = append(counts, fmt.Sprintf("%d", len(socketChannels[socket])))
+	}
+	uniqueSizes := make(map[int]bool)
+	for _, sizeMB := range channelSizes {
+		uniqueSizes[sizeMB] = true
+	}
+	var sizes []int
+	for sizeMB := range uniqueSizes {
+		sizes = append(sizes, sizeMB)
+	}
+	sort.Ints(sizes)
+	var capacities []string
+	for _, sizeMB := range sizes {
+		if sizeMB%1024 == 0 {
+			capacities = append(capacities, fmt.Sprintf("%dGB", sizeMB/1024))
+		} else {
+			capacities = append(capacities, fmt.Sprintf("%dMB", sizeMB))
+		}
+	}
+	channelsPerSocket = strings.Join(counts, "/")
+	channelCapacities = strings.Join(capacities, "/")
+	balanced = "Yes"
+	if len(sizes) > 1 {
+		balanced = "No"
+	}
+	for _, count := range counts {
+		if count != counts[0] {
+			balanced = "No"
+		}
+	}
+	return
+}
+
 /*
 Get DIMM socket and slot from Bank Locator or Locator field from dmidecode.
 This method is inherently unreliable/incomplete as each OEM can set
@@ -470,11 +631,31 @@ func deriveDIMMInfoHPE(dimms *[][]string, numSockets int, channelsPerSocket int)
 	return
 }
 
-func getHyperthreading(CPUdb cpudb.CPUDB, family, model, stepping, sockets, cpus, coresPerSocket string) (hyperthreading string) {
+// getCPU returns the host's CPU from the CPU database. x86 CPUs are identified by family, model,
+// stepping, etc., from lscpu and lspci, ARM CPUs by implementer and part from /proc/cpuinfo.
+func getCPU(CPUdb cpudb.CPUDB, source *Source) (cpu cpudb.CPU, err error) {
+	family := source.valFromRegexSubmatch("lscpu", `^CPU family.*:\s*([0-9]+)$`)
+	model := source.valFromRegexSubmatch("lscpu", `^Model.*:\s*([0-9]+)$`)
+	stepping := source.valFromRegexSubmatch("lscpu", `^Stepping.*:\s*(.+)$`)
+	sockets := source.valFromRegexSubmatch("lscpu", `^Socket\(.*:\s*(.+?)$`)
+	capid4 := source.valFromRegexSubmatch("lspci bits", `^([0-9a-fA-F]+)`)
+	devices := source.valFromRegexSubmatch("lspci devices", `^([0-9]+)`)
+	cpu, err = CPUdb.GetCPU(family, model, stepping, capid4, sockets, devices)
+	if err != nil {
+		implementer := source.valFromRegexSubmatch("/proc/cpuinfo", `^CPU implementer\s*:\s*(.+?)$`)
+		part := source.valFromRegexSubmatch("/proc/cpuinfo", `^CPU part\s*:\s*(.+?)$`)
+		if implementer != "" && part != "" {
+			cpu, err = CPUdb.GetARMCPU(implementer, part)
+		}
+	}
+	return
+}
+
+func getHyperthreading(CPUdb cpudb.CPUDB, source *Source, sockets, cpus, coresPerSocket string) (hyperthreading string) {
 	numCPUs, err1 := strconv.Atoi(cpus) // logical CPUs
 	numSockets, err2 := strconv.Atoi(sockets)
 	numCores, err3 := strconv.Atoi(coresPerSocket) // physical cores
-	cpu, err4 := CPUdb.GetCPU(family, model, stepping, "", "", "")
+	cpu, err4 := getCPU(CPUdb, source)
 	if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
 		return // leave it blank, we don't have enough information
 	}
@@ -716,3 +897,99 @@ func getInsightsRules() (rules []byte, err error) {
 	}
 	return
 }
+
+// hardwareComponent is a component compared by the Hardware Changes table
+type hardwareComponent struct {
+	kind        string // System, DIMM, Disk, or NIC
+	id          string // serial number or MAC address or, when not available, the slot or device name
+	description string
+}
+
+// isSerialNumber returns false for the placeholders reported when a component has no serial number
+func isSerialNumber(serial string) bool {
+	switch strings.ToLower(strings.TrimSpace(serial)) {
+	case "", "not specified", "unknown", "none", "no dimm", "to be filled by o.e.m.", strings.ToLower(anonymizedValue):
+		return false
+	}
+	return strings.Trim(serial, "0") != ""
+}
+
+// getHardwareComponents returns the host's system, DIMMs, disks, and NICs from the System, DIMM,
+// Disk, and NIC tables. Components are identified by serial number (MAC address for NICs) where
+// available, otherwise by slot or device name.
+func getHardwareComponents(tableSystem *Table, tableDIMM *Table, tableDisk *Table, tableNIC *Table, sourceIdx int) (components []hardwareComponent) {
+	joinNonEmpty := func(values ...string) string {
+		var nonEmpty []string
+		for _, value := range values {
+			if value != "" {
+				nonEmpty = append(nonEmpty, value)
+			}
+		}
+		return strings.Join(nonEmpty, ", ")
+	}
+	for _, system := range tableSystem.AllHostValues[sourceIdx].Values {
+		// the system is always the same component, a different serial number is a change
+		components = append(components, hardwareComponent{"System", "System", joinNonEmpty(system...)})
+	}
+	for _, dimm := range tableDIMM.AllHostValues[sourceIdx].Values {
+		if strings.Contains(dimm[SizeIdx], "No") {
+			continue
+		}
+		id := dimm[LocatorIdx]
+		if isSerialNumber(dimm[SerialIdx]) {
+			id = dimm[SerialIdx]
+		}
+		components = append(components, hardwareComponent{"DIMM", id, joinNonEmpty(dimm[LocatorIdx], dimm[SizeIdx], dimm[TypeIdx], dimm[SpeedIdx], dimm[ManufacturerIdx], dimm[PartIdx])})
+	}
+	diskModelIdx, diskSizeIdx, diskSerialIdx := 1, 2, 14
+	for _, disk := range tableDisk.AllHostValues[sourceIdx].Values {
+		// partitions have no model
+		if disk[diskModelIdx] == "" {
+			continue
+		}
+		id := disk[0]
+		if isSerialNumber(disk[diskSerialIdx]) {
+			id = disk[diskSerialIdx]
+		}
+		components = append(components, hardwareComponent{"Disk", id, joinNonEmpty(disk[0], disk[diskModelIdx], disk[diskSizeIdx])})
+	}
+	nicModelIdx, nicBusIdx, nicMACIdx := 1, 4, 8
+	for _, nic := range tableNIC.AllHostValues[sourceIdx].Values {
+		id := nic[0]
+		if nic[nicMACIdx] != "" {
+			id = nic[nicMACIdx]
+		}
+		components = append(components, hardwareComponent{"NIC", id, joinNonEmpty(nic[0], nic[nicModelIdx], nic[nicBusIdx])})
+	}
+	return
+}
+
+// getHardwareChanges compares the current components to the baseline components and returns a
+// row (component, change, identifier, baseline, current) for each component that was added,
+// removed, or changed
+func getHardwareChanges(baseline []hardwareComponent, current []hardwareComponent) (changes [][]string) {
+	matched := make([]bool, len(baseline))
+	for _, component := range current {
+		baselineIdx := -1
+		for i, baselineComponent := range baseline {
+			if !matched[i] && baselineComponent.kind == component.kind && baselineComponent.id == component.id {
+				baselineIdx = i
+				break
+			}
+		}
+		if baselineIdx == -1 {
+			changes = append(changes, []string{component.kind, "Added", component.id, "", component.description})
+			continue
+		}
+		matched[baselineIdx] = true
+		if baseline[baselineIdx].description != component.description {
+			changes = append(changes, []string{component.kind, "Changed", component.id, baseline[baselineIdx].description, component.description})
+		}
+	}
+	for i, component := range baseline {
+		if !matched[i] {
+			changes = append(changes, []string{component.kind, "Removed", component.id, component.description, ""})
+		}
+	}
+	return
+}
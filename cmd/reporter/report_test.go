@@ -0,0 +1,118 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommandRequiredPrivileges(t *testing.T) {
+	source := newTestSource(map[string]string{"dmidecode": "", "lshw": ""})
+	source.ParsedData["dmidecode"] = CommandData{Label: "dmidecode", ExitStatus: "0", Skipped: "privileges"}
+	if !source.commandRequiredPrivileges("dmidecode") {
+		t.Error("expected dmidecode to require privileges")
+	}
+	if source.commandRequiredPrivileges("lshw") {
+		t.Error("expected lshw to not require privileges")
+	}
+	// e.g., raw data from an older collector
+	if source.commandRequiredPrivileges("ipmitool sdr list full") {
+		t.Error("expected a command that isn't in the raw data to not require privileges")
+	}
+}
+
+func TestMarkPrivilegedValues(t *testing.T) {
+	skipped := newTestSource(map[string]string{"lshw": ""})
+	skipped.ParsedData["dmidecode"] = CommandData{Label: "dmidecode", ExitStatus: "0", Skipped: "privileges"}
+	skipped.ParsedData["ipmitool sdr list full"] = CommandData{Label: "ipmitool sdr list full", ExitStatus: "0", Skipped: "privileges"}
+	collected := newTestSource(map[string]string{"dmidecode": "", "lshw": "", "ipmitool sdr list full": ""})
+	newTable := func(name string, valueNames []string, values ...[][]string) *Table {
+		table := &Table{Name: name}
+		for _, hostValues := range values {
+			table.AllHostValues = append(table.AllHostValues, HostValues{Name: "host", ValueNames: valueNames, Values: hostValues})
+		}
+		return table
+	}
+	report := &Report{
+		InternalName: "Configuration",
+		Sources:      []*Source{skipped, collected},
+		Tables: []*Table{
+			// the skipped host's empty values are marked, the other host's are kept
+			newTable("System", []string{"Manufacturer", "Product Name"}, [][]string{{"", ""}}, [][]string{{"", "R760"}}),
+			// no records for the skipped host, one marker record
+			newTable("Sensor", []string{"Sensor", "Reading", "Status"}, [][]string{}, [][]string{}),
+			// the table's command wasn't skipped
+			newTable("NIC", []string{"Name", "Model"}, [][]string{{"eth0", ""}}, [][]string{{"eth0", ""}}),
+			// tables that combine regular and superuser commands' data aren't marked
+			newTable("CPU", []string{"Microcode"}, [][]string{{""}}, [][]string{{""}}),
+		},
+	}
+	markPrivilegedValues(report)
+	for _, tc := range []struct {
+		table     string
+		sourceIdx int
+		expected  [][]string
+	}{
+		{"System", 0, [][]string{{privilegedValue, privilegedValue}}},
+		{"System", 1, [][]string{{"", "R760"}}},
+		{"Sensor", 0, [][]string{{privilegedValue, "", ""}}},
+		{"Sensor", 1, [][]string{}},
+		{"NIC", 0, [][]string{{"eth0", ""}}},
+		{"NIC", 1, [][]string{{"eth0", ""}}},
+		{"CPU", 0, [][]string{{""}}},
+	} {
+		values := report.findTable(tc.table).AllHostValues[tc.sourceIdx].Values
+		if len(values) != len(tc.expected) {
+			t.Errorf("%s, host %d: expected %v, got %v", tc.table, tc.sourceIdx, tc.expected, values)
+			continue
+		}
+		for i := range tc.expected {
+			if strings.Join(values[i], "|") != strings.Join(tc.expected[i], "|") {
+				t.Errorf("%s, host %d: expected %v, got %v", tc.table, tc.sourceIdx, tc.expected[i], values[i])
+			}
+		}
+	}
+}
+
+func TestNewProcessTable(t *testing.T) {
+	source := newTestSource(map[string]string{"ps -eo": `    PID    PPID %CPU %MEM   RSS COMMAND
+      1       0  0.0  0.1 12000 /sbin/init splash
+    200       1  2.5  8.0 90000 /usr/bin/java -jar app.jar
+    300       1 50.0  1.0 20000 stress-ng --cpu 4
+    400       1  0.3 20.5 99000 /usr/sbin/mysqld
+`})
+	for _, tc := range []struct {
+		top      int
+		sortKey  string
+		expected []string
+	}{
+		{0, "cpu", []string{"1", "200", "300", "400"}},
+		{2, "cpu", []string{"300", "200"}},
+		{2, "mem", []string{"400", "200"}},
+		{10, "mem", []string{"400", "200", "300", "1"}},
+	} {
+		values := newProcessTable([]*Source{source}, tc.top, tc.sortKey, Status).AllHostValues[0].Values
+		var pids []string
+		for _, record := range values {
+			pids = append(pids, record[0])
+		}
+		if strings.Join(pids, ",") != strings.Join(tc.expected, ",") {
+			t.Errorf("top %d by %s: expected %v, got %v", tc.top, tc.sortKey, tc.expected, pids)
+		}
+	}
+	if values := newProcessTable([]*Source{source}, 2, "cpu", Status).AllHostValues[0].Values; values[0][5] != "stress-ng --cpu 4" {
+		t.Errorf("expected the command's fields to be combined, got %v", values[0])
+	}
+	// not truncated when the processes can't be sorted
+	noCPU := newTestSource(map[string]string{"ps -eo": `    PID    PPID   RSS COMMAND
+      1       0 12000 /sbin/init
+    200       1 90000 java
+    300       1 20000 stress-ng
+`})
+	if values := newProcessTable([]*Source{noCPU}, 2, "cpu", Status).AllHostValues[0].Values; len(values) != 3 {
+		t.Errorf("expected all 3 processes, got %v", values)
+	}
+}
@@ -7,19 +7,56 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+
+	"gopkg.in/yaml.v2"
 )
 
 // RulesEngineContext struct is used as context for rules engine, i.e. the rules
 // can call the exported functions below and access any exported data in the
 // struct (currently none)
 type RulesEngineContext struct {
-	insightTable *Table
-	reportsData  []*Report
-	sourceIdx    int
+	insightTable    *Table
+	reportsData     []*Report
+	sourceIdx       int
+	readinessPolicy BenchmarkReadinessPolicy
+	workloadClass   string   // see -workload-class
+	tunedProfiles   []string // the tuned profiles expected for the workload class
+}
+
+// BenchmarkReadinessPolicy selects the checks that make up the benchmark readiness insight
+type BenchmarkReadinessPolicy struct {
+	Turbo    bool   `yaml:"turbo"`    // Intel Turbo Boost is enabled
+	Governor string `yaml:"governor"` // CPU frequency governor, not checked when empty
+	THP      string `yaml:"thp"`      // Transparent Huge Pages setting, not checked when empty
+	Chassis  bool   `yaml:"chassis"`  // no power overload, main power fault, or cooling/fan fault
+	Channels bool   `yaml:"channels"` // all memory channels are populated
+}
+
+// defaultBenchmarkReadinessPolicy doesn't check THP, the best setting depends on the workload
+var defaultBenchmarkReadinessPolicy = BenchmarkReadinessPolicy{
+	Turbo:    true,
+	Governor: "performance",
+	Chassis:  true,
+	Channels: true,
+}
+
+// loadBenchmarkReadinessPolicy reads the benchmark readiness policy from a YAML file, checks
+// that aren't in the file keep their default
+func loadBenchmarkReadinessPolicy(path string) (policy BenchmarkReadinessPolicy, err error) {
+	policy = defaultBenchmarkReadinessPolicy
+	yamlBytes, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	err = yaml.UnmarshalStrict(yamlBytes, &policy)
+	return
 }
 
 // GetValue returns a string value from a table
@@ -137,6 +174,62 @@ func (r *RulesEngineContext) GetValuesFromRow(reportName string, tableName strin
 	return
 }
 
+// findTable returns the named table from the named report, or nil, logged, when either isn't found
+func (r *RulesEngineContext) findTable(reportName string, tableName string) (table *Table) {
+	var reportData *Report
+	for _, rd := range r.reportsData {
+		if rd.InternalName == reportName {
+			reportData = rd
+			break
+		}
+	}
+	if reportData == nil {
+		log.Printf("report specified in rule not found: %s", reportName)
+		return
+	}
+	table = reportData.findTable(tableName)
+	if table == nil {
+		log.Printf("table specified in rule not found: %s", tableName)
+	}
+	return
+}
+
+// GetHostCount returns the number of hosts in the reports
+func (r *RulesEngineContext) GetHostCount() int {
+	if len(r.reportsData) == 0 {
+		return 0
+	}
+	return len(r.reportsData[0].Sources)
+}
+
+// GetMajorityValue returns the value that is most common across all hosts. Hosts without the value
+// are ignored. Returns an empty string when no single value is the most common, i.e., when there
+// is a tie.
+func (r *RulesEngineContext) GetMajorityValue(reportName string, tableName string, valueName string) (value string) {
+	table := r.findTable(reportName, tableName)
+	if table == nil {
+		return
+	}
+	counts := make(map[string]int)
+	for sourceIdx := range table.AllHostValues {
+		v, err := table.getValue(sourceIdx, valueName)
+		if err != nil || v == "" {
+			continue
+		}
+		counts[v]++
+	}
+	maxCount := 0
+	for v, count := range counts {
+		if count > maxCount {
+			maxCount = count
+			value = v
+		} else if count == maxCount {
+			value = "" // tie, but keep maxCount so a later, more common value can win
+		}
+	}
+	return
+}
+
 // GetValueAsInt returns an integer value from a table
 func (r *RulesEngineContext) GetValueAsInt(reportName string, tableName string, valueName string) (value int) {
 	v := r.GetValue(reportName, tableName, valueName)
@@ -173,6 +266,81 @@ func (r *RulesEngineContext) GetValueAsFloat(reportName string, tableName string
 	return
 }
 
+// unitScales maps each supported unit to its family and its scale relative to the family's base
+// unit, e.g., 1 GHz is 1e9 Hz. Units are case sensitive, e.g., Mb/s is megabits and MB/s is
+// megabytes per second. Sizes are binary, i.e., 1 KB is 1024 B, as in the memory tables. Data
+// rates are decimal, i.e., 1 Gb/s is 1000 Mb/s as in ethtool's link speeds, and 1 GB/s is 1000
+// MB/s as in MLC's bandwidths, in bits per second so that bit and byte rates can be compared.
+var unitScales = map[string]struct {
+	family string
+	scale  float64
+}{
+	"Hz": {"frequency", 1}, "kHz": {"frequency", 1e3}, "KHz": {"frequency", 1e3}, "MHz": {"frequency", 1e6}, "GHz": {"frequency", 1e9},
+	"B": {"size", 1}, "kB": {"size", 1 << 10}, "KB": {"size", 1 << 10}, "MB": {"size", 1 << 20}, "GB": {"size", 1 << 30}, "TB": {"size", 1 << 40}, "PB": {"size", 1 << 50},
+	"KiB": {"size", 1 << 10}, "MiB": {"size", 1 << 20}, "GiB": {"size", 1 << 30}, "TiB": {"size", 1 << 40}, "PiB": {"size", 1 << 50},
+	"K": {"size", 1 << 10}, "M": {"size", 1 << 20}, "G": {"size", 1 << 30}, "T": {"size", 1 << 40}, // e.g., lsblk sizes
+	"b/s": {"data rate", 1}, "kb/s": {"data rate", 1e3}, "Kb/s": {"data rate", 1e3}, "Mb/s": {"data rate", 1e6}, "Gb/s": {"data rate", 1e9}, "Tb/s": {"data rate", 1e12},
+	"bps": {"data rate", 1}, "Kbps": {"data rate", 1e3}, "Mbps": {"data rate", 1e6}, "Gbps": {"data rate", 1e9}, "Tbps": {"data rate", 1e12},
+	"B/s": {"data rate", 8}, "kB/s": {"data rate", 8e3}, "KB/s": {"data rate", 8e3}, "MB/s": {"data rate", 8e6}, "GB/s": {"data rate", 8e9}, "TB/s": {"data rate", 8e12},
+	"MT/s": {"transfer rate", 1e6}, "GT/s": {"transfer rate", 1e9},
+	"mW": {"power", 1e-3}, "W": {"power", 1}, "kW": {"power", 1e3},
+	"ns": {"time", 1e-9}, "us": {"time", 1e-6}, "µs": {"time", 1e-6}, "ms": {"time", 1e-3}, "s": {"time", 1},
+	"%": {"percent", 1},
+}
+
+// reNumeric matches the first number in a value and the unit that follows it, e.g., "2.1GHz"
+var reNumeric = regexp.MustCompile(`([-+]?\d[\d,]*\.?\d*)\s*([A-Za-zµ%]+(?:/s)?)?`)
+
+// parseNumeric returns the first number in the string, e.g., 2.1 in "2.1GHz (16 cores)", converted
+// from the unit that follows it to the target unit. The number is returned as is when either unit
+// is empty.
+func parseNumeric(v string, unit string) (value float64, err error) {
+	match := reNumeric.FindStringSubmatch(v)
+	if match == nil {
+		err = fmt.Errorf("no number found in %s", v)
+		return
+	}
+	if value, err = strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64); err != nil {
+		return
+	}
+	if match[2] == "" || unit == "" {
+		return
+	}
+	from, ok := unitScales[match[2]]
+	if !ok {
+		err = fmt.Errorf("unsupported unit %s in %s", match[2], v)
+		return
+	}
+	to, ok := unitScales[unit]
+	if !ok {
+		err = fmt.Errorf("unsupported unit: %s", unit)
+		return
+	}
+	if from.family != to.family {
+		err = fmt.Errorf("can't convert %s to %s", match[2], unit)
+		return
+	}
+	value = value * from.scale / to.scale
+	return
+}
+
+// GetNumeric returns a number from a table converted to the given unit, e.g., GHz, GB, W, or ms,
+// so that rules can compare values with thresholds, e.g., Report.GetNumeric("Configuration",
+// "CPU", "Base Frequency", "GHz") < 2.0. Returns 0 when the value doesn't contain a number or its
+// unit can't be converted to the given unit.
+func (r *RulesEngineContext) GetNumeric(reportName string, tableName string, valueName string, unit string) (value float64) {
+	v := r.GetValue(reportName, tableName, valueName)
+	if v == "" {
+		return
+	}
+	value, err := parseNumeric(v, unit)
+	if err != nil {
+		log.Printf("failed to get numeric value of %s:%s, %v", tableName, valueName, err)
+		value = 0
+	}
+	return
+}
+
 // GetValueFromColumnAsFloat returns a float64 value from a table
 // if column value doesn't contain a float, result will be 0
 func (r *RulesEngineContext) GetValueFromColumnAsFloat(reportName, tableName, rowValueName, rowValue, targetValueName string) (value float64) {
@@ -190,6 +358,28 @@ func (r *RulesEngineContext) GetValueFromColumnAsFloat(reportName, tableName, ro
 	return
 }
 
+// GetMaxValueFromColumnAsFloat returns the largest value in the specified column, e.g., the
+// highest value across NUMA nodes. Values that aren't numbers are ignored. Result is 0 when
+// no value is a number.
+func (r *RulesEngineContext) GetMaxValueFromColumnAsFloat(reportName string, tableName string, valueName string) (value float64) {
+	table := r.findTable(reportName, tableName)
+	if table == nil {
+		return
+	}
+	hv := &table.AllHostValues[r.sourceIdx]
+	valueIndex := slices.Index(hv.ValueNames, valueName)
+	if valueIndex < 0 {
+		log.Printf("value specified in rule not found: %s", valueName)
+		return
+	}
+	for _, row := range hv.Values {
+		if v, err := strconv.ParseFloat(row[valueIndex], 64); err == nil && v > value {
+			value = v
+		}
+	}
+	return
+}
+
 // GetValueFromColumnAsInt returns an int value from a table
 // if column value doesn't contain an int, result will be 0
 func (r *RulesEngineContext) GetValueFromColumnAsInt(reportName, tableName, rowValueName, rowValue, targetValueName string) (value int64) {
@@ -284,6 +474,144 @@ func (r *RulesEngineContext) CompareMicroarchitecture(x, y string) int {
 	return 0 // equal
 }
 
+// getBenchmarkReadiness returns a description of each failing benchmark readiness check and the
+// name of each check that wasn't performed because its data wasn't collected, e.g., Chassis Status
+// on virtual machines, and the number of checks selected by the policy
+func (r *RulesEngineContext) getBenchmarkReadiness() (failed []string, notChecked []string, selected int) {
+	policy := r.readinessPolicy
+	if policy.Turbo {
+		selected++
+		turbo := r.GetValue("Configuration", "CPU", "Intel Turbo Boost")
+		if turbo == "" {
+			notChecked = append(notChecked, "Intel Turbo Boost")
+		} else if turbo != "Enabled" {
+			failed = append(failed, "Intel Turbo Boost is "+turbo)
+		}
+	}
+	if policy.Governor != "" {
+		selected++
+		governor := r.GetValue("Configuration", "Power", "Frequency Governor")
+		if governor == "" {
+			notChecked = append(notChecked, "Frequency Governor")
+		} else if governor != policy.Governor {
+			failed = append(failed, fmt.Sprintf("Frequency Governor is %s, expected %s", governor, policy.Governor))
+		}
+	}
+	if policy.THP != "" {
+		selected++
+		thp := r.GetValue("Configuration", "Memory", "Transparent Huge Pages")
+		if thp == "" {
+			notChecked = append(notChecked, "Transparent Huge Pages")
+		} else if thp != policy.THP {
+			failed = append(failed, fmt.Sprintf("Transparent Huge Pages is %s, expected %s", thp, policy.THP))
+		}
+	}
+	if policy.Chassis {
+		selected++
+		var faults []string
+		for _, valueName := range []string{"Power Overload", "Main Power Fault", "Cooling/Fan Fault"} {
+			value := r.GetValue("Configuration", "Chassis Status", valueName)
+			if value == "" {
+				faults = nil
+				notChecked = append(notChecked, "Chassis Status")
+				break
+			}
+			if value == "true" {
+				faults = append(faults, valueName)
+			}
+		}
+		if len(faults) > 0 {
+			failed = append(failed, "Chassis Status reports "+strings.Join(faults, ", "))
+		}
+	}
+	if policy.Channels {
+		selected++
+		channels := r.GetValue("Configuration", "CPU", "Memory Channels")
+		sockets := r.GetValue("Configuration", "CPU", "Sockets")
+		populated := r.GetValue("Configuration", "Memory", "Populated Memory Channels")
+		if channels == "" || sockets == "" || populated == "" {
+			notChecked = append(notChecked, "Populated Memory Channels")
+		} else if total := r.GetValueAsInt("Configuration", "CPU", "Memory Channels") * r.GetValueAsInt("Configuration", "CPU", "Sockets"); total != r.GetValueAsInt("Configuration", "Memory", "Populated Memory Channels") {
+			failed = append(failed, fmt.Sprintf("%s of %d memory channels populated", populated, total))
+		}
+	}
+	return
+}
+
+// GetBenchmarkReadiness returns the benchmark readiness verdict, PASS, FAIL, or NOT CHECKED when
+// none of the selected checks' data was collected, followed by the failing checks and the checks
+// that weren't performed, or an empty string when the policy selects no checks. Data that wasn't
+// collected doesn't fail its check.
+func (r *RulesEngineContext) GetBenchmarkReadiness() (justification string) {
+	failed, notChecked, selected := r.getBenchmarkReadiness()
+	if selected == 0 {
+		return
+	}
+	if len(failed) > 0 {
+		justification = "Benchmark Readiness: FAIL. Failing checks: " + strings.Join(failed, "; ") + "."
+	} else if len(notChecked) == selected {
+		justification = "Benchmark Readiness: NOT CHECKED."
+	} else {
+		justification = "Benchmark Readiness: PASS."
+	}
+	if len(notChecked) > 0 {
+		justification += " Not checked, data not collected: " + strings.Join(notChecked, ", ") + "."
+	}
+	return
+}
+
+// GetBenchmarkReadinessRecommendation returns what to do about the benchmark readiness verdict
+func (r *RulesEngineContext) GetBenchmarkReadinessRecommendation() (recommendation string) {
+	failed, notChecked, selected := r.getBenchmarkReadiness()
+	if len(failed) > 0 {
+		recommendation = "Resolve the failing checks before running benchmarks."
+	} else if len(notChecked) == selected {
+		recommendation = "Confirm the benchmark readiness settings manually before running benchmarks."
+	} else {
+		recommendation = "No changes are needed before running benchmarks."
+	}
+	if len(notChecked) > 0 && len(notChecked) < selected {
+		recommendation += " Confirm the settings that weren't checked manually."
+	}
+	return
+}
+
+// GetMixedDIMMChannels returns the memory channels with DIMMs of differing type, size, or
+// configured speed, and the slots involved, separated by semicolons, or an empty string when there
+// are none
+func (r *RulesEngineContext) GetMixedDIMMChannels() (channels string) {
+	for _, rd := range r.reportsData {
+		if rd.InternalName != "Configuration" {
+			continue
+		}
+		if table := rd.findTable("DIMM Population"); table != nil {
+			channels = strings.Join(getMixedDIMMChannels(table, r.sourceIdx), "; ")
+		}
+		break
+	}
+	return
+}
+
+// GetUnexpectedTunedProfile returns the active tuned profile when it isn't one of the profiles
+// expected for the workload class, otherwise empty string
+func (r *RulesEngineContext) GetUnexpectedTunedProfile() (profile string) {
+	active := r.GetValue("Configuration", "Tuned", "Active Profile")
+	if active == "" || len(r.tunedProfiles) == 0 || slices.Contains(r.tunedProfiles, active) {
+		return
+	}
+	return active
+}
+
+// GetExpectedTunedProfiles returns the tuned profiles expected for the workload class
+func (r *RulesEngineContext) GetExpectedTunedProfiles() string {
+	return strings.Join(r.tunedProfiles, ", ")
+}
+
+// GetWorkloadClass returns the workload class, see -workload-class
+func (r *RulesEngineContext) GetWorkloadClass() string {
+	return r.workloadClass
+}
+
 // AddInsight -- appends an insight to the table
 func (r *RulesEngineContext) AddInsight(justification string, recommendation string) {
 	r.insightTable.AllHostValues[r.sourceIdx].Values = append(
@@ -0,0 +1,286 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseNumeric(t *testing.T) {
+	for _, tc := range []struct {
+		in       string
+		unit     string
+		expected float64
+		err      bool
+	}{
+		{"2.1GHz", "GHz", 2.1, false},
+		{"3500 MHz", "GHz", 3.5, false},
+		{"1024GB (16x64GB DDR5 4800 MT/s)", "TB", 1, false},
+		{"527,802,508 kB", "GB", 503.35, false},
+		{"4800 MT/s", "GT/s", 4.8, false},
+		{"250 us", "ms", 0.25, false},
+		{"350W", "", 350, false},
+		{"25000Mb/s", "Gb/s", 25, false},
+		{"100 Gb/s", "Mb/s", 100000, false},
+		{"25000Mb/s", "MB/s", 3125, false},
+		{"250.5 GB/s", "MB/s", 250500, false},
+		{"1.5 µs", "ns", 1500, false},
+		{"4800 mt/s", "GT/s", 0, true},
+		{"2.1GHz", "ghz", 0, true},
+		{"56", "GB", 56, false},
+		{"2.1GHz", "GB", 0, true},
+		{"N/A", "GHz", 0, true},
+	} {
+		value, err := parseNumeric(tc.in, tc.unit)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s %s: expected an error, got %f", tc.in, tc.unit, value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %s: %v", tc.in, tc.unit, err)
+			continue
+		}
+		if value < tc.expected*0.999 || value > tc.expected*1.001 {
+			t.Errorf("%s %s: expected %f, got %f", tc.in, tc.unit, tc.expected, value)
+		}
+	}
+}
+
+func TestGetBenchmarkReadinessFailures(t *testing.T) {
+	newTable := func(name string, valueNames []string, values []string) *Table {
+		return &Table{Name: name, AllHostValues: []HostValues{{Name: "host", ValueNames: valueNames, Values: [][]string{values}}}}
+	}
+	configReport := &Report{
+		InternalName: "Configuration",
+		Tables: []*Table{
+			newTable("CPU", []string{"Intel Turbo Boost", "Memory Channels", "Sockets"}, []string{"Enabled", "8", "2"}),
+			newTable("Power", []string{"Frequency Governor"}, []string{"powersave"}),
+			newTable("Memory", []string{"Transparent Huge Pages", "Populated Memory Channels"}, []string{"always", "12"}),
+			newTable("Chassis Status", []string{"Power Overload", "Main Power Fault", "Cooling/Fan Fault"}, []string{"false", "false", "true"}),
+		},
+	}
+	r := &RulesEngineContext{reportsData: []*Report{configReport}, readinessPolicy: defaultBenchmarkReadinessPolicy}
+	expected := "Benchmark Readiness: FAIL. Failing checks: Frequency Governor is powersave, expected performance; Chassis Status reports Cooling/Fan Fault; 12 of 16 memory channels populated."
+	if justification := r.GetBenchmarkReadiness(); justification != expected {
+		t.Errorf("expected %s, got %s", expected, justification)
+	}
+	if recommendation := r.GetBenchmarkReadinessRecommendation(); recommendation != "Resolve the failing checks before running benchmarks." {
+		t.Errorf("unexpected recommendation: %s", recommendation)
+	}
+	r.readinessPolicy = BenchmarkReadinessPolicy{Turbo: true, THP: "always"}
+	if justification := r.GetBenchmarkReadiness(); justification != "Benchmark Readiness: PASS." {
+		t.Errorf("expected PASS, got %s", justification)
+	}
+	// no checks selected
+	r.readinessPolicy = BenchmarkReadinessPolicy{}
+	if justification := r.GetBenchmarkReadiness(); justification != "" {
+		t.Errorf("expected no verdict, got %s", justification)
+	}
+	// e.g., a virtual machine without a BMC, the chassis status isn't checked rather than failed
+	configReport.Tables = configReport.Tables[:3]
+	configReport.Tables[1] = newTable("Power", []string{"Frequency Governor"}, []string{"performance"})
+	configReport.Tables[2] = newTable("Memory", []string{"Transparent Huge Pages", "Populated Memory Channels"}, []string{"always", "16"})
+	r.readinessPolicy = defaultBenchmarkReadinessPolicy
+	expected = "Benchmark Readiness: PASS. Not checked, data not collected: Chassis Status."
+	if justification := r.GetBenchmarkReadiness(); justification != expected {
+		t.Errorf("expected %s, got %s", expected, justification)
+	}
+	if recommendation := r.GetBenchmarkReadinessRecommendation(); !strings.Contains(recommendation, "weren't checked") {
+		t.Errorf("unexpected recommendation: %s", recommendation)
+	}
+	// nothing collected
+	r.reportsData = []*Report{{InternalName: "Configuration"}}
+	expected = "Benchmark Readiness: NOT CHECKED. Not checked, data not collected: Intel Turbo Boost, Frequency Governor, Chassis Status, Populated Memory Channels."
+	if justification := r.GetBenchmarkReadiness(); justification != expected {
+		t.Errorf("expected %s, got %s", expected, justification)
+	}
+}
+
+func TestGetMixedDIMMChannels(t *testing.T) {
+	dimm := func(locator, size, speed, socket, channel string) []string {
+		values := make([]string, DerivedSlotIdx+1)
+		values[LocatorIdx], values[SizeIdx], values[TypeIdx], values[ConfiguredSpeedIdx] = locator, size, "DDR5", speed
+		values[DerivedSocketIdx], values[DerivedChannelIdx] = socket, channel
+		return values
+	}
+	configReport := &Report{
+		InternalName: "Configuration",
+		Tables: []*Table{{Name: "DIMM Population", AllHostValues: []HostValues{{Values: [][]string{
+			dimm("A1", "32 GB", "4800 MT/s", "0", "0"),
+			dimm("A2", "32 GB", "4800 MT/s", "0", "0"),
+			dimm("B1", "32 GB", "4800 MT/s", "0", "1"),
+			dimm("B2", "64 GB", "4400 MT/s", "0", "1"),
+			dimm("C1", "No Module Installed", "Unknown", "0", "2"),
+			dimm("C2", "32 GB", "4800 MT/s", "0", "2"),
+		}}}}},
+	}
+	r := &RulesEngineContext{reportsData: []*Report{configReport}}
+	expected := "socket 0 channel 1: B1 (DDR5, 32 GB, 4800 MT/s), B2 (DDR5, 64 GB, 4400 MT/s)"
+	if channels := r.GetMixedDIMMChannels(); channels != expected {
+		t.Errorf("expected %s, got %s", expected, channels)
+	}
+}
+
+func TestGetUnexpectedTunedProfile(t *testing.T) {
+	profiles, err := loadTunedProfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	configReport := &Report{
+		InternalName: "Configuration",
+		Tables: []*Table{
+			{Name: "Tuned", AllHostValues: []HostValues{{Name: "host", ValueNames: []string{"Active Profile"}, Values: [][]string{{"powersave"}}}}},
+		},
+	}
+	r := &RulesEngineContext{reportsData: []*Report{configReport}, workloadClass: "throughput", tunedProfiles: profiles["throughput"]}
+	if profile := r.GetUnexpectedTunedProfile(); profile != "powersave" {
+		t.Errorf("expected powersave, got %s", profile)
+	}
+	configReport.Tables[0].AllHostValues[0].Values[0][0] = "throughput-performance"
+	if profile := r.GetUnexpectedTunedProfile(); profile != "" {
+		t.Errorf("expected no unexpected profile, got %s", profile)
+	}
+}
+
+func TestGetMajorityValue(t *testing.T) {
+	host := func(microcode string) HostValues {
+		return HostValues{ValueNames: []string{"Microcode"}, Values: [][]string{{microcode}}}
+	}
+	for _, tc := range []struct {
+		microcodes []string
+		expected   string
+	}{
+		{[]string{"0x2b000590", "0x2b000590", "0x2b000461"}, "0x2b000590"},
+		{[]string{"0x2b000590", "", "", "0x2b000461", "0x2b000461"}, "0x2b000461"},
+		{[]string{"0x2b000590", "0x2b000461"}, ""},
+		{[]string{"0x2b000590", "0x2b000461", "0x2b000461", "0x2b000590", "0x2b000571", "0x2b000571", "0x2b000571"}, "0x2b000571"},
+		{[]string{"", ""}, ""},
+	} {
+		table := &Table{Name: "CPU"}
+		for _, microcode := range tc.microcodes {
+			table.AllHostValues = append(table.AllHostValues, host(microcode))
+		}
+		r := &RulesEngineContext{reportsData: []*Report{{InternalName: "Configuration", Tables: []*Table{table}}}}
+		if value := r.GetMajorityValue("Configuration", "CPU", "Microcode"); value != tc.expected {
+			t.Errorf("%v: expected %q, got %q", tc.microcodes, tc.expected, value)
+		}
+	}
+	r := &RulesEngineContext{reportsData: []*Report{{InternalName: "Configuration"}}}
+	if value := r.GetMajorityValue("Configuration", "CPU", "Microcode"); value != "" {
+		t.Errorf("expected no value for a missing table, got %q", value)
+	}
+}
+
+func TestGetMaxValueFromColumnAsFloat(t *testing.T) {
+	table := &Table{Name: "NUMA Stats", AllHostValues: []HostValues{{
+		ValueNames: []string{"Node", "Miss %"},
+		Values:     [][]string{{"0", "0.00"}, {"1", "20.20"}, {"2", ""}},
+	}}}
+	r := &RulesEngineContext{reportsData: []*Report{{InternalName: "Configuration", Tables: []*Table{table}}}}
+	if value := r.GetMaxValueFromColumnAsFloat("Configuration", "NUMA Stats", "Miss %"); value != 20.2 {
+		t.Errorf("expected 20.2, got %f", value)
+	}
+	if value := r.GetMaxValueFromColumnAsFloat("Configuration", "NUMA Stats", "Hit %"); value != 0 {
+		t.Errorf("expected 0 for a missing value, got %f", value)
+	}
+	if value := r.GetMaxValueFromColumnAsFloat("Configuration", "CPU", "Miss %"); value != 0 {
+		t.Errorf("expected 0 for a missing table, got %f", value)
+	}
+	var found bool
+	for _, insight := range newTestInsights(table) {
+		found = found || strings.Contains(insight, "numa_miss")
+	}
+	if !found {
+		t.Error("expected the NUMA miss insight")
+	}
+}
+
+// newTestInsights runs the insights rules on a configuration report with the given tables, for one host
+func newTestInsights(tables ...*Table) (insights []string) {
+	configReport := &Report{InternalName: "Configuration", Sources: []*Source{newTestSource(nil)}, Tables: tables}
+	table := newInsightTable(configReport, &Report{InternalName: "Brief"}, &Report{InternalName: "Profile"}, &Report{InternalName: "Benchmark"}, &Report{InternalName: "Analyze"}, BenchmarkReadinessPolicy{}, "throughput")
+	for _, values := range table.AllHostValues[0].Values {
+		insights = append(insights, strings.Join(values, " "))
+	}
+	return
+}
+
+func TestDIMMPopulationBalanceInsight(t *testing.T) {
+	cpu := func(channels string) *Table {
+		return &Table{Name: "CPU", AllHostValues: []HostValues{{ValueNames: []string{"Memory Channels", "Sockets"}, Values: [][]string{{channels, "2"}}}}}
+	}
+	balance := func(populated, balanced string) *Table {
+		return &Table{Name: "DIMM Population Balance", AllHostValues: []HostValues{{
+			ValueNames: []string{"Populated Channels", "Populated Channels per Socket", "Channel Capacity", "Balanced"},
+			Values:     [][]string{{populated, "", "64GB", balanced}},
+		}}}
+	}
+	for _, tc := range []struct {
+		name     string
+		tables   []*Table
+		expected bool
+	}{
+		{"balanced, all channels populated", []*Table{cpu("8"), balance("16", "Yes")}, false},
+		{"unbalanced capacity", []*Table{cpu("8"), balance("16", "No")}, true},
+		{"balanced, channels not populated", []*Table{cpu("8"), balance("12", "Yes")}, true},
+		{"balanced, memory channels unknown", []*Table{cpu(""), balance("12", "Yes")}, false},
+	} {
+		found := false
+		for _, insight := range newTestInsights(tc.tables...) {
+			if strings.Contains(insight, "not balanced across memory channels") {
+				found = true
+			}
+		}
+		if found != tc.expected {
+			t.Errorf("%s: expected insight %t, got %t", tc.name, tc.expected, found)
+		}
+	}
+}
+
+func TestVulnerabilityPolicy(t *testing.T) {
+	source := newTestSource(map[string]string{"spectre-meltdown-checker": "CVE-2017-5753: OK (Mitigation: usercopy/swapgs barriers and __user pointer sanitization)\nCVE-2018-3639: VULN (Your CPU doesn't support SSBD)\n"})
+	for _, tc := range []struct {
+		name         string
+		policy       VulnerabilityPolicy
+		compliant    string
+		nonCompliant string
+	}{
+		{"no policy", nil, "", ""},
+		{"compliant", VulnerabilityPolicy{"CVE-2017-5753": "OK"}, "Yes", ""},
+		{"non-compliant and missing", VulnerabilityPolicy{"CVE-2017-5753": "OK", "CVE-2018-3639": "OK", "CVE-2019-11135": "OK"}, "No", "CVE-2018-3639, CVE-2019-11135"},
+	} {
+		table := newVulnerabilityTable([]*Source{source}, tc.policy, Security)
+		compliant, err := table.getValue(0, "Compliant")
+		if tc.compliant == "" {
+			if err == nil {
+				t.Errorf("%s: unexpected Compliant value %q", tc.name, compliant)
+			}
+		} else if compliant != tc.compliant {
+			t.Errorf("%s: expected %q, got %q, %v", tc.name, tc.compliant, compliant, err)
+		}
+		if nonCompliant, _ := table.getValue(0, "Non-Compliant"); nonCompliant != tc.nonCompliant {
+			t.Errorf("%s: expected non-compliant %q, got %q", tc.name, tc.nonCompliant, nonCompliant)
+		}
+		var found bool
+		for _, insight := range newTestInsights(table) {
+			found = found || strings.Contains(insight, "Vulnerabilities not compliant with the vulnerability policy: "+tc.nonCompliant+".")
+		}
+		if found != (tc.compliant == "No") {
+			t.Errorf("%s: unexpected vulnerability policy insight: %t", tc.name, found)
+		}
+		// the summary lists the vulnerabilities only
+		if summary := newVulnerabilitySummaryTable(table, Security); strings.Contains(summary.AllHostValues[0].Values[0][0], "Compliant") {
+			t.Errorf("%s: unexpected compliance in summary %s", tc.name, summary.AllHostValues[0].Values[0][0])
+		}
+	}
+	// no vulnerabilities collected
+	table := newVulnerabilityTable([]*Source{newTestSource(nil)}, VulnerabilityPolicy{"CVE-2017-5753": "OK"}, Security)
+	if len(table.AllHostValues[0].Values) != 0 {
+		t.Errorf("expected no values, got %v", table.AllHostValues[0].Values)
+	}
+}
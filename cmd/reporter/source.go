@@ -7,17 +7,36 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"io"
 	"log"
 	"math"
 	"os"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// anonymizedValue replaces identifying values, e.g., serial numbers, when anonymizing
+const anonymizedValue = "ANONYMIZED"
+
+// ndjsonVersion is the highest version of the collector's NDJSON output format that can be parsed
+const ndjsonVersion = 1
+
+// ndjsonHeader is the first line of the collector's NDJSON output (collector -ndjson)
+type ndjsonHeader struct {
+	Version int    `json:"ndjson_version"`
+	Name    string `json:"name"`
+}
+
 type CommandData struct {
 	Command    string `json:"command"`
 	ExitStatus string `json:"exitstatus"`
@@ -25,6 +44,8 @@ type CommandData struct {
 	Stderr     string `json:"stderr"`
 	Stdout     string `json:"stdout"`
 	SuperUser  string `json:"superuser"`
+	DurationMS string `json:"duration_ms,omitempty"` // not present in output from older collectors
+	Skipped    string `json:"skipped,omitempty"`     // why the command wasn't run, e.g., privileges
 }
 
 type Source struct {
@@ -47,6 +68,21 @@ func (s *Source) parse() (err error) {
 	if err != nil {
 		return
 	}
+	// archived collections may be gzip compressed, e.g., host.raw.json.gz
+	if strings.HasSuffix(s.inputFilePath, ".gz") || bytes.HasPrefix(inputBytes, []byte{0x1f, 0x8b}) {
+		inputBytes, err = gunzip(inputBytes)
+		if err != nil {
+			err = fmt.Errorf("failed to decompress %s: %v", s.inputFilePath, err)
+			return
+		}
+	}
+	// NDJSON input starts with a header line that includes the format version
+	firstLine, _, _ := bytes.Cut(inputBytes, []byte("\n"))
+	var header ndjsonHeader
+	if json.Unmarshal(firstLine, &header) == nil && header.Version > 0 {
+		err = s.parseNDJSON(inputBytes, header)
+		return
+	}
 	var jsonData map[string][]CommandData // hostname: array of command data (this is the format of collector output file)
 	err = json.Unmarshal(inputBytes, &jsonData)
 	if err != nil {
@@ -65,10 +101,143 @@ func (s *Source) parse() (err error) {
 	return
 }
 
+// parseNDJSON parses the collector's NDJSON output, i.e., a header line followed by one line
+// of command data per command
+func (s *Source) parseNDJSON(inputBytes []byte, header ndjsonHeader) (err error) {
+	if header.Version > ndjsonVersion {
+		err = fmt.Errorf("unsupported NDJSON version in %s: %d", s.inputFilePath, header.Version)
+		return
+	}
+	decoder := json.NewDecoder(bytes.NewReader(inputBytes))
+	if err = decoder.Decode(&header); err != nil {
+		return
+	}
+	s.Hostname = header.Name
+	for decoder.More() {
+		var c CommandData
+		if err = decoder.Decode(&c); err != nil {
+			err = fmt.Errorf("failed to parse command data in %s: %v", s.inputFilePath, err)
+			return
+		}
+		s.ParsedData[c.Label] = c
+	}
+	return
+}
+
+// getRawJSON returns the source's data in the collector's output format, i.e., as found in
+// the raw.json file, reflecting any changes made to the data, e.g., anonymization
+func (s *Source) getRawJSON() (rawJSON []byte, err error) {
+	var labels []string
+	for label := range s.ParsedData {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	var commands []CommandData
+	for _, label := range labels {
+		commands = append(commands, s.ParsedData[label])
+	}
+	rawJSON, err = json.MarshalIndent(map[string][]CommandData{s.Hostname: commands}, "", "  ")
+	return
+}
+
+func gunzip(compressed []byte) (decompressed []byte, err error) {
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+	decompressed, err = io.ReadAll(reader)
+	return
+}
+
+// anonymize replaces the host's name, wherever it appears in the collected data, with the provided
+// name and removes the serial numbers and UUIDs reported by dmidecode and the serial numbers
+// reported by cxl-cli and lsblk
+func (s *Source) anonymize(hostname string) {
+	// the name the data was collected under may differ from the host's own name
+	names := []string{s.Hostname}
+	if nodename := s.valFromRegexSubmatch("uname -a", `^Linux (\S+) \S+`); nodename != "" && nodename != s.Hostname {
+		names = append(names, nodename)
+	}
+	var reNames []*regexp.Regexp
+	for _, name := range names {
+		reNames = append(reNames, regexp.MustCompile(`\b`+regexp.QuoteMeta(name)+`\b`))
+	}
+	reSerial := regexp.MustCompile(`(?m)^(\s*(?:Serial Number|UUID):\s*)\S.*$`)
+	reCXLSerial := regexp.MustCompile(`("serial"\s*:\s*)[^,}\s]+`)
+	reDiskSerial := regexp.MustCompile(`(?m)^(\S+ )\S+$`)
+	for label, c := range s.ParsedData {
+		for _, re := range reNames {
+			c.Stdout = re.ReplaceAllLiteralString(c.Stdout, hostname)
+			c.Stderr = re.ReplaceAllLiteralString(c.Stderr, hostname)
+		}
+		if label == "dmidecode" {
+			c.Stdout = reSerial.ReplaceAllString(c.Stdout, "${1}"+anonymizedValue)
+		}
+		if label == "cxl list" {
+			c.Stdout = reCXLSerial.ReplaceAllString(c.Stdout, `${1}"`+anonymizedValue+`"`)
+		}
+		if label == "disk serial" {
+			c.Stdout = reDiskSerial.ReplaceAllString(c.Stdout, "${1}"+anonymizedValue)
+		}
+		s.ParsedData[label] = c
+	}
+	s.Hostname = hostname
+}
+
+// RedfishEthernetInterface holds the fields of interest from a Redfish EthernetInterface resource
+type RedfishEthernetInterface struct {
+	ID            string `json:"Id"`
+	MACAddress    string `json:"MACAddress"`
+	HostName      string `json:"HostName"`
+	IPv4Addresses []struct {
+		Address       string `json:"Address"`
+		SubnetMask    string `json:"SubnetMask"`
+		Gateway       string `json:"Gateway"`
+		AddressOrigin string `json:"AddressOrigin"`
+	} `json:"IPv4Addresses"`
+}
+
+// RedfishManager holds the fields of interest from a Redfish Manager resource, i.e., a BMC,
+// and its network interfaces
+type RedfishManager struct {
+	Manager struct {
+		ID              string `json:"Id"`
+		Model           string `json:"Model"`
+		FirmwareVersion string `json:"FirmwareVersion"`
+	} `json:"Manager"`
+	EthernetInterfaces []RedfishEthernetInterface `json:"EthernetInterfaces"`
+}
+
+// getRedfishManagers returns the BMCs found by the collector's redfish query, if any
+func (s *Source) getRedfishManagers() (managers []RedfishManager) {
+	output := s.getCommandOutput("redfish")
+	if output == "" {
+		return
+	}
+	var data struct {
+		Managers []RedfishManager `json:"Managers"`
+	}
+	err := json.Unmarshal([]byte(output), &data)
+	if err != nil {
+		log.Printf("failed to parse redfish output: %v", err)
+		return
+	}
+	managers = data.Managers
+	return
+}
+
 func (s *Source) getHostname() (hostname string) {
 	return s.Hostname
 }
 
+// commandRequiredPrivileges returns true when the collector skipped the command because it
+// couldn't elevate its privileges
+func (s *Source) commandRequiredPrivileges(cmdLabel string) bool {
+	c, ok := s.ParsedData[cmdLabel]
+	return ok && c.Skipped == "privileges"
+}
+
 // return command output or empty string if no match
 func (s *Source) getCommandOutput(cmdLabel string) (output string) {
 	if c, ok := s.ParsedData[cmdLabel]; ok {
@@ -77,6 +246,38 @@ func (s *Source) getCommandOutput(cmdLabel string) (output string) {
 	return
 }
 
+// getCommandDurations returns the label, duration, and exit status of each command that
+// reported its duration, slowest first
+func (s *Source) getCommandDurations() (durations [][]string) {
+	type commandDuration struct {
+		label      string
+		durationMS int
+		exitStatus string
+	}
+	var commands []commandDuration
+	for label, c := range s.ParsedData {
+		if c.DurationMS == "" {
+			continue
+		}
+		ms, err := strconv.Atoi(c.DurationMS)
+		if err != nil {
+			log.Printf("invalid duration for %s: %s", label, c.DurationMS)
+			continue
+		}
+		commands = append(commands, commandDuration{label, ms, c.ExitStatus})
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		if commands[i].durationMS == commands[j].durationMS {
+			return commands[i].label < commands[j].label
+		}
+		return commands[i].durationMS > commands[j].durationMS
+	})
+	for _, c := range commands {
+		durations = append(durations, []string{c.label, fmt.Sprintf("%d", c.durationMS), c.exitStatus})
+	}
+	return
+}
+
 // return array of lines from command output, or empty array if no match or all empty lines
 func (s *Source) getCommandOutputLines(cmdLabel string) (lines []string) {
 	cmdout := s.getCommandOutput(cmdLabel)
@@ -223,6 +424,88 @@ func (s *Source) valFromDmiDecodeRegexSubmatch(dmiType string, regex string) (va
 	return
 }
 
+// getHugepagesPerNode returns the number of reserved 2M and 1G hugepages on each NUMA node,
+// fields are left blank when the page size isn't found in sysfs
+func (s *Source) getHugepagesPerNode() (values [][]string) {
+	// /sys/devices/system/node/node0/hugepages/hugepages-2048kB/nr_hugepages:512
+	matches := s.valsArrayFromRegexSubmatch("hugepages per node", `/node(\d+)/hugepages/hugepages-(\d+)kB/nr_hugepages:(\d+)$`)
+	nodePages := make(map[int][]string) // node: [2M pages, 1G pages]
+	var nodes []int
+	for _, match := range matches {
+		node, err := strconv.Atoi(match[0])
+		if err != nil {
+			continue
+		}
+		if _, ok := nodePages[node]; !ok {
+			nodePages[node] = []string{"", ""}
+			nodes = append(nodes, node)
+		}
+		switch match[1] {
+		case "2048":
+			nodePages[node][0] = match[2]
+		case "1048576":
+			nodePages[node][1] = match[2]
+		}
+	}
+	sort.Ints(nodes)
+	for _, node := range nodes {
+		values = append(values, append([]string{fmt.Sprintf("%d", node)}, nodePages[node]...))
+	}
+	return
+}
+
+// getCPUVendor returns the processor manufacturer reported by dmidecode, e.g.,
+// "Intel(R) Corporation" or "Advanced Micro Devices, Inc."
+func (s *Source) getCPUVendor() (vendor string) {
+	vendor = s.valFromDmiDecodeRegexSubmatch("4", `^Manufacturer:\s*(.+?)$`)
+	return
+}
+
+func (s *Source) isAMD() bool {
+	vendor := s.getCPUVendor()
+	return strings.Contains(vendor, "AMD") || strings.Contains(vendor, "Advanced Micro Devices")
+}
+
+// getMLCBandwidthScale returns the factor that converts the bandwidth values found in the
+// MLC command's output to MB/sec. MLC usually reports MB/sec, but output from some versions,
+// e.g., as seen on AMD EPYC, reports GB/s. When the output doesn't include the units, values
+// that are too small to be MB/sec on AMD systems are assumed to be GB/s.
+func (s *Source) getMLCBandwidthScale(cmdLabel string) (scale float64) {
+	scale = 1
+	output := s.getCommandOutput(cmdLabel)
+	if regexp.MustCompile(`(?i)\bMB/s(ec)?\b`).MatchString(output) {
+		return
+	}
+	if regexp.MustCompile(`(?i)\bGB/s(ec)?\b`).MatchString(output) {
+		scale = 1000
+		return
+	}
+	if !s.isAMD() {
+		return
+	}
+	// the last column is bandwidth in both the bandwidth matrix and loaded latency output
+	reValue := regexp.MustCompile(`^\d+\s+.*?(\d*\.?\d+)$`)
+	foundValue := false
+	for _, line := range s.getCommandOutputLines(cmdLabel) {
+		match := reValue.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		value, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			continue
+		}
+		foundValue = true
+		if value >= 1000 {
+			return
+		}
+	}
+	if foundValue {
+		scale = 1000
+	}
+	return
+}
+
 // finds first match in dmiType section of DMI Decode output
 // return array of values from regex submatches or zero-length array if no match
 func (s *Source) valsFromDmiDecodeRegexSubmatch(dmiType string, regex string) (vals []string) {
@@ -426,6 +709,52 @@ func (s *Source) getNUMACPUList() (val string) {
 	return
 }
 
+// getCoresPerSocket returns lscpu's cores per socket when all sockets have the same number of
+// on-line cores, otherwise the per-socket on-line core counts, e.g., "28/26"
+func (s *Source) getCoresPerSocket(lscpuCoresPerSocket string) (val string) {
+	val = lscpuCoresPerSocket
+	socketCores := make(map[int]map[string]bool) // socket: set of core ids
+	socket := -1
+	for _, line := range s.getCommandOutputLines("/proc/cpuinfo") {
+		fields := strings.SplitN(line, ":", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(fields[0])
+		value := strings.TrimSpace(fields[1])
+		if key == "physical id" {
+			var err error
+			if socket, err = strconv.Atoi(value); err != nil {
+				return
+			}
+		} else if key == "core id" && socket >= 0 {
+			if socketCores[socket] == nil {
+				socketCores[socket] = make(map[string]bool)
+			}
+			socketCores[socket][value] = true
+		}
+	}
+	if len(socketCores) < 2 {
+		return
+	}
+	var counts []string
+	symmetric := true
+	for socket := 0; socket < len(socketCores); socket++ {
+		cores, ok := socketCores[socket]
+		if !ok {
+			return // unexpected socket numbering
+		}
+		if len(cores) != len(socketCores[0]) {
+			symmetric = false
+		}
+		counts = append(counts, fmt.Sprintf("%d", len(cores)))
+	}
+	if !symmetric {
+		val = strings.Join(counts, "/")
+	}
+	return
+}
+
 func (s *Source) getUncoreMaxFrequency(uArch string) (val string) {
 	var parsed int64
 	var err error
@@ -1009,6 +1338,41 @@ func (s *Source) getPowerPerfPolicy() (val string) {
 	return
 }
 
+// getHWP returns whether Hardware P-States (HWP) are enabled, from IA32_PM_ENABLE
+func (s *Source) getHWP() (val string) {
+	msrHex := s.getCommandOutputLine("rdmsr 0x770")
+	msr, err := strconv.ParseInt(msrHex, 16, 0)
+	if err == nil {
+		// bit 0 of IA32_PM_ENABLE
+		if msr&1 == 1 {
+			val = "Enabled"
+		} else {
+			val = "Disabled"
+		}
+	}
+	return
+}
+
+// getEnergyPerformancePreference returns the CPUs' EPP, e.g., "balance_performance". When
+// CPUs have different values, each is listed with its CPU count, e.g.,
+// "balance_performance (94), power (2)".
+func (s *Source) getEnergyPerformancePreference() (val string) {
+	var values []string
+	for _, line := range s.getCommandOutputLines("energy_performance_preference") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		values = append(values, fmt.Sprintf("%s (%s)", fields[1], fields[0]))
+	}
+	if len(values) == 1 {
+		val = strings.Fields(values[0])[0]
+	} else {
+		val = strings.Join(values, ", ")
+	}
+	return
+}
+
 func (s *Source) getTDP() (val string) {
 	msrHex := s.getCommandOutputLine("rdmsr 0x610")
 	msr, err := strconv.ParseInt(msrHex, 16, 0)
@@ -1018,6 +1382,18 @@ func (s *Source) getTDP() (val string) {
 	return
 }
 
+// getDiskSerial returns the device's serial number from lsblk, e.g., for device sda
+func (s *Source) getDiskSerial(device string) (serial string) {
+	for _, line := range s.getCommandOutputLines("disk serial") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == device {
+			serial = fields[1]
+			break
+		}
+	}
+	return
+}
+
 // get the FwRev for the given device from hdparm
 func (s *Source) getDiskFwRev(device string) (fwRev string) {
 	reFwRev := regexp.MustCompile(`FwRev=(\w+)`)
@@ -1118,6 +1494,768 @@ func (s *Source) getSystemFolded() (folded string) {
 	return
 }
 
+// getCacheContention parses 'perf c2c report --stdio' output and returns the cache lines with the
+// most HITMs (loads that hit a modified line in another core's cache), in the order perf reports
+// them, with the symbols that access each line
+func (s *Source) getCacheContention(maxLines int) (lines [][]string) {
+	const (
+		sectionNone = iota
+		sectionCacheLines
+		sectionPareto
+	)
+	section := sectionNone
+	// e.g., "      0      0xffff8b4e8a2e0000     0    2563   21.65%     1951     1951        0 ..."
+	reCacheLine := regexp.MustCompile(`^\s*(\d+)\s+(0x[0-9a-fA-F]+)\s+(\S+)\s+(.*)$`)
+	// e.g., "      0        0     1951     2559        0      0xffff8b4e8a2e0000"
+	reParetoLine := regexp.MustCompile(`^\s*(\d+)\s+.*\s(0x[0-9a-fA-F]+)\s*$`)
+	// e.g., "... 36  [k] __update_load_avg_cfs_rq  [kernel.kallsyms] ..."
+	reSymbol := regexp.MustCompile(`\[[k.]\]\s+(\S+)`)
+	indexes := make(map[string]int) // cache line index -> index into lines
+	var paretoIdx = -1
+	for _, line := range strings.Split(s.getCommandOutput("perf c2c"), "\n") {
+		if strings.Contains(line, "Shared Data Cache Line Table") {
+			section = sectionCacheLines
+			continue
+		} else if strings.Contains(line, "Shared Cache Line Distribution Pareto") {
+			section = sectionPareto
+			continue
+		} else if strings.HasPrefix(line, "#") || strings.HasPrefix(strings.TrimSpace(line), "---") {
+			continue
+		}
+		switch section {
+		case sectionCacheLines:
+			match := reCacheLine.FindStringSubmatch(line)
+			if match == nil || len(lines) >= maxLines {
+				continue
+			}
+			// the total HITM percentage is the first percentage, the HITM count follows it
+			var hitmPct, hitmCount string
+			fields := strings.Fields(match[4])
+			for i, field := range fields {
+				if strings.HasSuffix(field, "%") {
+					hitmPct = field
+					if i+1 < len(fields) {
+						hitmCount = fields[i+1]
+					}
+					break
+				}
+			}
+			indexes[match[1]] = len(lines)
+			lines = append(lines, []string{match[1], match[2], match[3], hitmPct, hitmCount, ""})
+		case sectionPareto:
+			if match := reParetoLine.FindStringSubmatch(line); match != nil && !reSymbol.MatchString(line) {
+				paretoIdx = -1
+				if idx, ok := indexes[match[1]]; ok && lines[idx][1] == match[2] {
+					paretoIdx = idx
+				}
+				continue
+			}
+			if paretoIdx == -1 {
+				continue
+			}
+			if match := reSymbol.FindStringSubmatch(line); match != nil {
+				symbols := lines[paretoIdx][5]
+				if symbols == "" {
+					lines[paretoIdx][5] = match[1]
+				} else if !strings.Contains(", "+symbols+", ", ", "+match[1]+", ") {
+					lines[paretoIdx][5] = symbols + ", " + match[1]
+				}
+			}
+		}
+	}
+	return
+}
+
+// getNVMeModels returns the model of each NVMe controller, e.g., nvme0, from 'nvme list' output.
+// The columns are located by the widths of the dashes under the column headers because values,
+// e.g., the model, may contain spaces.
+func (s *Source) getNVMeModels() (models map[string]string) {
+	models = make(map[string]string)
+	lines := s.getCommandOutputLines("nvme list")
+	if len(lines) < 2 {
+		return
+	}
+	type column struct{ start, end int }
+	var columns []column
+	for _, match := range regexp.MustCompile(`-+`).FindAllStringIndex(lines[1], -1) {
+		columns = append(columns, column{match[0], match[1]})
+	}
+	nodeIdx, modelIdx := -1, -1
+	for i, col := range columns {
+		if col.start >= len(lines[0]) {
+			break
+		}
+		header := strings.TrimSpace(lines[0][col.start:min(col.end, len(lines[0]))])
+		if header == "Node" {
+			nodeIdx = i
+		} else if header == "Model" {
+			modelIdx = i
+		}
+	}
+	if nodeIdx == -1 || modelIdx == -1 {
+		return
+	}
+	reController := regexp.MustCompile(`^/dev/(nvme[0-9]+)`)
+	for _, line := range lines[2:] {
+		if len(line) < columns[modelIdx].start {
+			continue
+		}
+		node := strings.TrimSpace(line[columns[nodeIdx].start:min(columns[nodeIdx].end, len(line))])
+		match := reController.FindStringSubmatch(node)
+		if match == nil {
+			continue
+		}
+		models[match[1]] = strings.TrimSpace(line[columns[modelIdx].start:min(columns[modelIdx].end, len(line))])
+	}
+	return
+}
+
+// getNVMeCriticalWarnings decodes the NVMe SMART critical warning bits
+func getNVMeCriticalWarnings(criticalWarning string) string {
+	val, err := strconv.ParseUint(criticalWarning, 0, 8)
+	if err != nil {
+		return criticalWarning
+	}
+	if val == 0 {
+		return "None"
+	}
+	var warnings []string
+	for bit, warning := range []string{
+		"Available Spare Below Threshold",
+		"Temperature Threshold Exceeded",
+		"Reliability Degraded",
+		"Read Only",
+		"Volatile Memory Backup Failed",
+		"Persistent Memory Region Read Only",
+	} {
+		if val&(1<<bit) != 0 {
+			warnings = append(warnings, warning)
+		}
+	}
+	return strings.Join(warnings, ", ")
+}
+
+// getNVMeHealth parses 'nvme smart-log' output and returns the health of each NVMe controller.
+// The Health column is "Warning" when the device reports a critical warning or when more than
+// 90% of its rated endurance has been used.
+func (s *Source) getNVMeHealth() (health [][]string) {
+	models := s.getNVMeModels()
+	reDevice := regexp.MustCompile(`^NVME DEVICE: /dev/(nvme[0-9]+)$`)
+	// e.g., "percentage_used				: 2%"
+	reField := regexp.MustCompile(`^(\S+)\s*:\s*(.+?)\s*$`)
+	const (
+		deviceIdx = iota
+		modelIdx
+		criticalWarningIdx
+		percentageUsedIdx
+		temperatureIdx
+		dataUnitsReadIdx
+		dataUnitsWrittenIdx
+		healthIdx
+		fieldCount
+	)
+	fieldIndexes := map[string]int{
+		"critical_warning":   criticalWarningIdx,
+		"percentage_used":    percentageUsedIdx,
+		"temperature":        temperatureIdx,
+		"data_units_read":    dataUnitsReadIdx,
+		"data_units_written": dataUnitsWrittenIdx,
+	}
+	for _, line := range s.getCommandOutputLines("nvme smart-log") {
+		if match := reDevice.FindStringSubmatch(line); match != nil {
+			row := make([]string, fieldCount)
+			row[deviceIdx] = match[1]
+			row[modelIdx] = models[match[1]]
+			health = append(health, row)
+			continue
+		}
+		if len(health) == 0 {
+			continue
+		}
+		match := reField.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		idx, ok := fieldIndexes[match[1]]
+		if !ok {
+			continue
+		}
+		value := match[2]
+		if idx == temperatureIdx {
+			// e.g., "38 C (311 Kelvin)" or "38 °C (311 K)"
+			value = strings.TrimSpace(strings.Split(value, "(")[0])
+		}
+		health[len(health)-1][idx] = value
+	}
+	for _, row := range health {
+		row[healthIdx] = "OK"
+		if row[criticalWarningIdx] != "" {
+			row[criticalWarningIdx] = getNVMeCriticalWarnings(row[criticalWarningIdx])
+			if row[criticalWarningIdx] != "None" {
+				row[healthIdx] = "Warning"
+			}
+		}
+		if used, err := strconv.Atoi(strings.TrimSuffix(row[percentageUsedIdx], "%")); err == nil && used > 90 {
+			row[healthIdx] = "Warning"
+		}
+	}
+	return
+}
+
+// getNICs returns the names of the PCI and USB network interfaces reported by lshw
+func (s *Source) getNICs() (nics []string) {
+	nics = s.valsFromRegexSubmatch("lshw", `^pci.*? (\S+)\s+network\s+\S.*?\s+\[\w+:\w+]$`)
+	nics = append(nics, s.valsFromRegexSubmatch("lshw", `^usb.*? (\S+)\s+network\s+\S.*?$`)...)
+	return
+}
+
+// getNICQueueStats parses the per-queue packet and drop counters from the
+// 'ethtool -S' output of each NIC. Counter names vary by driver, e.g.,
+// rx_queue_0_packets (ice, ixgbe), rx-0.packets (i40e), rx0_packets (mlx5).
+// A Total row per NIC holds the interface-level counters, where RX Drops is
+// the sum of rx_dropped and rx_missed.
+func (s *Source) getNICQueueStats() (stats [][]string) {
+	reInterface := regexp.MustCompile(`^INTERFACE: (\S+)$`)
+	reQueue := regexp.MustCompile(`^\s*(rx|tx)[_-]?(?:queue[_-])?(\d+)[_.](packets|drops|dropped|drop):\s*(\d+)$`)
+	reTotal := regexp.MustCompile(`^\s*(rx_packets|tx_packets|rx_dropped|rx_missed|rx_missed_errors|tx_dropped):\s*(\d+)$`)
+	type queueStats struct {
+		rxPackets, rxDrops, txPackets, txDrops string
+	}
+	var nic string
+	queues := make(map[string]map[int]*queueStats)
+	totals := make(map[string]map[string]uint64)
+	for _, line := range s.getCommandOutputLines("ethtool -S") {
+		if match := reInterface.FindStringSubmatch(line); match != nil {
+			nic = match[1]
+			queues[nic] = make(map[int]*queueStats)
+			totals[nic] = make(map[string]uint64)
+			continue
+		}
+		if nic == "" {
+			continue
+		}
+		if match := reQueue.FindStringSubmatch(line); match != nil {
+			queue, _ := strconv.Atoi(match[2])
+			if queues[nic][queue] == nil {
+				queues[nic][queue] = &queueStats{}
+			}
+			qs := queues[nic][queue]
+			if match[1] == "rx" {
+				if match[3] == "packets" {
+					qs.rxPackets = match[4]
+				} else {
+					qs.rxDrops = match[4]
+				}
+			} else {
+				if match[3] == "packets" {
+					qs.txPackets = match[4]
+				} else {
+					qs.txDrops = match[4]
+				}
+			}
+			continue
+		}
+		if match := reTotal.FindStringSubmatch(line); match != nil {
+			val, _ := strconv.ParseUint(match[2], 10, 64)
+			totals[nic][match[1]] = val
+		}
+	}
+	for _, nic := range s.getNICs() {
+		if _, ok := queues[nic]; !ok {
+			continue
+		}
+		var queueIDs []int
+		for queue := range queues[nic] {
+			queueIDs = append(queueIDs, queue)
+		}
+		sort.Ints(queueIDs)
+		for _, queue := range queueIDs {
+			qs := queues[nic][queue]
+			stats = append(stats, []string{nic, strconv.Itoa(queue), qs.rxPackets, qs.rxDrops, qs.txPackets, qs.txDrops, ""})
+		}
+		total := totals[nic]
+		rxDrops := total["rx_dropped"] + total["rx_missed"] + total["rx_missed_errors"]
+		status := "OK"
+		if rxDrops != 0 {
+			status = "Dropping"
+		}
+		stats = append(stats, []string{
+			nic,
+			"Total",
+			strconv.FormatUint(total["rx_packets"], 10),
+			strconv.FormatUint(rxDrops, 10),
+			strconv.FormatUint(total["tx_packets"], 10),
+			strconv.FormatUint(total["tx_dropped"], 10),
+			status,
+		})
+	}
+	return
+}
+
+// getTSCStability returns "Unstable" when the kernel log reports that the TSC was marked
+// unstable, e.g., by the clocksource watchdog, "Stable" when the kernel log was read and it
+// doesn't, otherwise empty string
+func (s *Source) getTSCStability() (val string) {
+	if c, ok := s.ParsedData["dmesg tsc"]; !ok || c.ExitStatus != "0" {
+		return
+	}
+	re := regexp.MustCompile(`(?i)tsc.*unstable|unstable.*tsc`)
+	for _, line := range s.getCommandOutputLines("dmesg tsc") {
+		if re.MatchString(line) {
+			return "Unstable"
+		}
+	}
+	return "Stable"
+}
+
+// getTunedProfile returns the active tuned profile, or empty string when tuned isn't running
+func (s *Source) getTunedProfile() (val string) {
+	return s.valFromRegexSubmatch("tuned-adm active", `^Current active profile:\s*(.+)$`)
+}
+
+// getKernelModules returns the name, size, and used by count of each loaded module, in lsmod
+// order, and the parameters of the modules of interest
+func (s *Source) getKernelModules(modulesOfInterest []string) (modules [][]string) {
+	// e.g., "/sys/module/ice/parameters/debug_mask:0"
+	reParam := regexp.MustCompile(`^/sys/module/([^/]+)/parameters/([^:]+):(.*)$`)
+	interest := make(map[string]bool)
+	for _, module := range modulesOfInterest {
+		interest[module] = true
+	}
+	params := make(map[string][]string)
+	for _, line := range s.getCommandOutputLines("module parameters") {
+		match := reParam.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if !interest[match[1]] {
+			continue
+		}
+		params[match[1]] = append(params[match[1]], match[2]+"="+match[3])
+	}
+	for i, line := range s.getCommandOutputLines("lsmod") {
+		// e.g., "ice                  1130496  0"
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 3 { // skip the header
+			continue
+		}
+		sort.Strings(params[fields[0]])
+		modules = append(modules, []string{fields[0], fields[1], fields[2], strings.Join(params[fields[0]], ", ")})
+	}
+	return
+}
+
+// getContainers returns the runtime, ID, image, state, and names of the containers listed by
+// docker and/or crictl, whichever are installed
+func (s *Source) getContainers() (containers [][]string) {
+	// IDs are shortened to the length displayed by the runtimes' ps commands
+	shortID := func(id string) string {
+		if len(id) > 12 {
+			return id[:12]
+		}
+		return id
+	}
+	var runtime string
+	var crictlOutput []string
+	for _, line := range strings.Split(s.getCommandOutput("containers"), "\n") {
+		if strings.HasPrefix(line, "RUNTIME: ") {
+			runtime = strings.TrimPrefix(line, "RUNTIME: ")
+			continue
+		}
+		switch runtime {
+		case "docker":
+			// e.g., 4c01db0b339c...|nginx:latest|running|web
+			fields := strings.Split(line, "|")
+			if len(fields) != 4 {
+				continue
+			}
+			containers = append(containers, []string{runtime, shortID(fields[0]), fields[1], fields[2], fields[3]})
+		case "crictl":
+			crictlOutput = append(crictlOutput, line)
+		}
+	}
+	if len(crictlOutput) == 0 || strings.TrimSpace(strings.Join(crictlOutput, "")) == "" {
+		return
+	}
+	var data struct {
+		Containers []struct {
+			ID       string `json:"id"`
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Image struct {
+				Image string `json:"image"`
+			} `json:"image"`
+			State string `json:"state"`
+		} `json:"containers"`
+	}
+	if err := json.Unmarshal([]byte(strings.Join(crictlOutput, "\n")), &data); err != nil {
+		log.Printf("failed to parse crictl output: %v", err)
+		return
+	}
+	for _, c := range data.Containers {
+		// e.g., CONTAINER_RUNNING
+		state := strings.ToLower(strings.TrimPrefix(c.State, "CONTAINER_"))
+		containers = append(containers, []string{"crictl", shortID(c.ID), c.Image.Image, state, c.Metadata.Name})
+	}
+	return
+}
+
+// getRAPLPowerLimits returns the package power limits, PL1 (long_term) and PL2 (short_term),
+// and their time windows, one value per package, or one value when all packages match
+func (s *Source) getRAPLPowerLimits() (pl1, pl1Window, pl2, pl2Window string) {
+	var pl1s, pl1Windows, pl2s, pl2Windows []string
+	for _, line := range s.getCommandOutputLines("rapl power limits") {
+		fields := strings.Split(line, "|")
+		if len(fields) != 4 || !strings.HasPrefix(fields[0], "package") {
+			continue
+		}
+		var limit, window string
+		if uw, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			limit = fmt.Sprintf("%dW", uw/1000000)
+		}
+		if us, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
+			window = (time.Duration(us) * time.Microsecond).String()
+		}
+		switch fields[1] {
+		case "long_term":
+			pl1s = append(pl1s, limit)
+			pl1Windows = append(pl1Windows, window)
+		case "short_term":
+			pl2s = append(pl2s, limit)
+			pl2Windows = append(pl2Windows, window)
+		}
+	}
+	// one value per package, unless all packages have the same value
+	join := func(vals []string) string {
+		for _, val := range vals {
+			if val != vals[0] {
+				return strings.Join(vals, ", ")
+			}
+		}
+		if len(vals) > 0 {
+			return vals[0]
+		}
+		return ""
+	}
+	return join(pl1s), join(pl1Windows), join(pl2s), join(pl2Windows)
+}
+
+// getIOMMUKernelParameters returns the IOMMU related kernel boot parameters, e.g., intel_iommu=on iommu=pt
+func (s *Source) getIOMMUKernelParameters() (params string) {
+	var iommuParams []string
+	for _, param := range strings.Fields(s.getCommandOutputLine("/proc/cmdline")) {
+		if strings.HasPrefix(param, "intel_iommu=") || strings.HasPrefix(param, "amd_iommu=") || strings.HasPrefix(param, "iommu=") || strings.HasPrefix(param, "iommu.") {
+			iommuParams = append(iommuParams, param)
+		}
+	}
+	return strings.Join(iommuParams, " ")
+}
+
+// getIOMMUGroups returns the number of IOMMU groups and their default domain type(s), e.g., DMA-FQ or
+// identity, status is Enabled when the kernel created IOMMU groups, blank when not collected
+func (s *Source) getIOMMUGroups() (status, groups, domainTypes string) {
+	if _, ok := s.ParsedData["iommu groups"]; !ok {
+		return
+	}
+	// "     42 DMA-FQ"
+	count := 0
+	var types []string
+	for _, match := range s.valsArrayFromRegexSubmatch("iommu groups", `^\s*(\d+)\s+(\S+)$`) {
+		n, err := strconv.Atoi(match[0])
+		if err != nil {
+			continue
+		}
+		count += n
+		types = append(types, match[1])
+	}
+	status = "Disabled"
+	if count > 0 {
+		status = "Enabled"
+	}
+	return status, fmt.Sprintf("%d", count), strings.Join(types, ", ")
+}
+
+// getSRIOVFunctions returns the PCI address, network interface, configured VFs, and supported VFs
+// of each SR-IOV capable physical function
+func (s *Source) getSRIOVFunctions() (pfs [][]string) {
+	for _, line := range s.getCommandOutputLines("sriov") {
+		fields := strings.Split(line, "|")
+		if len(fields) != 4 {
+			continue
+		}
+		pfs = append(pfs, fields)
+	}
+	return
+}
+
+// getNUMAStats returns, for each NUMA node, the numa_hit, numa_miss, and other_node page counts
+// since boot, the percentage of allocations that missed the node, and the node's total, free, and
+// used memory
+func (s *Source) getNUMAStats() (values [][]string) {
+	// numastat:
+	//                            node0           node1
+	// numa_hit               123456789       987654321
+	var nodes []string
+	counts := make(map[string][]string) // counter name: value per node
+	for _, line := range s.getCommandOutputLines("numastat") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && strings.HasPrefix(fields[0], "node") {
+			nodes = fields
+			continue
+		}
+		if len(fields) == len(nodes)+1 {
+			counts[fields[0]] = fields[1:]
+		}
+	}
+	// numastat -m:
+	//                           Node 0          Node 1           Total
+	// MemTotal                95345.12        96761.37       192106.49
+	memory := make(map[string][]string) // meminfo name: value (MB) per node, then total
+	for _, line := range s.getCommandOutputLines("numastat -m") {
+		fields := strings.Fields(line)
+		if len(fields) == len(nodes)+2 {
+			memory[fields[0]] = fields[1:]
+		}
+	}
+	for i, node := range nodes {
+		value := func(values map[string][]string, name string) string {
+			if v, ok := values[name]; ok && i < len(v) {
+				return v[i]
+			}
+			return ""
+		}
+		megabytes := func(name string) string {
+			mb, err := strconv.ParseFloat(value(memory, name), 64)
+			if err != nil {
+				return ""
+			}
+			return fmt.Sprintf("%.0f MB", mb)
+		}
+		var missPercent string
+		hit, errHit := strconv.ParseFloat(value(counts, "numa_hit"), 64)
+		miss, errMiss := strconv.ParseFloat(value(counts, "numa_miss"), 64)
+		if errHit == nil && errMiss == nil && hit+miss > 0 {
+			missPercent = fmt.Sprintf("%.2f", miss/(hit+miss)*100)
+		}
+		values = append(values, []string{
+			strings.TrimPrefix(node, "node"),
+			value(counts, "numa_hit"),
+			value(counts, "numa_miss"),
+			value(counts, "other_node"),
+			missPercent,
+			megabytes("MemTotal"),
+			megabytes("MemFree"),
+			megabytes("MemUsed"),
+		})
+	}
+	return
+}
+
+// GPUDetails are a GPU's details as reported by its vendor's tools, e.g., nvidia-smi
+type GPUDetails struct {
+	Manufacturer string
+	Model        string
+	PCIAddress   string // normalized, e.g., 0000:3b:00.0
+	Driver       string
+	Firmware     string
+	Memory       string
+}
+
+// normalizePCIAddress returns the PCI address in lshw's form, e.g., 00000000:3B:00.0 is 0000:3b:00.0
+func normalizePCIAddress(address string) string {
+	fields := strings.SplitN(strings.ToLower(address), ":", 2)
+	if len(fields) != 2 {
+		return strings.ToLower(address)
+	}
+	domain, err := strconv.ParseUint(fields[0], 16, 32)
+	if err != nil {
+		return strings.ToLower(address)
+	}
+	return fmt.Sprintf("%04x:%s", domain, fields[1])
+}
+
+// getGPUDetails returns the GPUs reported by nvidia-smi, xpu-smi, and, for GPUs that neither
+// reports, clinfo. There are none when the tools aren't installed.
+func (s *Source) getGPUDetails() (gpus []GPUDetails) {
+	gpus = append(gpus, s.getNvidiaGPUDetails()...)
+	gpus = append(gpus, s.getXPUGPUDetails()...)
+	for _, clGPU := range s.getOpenCLGPUDetails() {
+		if clGPU.PCIAddress == "" || !slices.ContainsFunc(gpus, func(gpu GPUDetails) bool { return gpu.PCIAddress == clGPU.PCIAddress }) {
+			gpus = append(gpus, clGPU)
+		}
+	}
+	return
+}
+
+func (s *Source) getNvidiaGPUDetails() (gpus []GPUDetails) {
+	output := s.getCommandOutput("nvidia-smi")
+	if output == "" {
+		return
+	}
+	var smiLog struct {
+		DriverVersion string `xml:"driver_version"`
+		GPUs          []struct {
+			ID           string `xml:"id,attr"`
+			ProductName  string `xml:"product_name"`
+			VBIOSVersion string `xml:"vbios_version"`
+			MemoryTotal  string `xml:"fb_memory_usage>total"`
+		} `xml:"gpu"`
+	}
+	if err := xml.Unmarshal([]byte(output), &smiLog); err != nil {
+		log.Printf("failed to parse nvidia-smi output: %v", err)
+		return
+	}
+	for _, gpu := range smiLog.GPUs {
+		gpus = append(gpus, GPUDetails{
+			Manufacturer: "NVIDIA",
+			Model:        gpu.ProductName,
+			PCIAddress:   normalizePCIAddress(gpu.ID),
+			Driver:       smiLog.DriverVersion,
+			Firmware:     gpu.VBIOSVersion,
+			Memory:       gpu.MemoryTotal,
+		})
+	}
+	return
+}
+
+func (s *Source) getXPUGPUDetails() (gpus []GPUDetails) {
+	// one JSON object per device, from xpu-smi discovery -d <id> -j
+	decoder := json.NewDecoder(strings.NewReader(s.getCommandOutput("xpu-smi")))
+	for decoder.More() {
+		var device struct {
+			DeviceName         string `json:"device_name"`
+			PCIBDFAddress      string `json:"pci_bdf_address"`
+			DriverVersion      string `json:"driver_version"`
+			GFXFirmwareVersion string `json:"gfx_firmware_version"`
+			MemoryPhysicalSize string `json:"memory_physical_size_byte"`
+		}
+		if err := decoder.Decode(&device); err != nil {
+			log.Printf("failed to parse xpu-smi output: %v", err)
+			return
+		}
+		gpus = append(gpus, GPUDetails{
+			Manufacturer: "Intel",
+			Model:        device.DeviceName,
+			PCIAddress:   normalizePCIAddress(device.PCIBDFAddress),
+			Driver:       device.DriverVersion,
+			Firmware:     device.GFXFirmwareVersion,
+			Memory:       bytesToMiB(device.MemoryPhysicalSize),
+		})
+	}
+	return
+}
+
+func (s *Source) getOpenCLGPUDetails() (gpus []GPUDetails) {
+	// clinfo --raw:
+	// [INTEL/0]  CL_DEVICE_NAME                  Intel(R) Data Center GPU Flex 170
+	// [INTEL/0]  CL_DEVICE_TYPE                  CL_DEVICE_TYPE_GPU
+	// [INTEL/0]  CL_DEVICE_PCI_BUS_INFO_KHR      PCI-E, 0000:4d:00.0
+	reLine := regexp.MustCompile(`^\[(\S+)]\s+(CL_\w+)\s+(.*)$`)
+	reAddress := regexp.MustCompile(`[0-9a-fA-F]+:[0-9a-fA-F]{2}:[0-9a-fA-F]{2}\.[0-7]`)
+	var devices []string
+	properties := make(map[string]map[string]string) // device: property name: value
+	for _, line := range s.getCommandOutputLines("clinfo") {
+		match := reLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if _, ok := properties[match[1]]; !ok {
+			devices = append(devices, match[1])
+			properties[match[1]] = make(map[string]string)
+		}
+		properties[match[1]][match[2]] = match[3]
+	}
+	for _, device := range devices {
+		property := properties[device]
+		// platform-level properties are listed under the platform name, e.g., [INTEL/*]
+		if !strings.Contains(property["CL_DEVICE_TYPE"], "CL_DEVICE_TYPE_GPU") {
+			continue
+		}
+		manufacturer := property["CL_DEVICE_VENDOR"]
+		if strings.HasPrefix(manufacturer, "Intel") {
+			manufacturer = "Intel"
+		}
+		gpus = append(gpus, GPUDetails{
+			Manufacturer: manufacturer,
+			Model:        property["CL_DEVICE_NAME"],
+			PCIAddress:   normalizePCIAddress(reAddress.FindString(property["CL_DEVICE_PCI_BUS_INFO_KHR"])),
+			Driver:       property["CL_DRIVER_VERSION"],
+			Memory:       bytesToMiB(property["CL_DEVICE_GLOBAL_MEM_SIZE"]),
+		})
+	}
+	return
+}
+
+// bytesToMiB formats a number of bytes, e.g., 17179869184, in MiB, e.g., 16384 MiB, as reported
+// by nvidia-smi
+func bytesToMiB(bytes string) string {
+	size, err := strconv.ParseUint(bytes, 10, 64)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d MiB", size/(1024*1024))
+}
+
+// getCXLMemoryDevices returns, for each CXL memory device listed by cxl-cli, its name, host PCI
+// address, serial number, NUMA node, total, volatile, and persistent capacity, and the QoS class
+// of its volatile and persistent partitions
+func (s *Source) getCXLMemoryDevices() (devices [][]string) {
+	output := strings.TrimSpace(s.getCommandOutput("cxl list"))
+	if output == "" {
+		return
+	}
+	// a single device is listed as an object rather than an array of objects
+	if strings.HasPrefix(output, "{") {
+		output = "[" + output + "]"
+	}
+	var memdevs []map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(output))
+	decoder.UseNumber()
+	if err := decoder.Decode(&memdevs); err != nil {
+		log.Printf("failed to parse cxl list output: %v", err)
+		return
+	}
+	for _, memdev := range memdevs {
+		value := func(key string) string {
+			if v, ok := memdev[key]; ok {
+				return fmt.Sprintf("%v", v)
+			}
+			return ""
+		}
+		size := func(key string) (bytes int64) {
+			if v, ok := memdev[key].(json.Number); ok {
+				bytes, _ = v.Int64()
+			}
+			return
+		}
+		var ram, pmem string
+		if size("ram_size") > 0 {
+			ram = bytesToGiB(size("ram_size"))
+		}
+		if size("pmem_size") > 0 {
+			pmem = bytesToGiB(size("pmem_size"))
+		}
+		devices = append(devices, []string{
+			value("memdev"),
+			value("host"),
+			value("serial"),
+			value("numa_node"),
+			bytesToGiB(size("ram_size") + size("pmem_size")),
+			ram,
+			pmem,
+			value("ram_qos_class"),
+			value("pmem_qos_class"),
+		})
+	}
+	return
+}
+
+// bytesToGiB formats a number of bytes, e.g., 274877906944, in GiB, e.g., 256 GiB
+func bytesToGiB(bytes int64) string {
+	return strconv.FormatFloat(float64(bytes)/(1024*1024*1024), 'f', -1, 64) + " GiB"
+}
+
 func (s *Source) getTurboEnabled(family string) (val string) {
 	if family == "6" { // Intel
 		val = enabledIfValAndTrue(s.valFromRegexSubmatch("cpuid -1", `^Intel Turbo Boost Technology\s*= (.+?)$`))
@@ -1197,7 +2335,7 @@ func (s *Source) getPMUMetrics() (orderedMetricNames []string, timeStamps []floa
 		label := rows[0][colIdx]
 		// skip metrics with no name (can occur in some rare situations)
 		// svr-info runs pmu2metrics with system-level scope and granularity, skip irrelevant columns
-		if label == "" || label == "SKT" || label == "CPU" || label == "PID" || label == "CMD" || label == "CID" {
+		if label == "" || label == "SKT" || label == "NODE" || label == "CPU" || label == "PID" || label == "CMD" || label == "CID" {
 			continue
 		}
 		if colIdx != 0 { // don't put timestamp column in the metric names list
@@ -1291,3 +2429,91 @@ func (s *Source) getEfficiencyLatencyControl() (valueNames []string, values [][]
 	}
 	return
 }
+
+// getSSTPerfProfiles parses intel-speed-select perf-profile output into one row per
+// SST-PP level. Levels are reported once, i.e., from the first package/die/cpu block.
+func (s *Source) getSSTPerfProfiles() (valueNames []string, values [][]string) {
+	lines := s.getCommandOutputLines("intel-speed-select perf-profile info")
+	if len(lines) == 0 {
+		return
+	}
+	currentLevel := s.valFromRegexSubmatch("intel-speed-select perf-profile current level", `^get-config-current_level:(\d+)`)
+	if currentLevel == "" {
+		currentLevel = s.valFromRegexSubmatch("intel-speed-select perf-profile info", `^get-config-current_level:(\d+)`)
+	}
+	type perfProfile struct {
+		level         string
+		cores         string
+		baseFreq      string
+		turboFreqs    []string // SSE turbo buckets, highest first
+		tdp           string
+		alreadyParsed bool
+	}
+	var profiles []*perfProfile
+	var profile *perfProfile
+	seenLevels := make(map[string]bool)
+	reLevel := regexp.MustCompile(`^perf-profile-level-(\d+)$`)
+	inSSETurbo := false
+	for _, line := range lines {
+		if match := reLevel.FindStringSubmatch(line); match != nil {
+			profile = &perfProfile{level: match[1], alreadyParsed: seenLevels[match[1]]}
+			if !profile.alreadyParsed {
+				seenLevels[match[1]] = true
+				profiles = append(profiles, profile)
+			}
+			inSSETurbo = false
+			continue
+		}
+		if profile == nil || profile.alreadyParsed {
+			continue
+		}
+		if strings.HasPrefix(line, "turbo-ratio-limits-") {
+			inSSETurbo = line == "turbo-ratio-limits-sse"
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		switch key {
+		case "cpu-count":
+			profile.cores = value
+		case "base-frequency(MHz)":
+			profile.baseFreq = value
+		case "thermal-design-power(W)":
+			profile.tdp = value
+		case "max-turbo-frequency(MHz)":
+			if inSSETurbo {
+				profile.turboFreqs = append(profile.turboFreqs, value)
+			}
+		}
+	}
+	if len(profiles) == 0 {
+		return
+	}
+	mhzToGhz := func(mhz string) string {
+		freq, err := strconv.ParseFloat(mhz, 64)
+		if err != nil || freq == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%.1fGHz", freq/1000)
+	}
+	valueNames = []string{"Level", "Active", "Cores", "Base Frequency", "Max Turbo Frequency", "All-core Turbo Frequency", "TDP"}
+	for _, profile := range profiles {
+		active := "No"
+		if profile.level == currentLevel {
+			active = "Yes"
+		}
+		var maxTurbo, allCoreTurbo string
+		if len(profile.turboFreqs) > 0 {
+			maxTurbo = mhzToGhz(profile.turboFreqs[0])
+			allCoreTurbo = mhzToGhz(profile.turboFreqs[len(profile.turboFreqs)-1])
+		}
+		tdp := profile.tdp
+		if tdp != "" {
+			tdp += "W"
+		}
+		values = append(values, []string{profile.level, active, profile.cores, mhzToGhz(profile.baseFreq), maxTurbo, allCoreTurbo, tdp})
+	}
+	return
+}
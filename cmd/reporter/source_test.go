@@ -0,0 +1,654 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// newTestSource returns a source with the given command outputs, by command label
+func newTestSource(outputs map[string]string) (source *Source) {
+	source = newSource("test.raw.json")
+	source.Hostname = "test"
+	for label, stdout := range outputs {
+		source.ParsedData[label] = CommandData{Label: label, Stdout: stdout, ExitStatus: "0"}
+	}
+	return
+}
+
+const dmidecodeProcessorAMD = `Handle 0x0004, DMI type 4, 48 bytes
+Processor Information
+	Socket Designation: P0
+	Type: Central Processor
+	Manufacturer: Advanced Micro Devices, Inc.
+
+`
+
+const dmidecodeProcessorIntel = `Handle 0x0004, DMI type 4, 48 bytes
+Processor Information
+	Socket Designation: CPU0
+	Type: Central Processor
+	Manufacturer: Intel(R) Corporation
+
+`
+
+func TestGetMLCBandwidthScale(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		dmidecode string
+		mlc       string
+		scale     float64
+	}{
+		{"MB/sec units", dmidecodeProcessorAMD, "Measuring Memory Bandwidths between nodes within system\nBandwidths are in MB/sec (1 MB/sec = 1,000,000 Bytes/sec)\n\t\tNuma node\nNuma node\t     0\t     1\n       0\t  120.5\t   60.1\n", 1},
+		{"GB/s units", dmidecodeProcessorIntel, "Bandwidths are in GB/s\n       0\t  120.5\t   60.1\n", 1000},
+		{"AMD, no units, small values", dmidecodeProcessorAMD, "       0\t  120.5\t   60.1\n       1\t   61.0\t  121.2\n", 1000},
+		{"AMD, no units, large values", dmidecodeProcessorAMD, "       0\t120512.3\t60123.4\n", 1},
+		{"Intel, no units, small values", dmidecodeProcessorIntel, "       0\t  120.5\t   60.1\n", 1},
+		{"AMD, no values", dmidecodeProcessorAMD, "", 1},
+	} {
+		source := newTestSource(map[string]string{"dmidecode": tc.dmidecode, "Memory MLC Bandwidth": tc.mlc})
+		if scale := source.getMLCBandwidthScale("Memory MLC Bandwidth"); scale != tc.scale {
+			t.Errorf("%s: expected %f, got %f", tc.name, tc.scale, scale)
+		}
+	}
+}
+
+func TestCommandDataOptionalFields(t *testing.T) {
+	// raw data from older collectors has no duration or skipped reason, don't add them when re-emitted
+	b, err := json.Marshal(CommandData{Label: "date", Command: "date", ExitStatus: "0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "duration_ms") || strings.Contains(string(b), "skipped") {
+		t.Errorf("unexpected optional fields in %s", string(b))
+	}
+	b, err = json.Marshal(CommandData{Label: "date", DurationMS: "12"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"duration_ms":"12"`) {
+		t.Errorf("expected duration in %s", string(b))
+	}
+}
+
+const perfC2CReport = `=================================================
+            Trace Event Information
+=================================================
+  Total records                     :     329219
+  Load HITM                         :       9019
+
+=================================================
+           Shared Data Cache Line Table
+=================================================
+#
+#        ----------- Cacheline ----------      Tot  ------- Load Hitm -------    Total    Total    Total  ---- Stores ----  ----- Core Load Hit -----  - LLC Load Hit --  - RMT Load Hit --  --- Load Dram ----
+# Index             Address  Node  PA cnt     Hitm    Total  LclHitm  RmtHitm  records    Loads   Stores    L1Hit   L1Miss       FB       L1       L2    LclHit  LclHitm    RmtHit  RmtHitm       Lcl       Rmt
+# .....  ..................  ....  ......  .......  .......  .......  .......  .......  .......  .......  .......  .......  .......  .......  .......  ........  .......  ........  .......  ........  ........
+#
+      0      0xffff8b4e8a2e0000     0    2563   21.65%     1953     1951        2     4371     2904     1467     1467        0        0      951        0         0     1951         0        2         0         0
+      1      0x556e2b3a9c40         1     310    4.12%      372      372        0      512      490       22       22        0       10      108        0         0      372         0        0         0         0
+      2      0x556e2b3a9d00         1      21    0.50%       45       45        0       60       58        2        2        0        0       13        0         0       45         0        0         0         0
+
+=================================================
+      Shared Cache Line Distribution Pareto
+=================================================
+#
+#        ----- HITM -----  -- Store Refs --  ------- CL --------                      ---------- cycles ----------    Total       cpu                                  Shared
+#   Num  RmtHitm  LclHitm   L1 Hit  L1 Miss    Off  Node  PA cnt        Code address  rmt hitm  lcl hitm      load  records       cnt               Symbol                Object
+# .....  .......  .......  .......  .......  .....  ....  ......  ..................  ........  ........  ........  .......  ........  ...................  ....................
+#
+  -------------------------------------------------------------------------------
+      0        2     1951     1467        0      0xffff8b4e8a2e0000
+  -------------------------------------------------------------------------------
+           0.00%   65.56%    0.00%    0.00%    0x0     0       1  0xffffffff9a0e9f8e         0       179       187     1320       36  [k] __update_load_avg_cfs_rq  [kernel.kallsyms]  update_load_avg+124
+         100.00%   20.14%    0.00%    0.00%    0x0     0       1  0xffffffff9a0eb1a2       283       160       165      410       32  [k] update_blocked_averages    [kernel.kallsyms]  update_blocked_averages+402
+           0.00%    9.30%   85.00%    0.00%    0x0     0       1  0xffffffff9a0e9f70         0       173       180      200       20  [k] __update_load_avg_cfs_rq  [kernel.kallsyms]  update_load_avg+96
+  -------------------------------------------------------------------------------
+      1        0      372       22        0          0x556e2b3a9c40
+  -------------------------------------------------------------------------------
+           0.00%  100.00%  100.00%    0.00%   0x10     1       1      0x556e29a1b2c4         0       210       220      394       12  [.] worker_loop                  app                  worker.c:88
+  -------------------------------------------------------------------------------
+      2        0       45        2        0          0x556e2b3a9d00
+  -------------------------------------------------------------------------------
+           0.00%  100.00%  100.00%    0.00%   0x20     1       1      0x556e29a1b400         0       190       201       47        4  [.] stats_update                 app                  stats.c:21
+`
+
+func TestGetCacheContention(t *testing.T) {
+	source := newTestSource(map[string]string{"perf c2c": perfC2CReport})
+	expected := [][]string{
+		{"0", "0xffff8b4e8a2e0000", "0", "21.65%", "1953", "__update_load_avg_cfs_rq, update_blocked_averages"},
+		{"1", "0x556e2b3a9c40", "1", "4.12%", "372", "worker_loop"},
+		{"2", "0x556e2b3a9d00", "1", "0.50%", "45", "stats_update"},
+	}
+	lines := source.getCacheContention(10)
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d: %v", len(expected), len(lines), lines)
+	}
+	for i := range expected {
+		if strings.Join(lines[i], "|") != strings.Join(expected[i], "|") {
+			t.Errorf("line %d: expected %v, got %v", i, expected[i], lines[i])
+		}
+	}
+	// limited number of cache lines, symbols of cache lines that weren't kept are ignored
+	if lines = source.getCacheContention(1); len(lines) != 1 || lines[0][5] != expected[0][5] {
+		t.Errorf("expected only the first cache line, got %v", lines)
+	}
+	// perf c2c not run
+	if lines = newTestSource(map[string]string{}).getCacheContention(10); len(lines) != 0 {
+		t.Errorf("expected no lines, got %v", lines)
+	}
+}
+
+const nvmeList = `Node                  SN                   Model                                    Namespace Usage                      Format           FW Rev
+--------------------- -------------------- ---------------------------------------- --------- -------------------------- ---------------- --------
+/dev/nvme0n1          S4EWNX0R123456       Samsung SSD 970 EVO Plus 1TB             1         120.45  GB /   1.00  TB    512   B +  0 B   2B2QEXM7
+/dev/nvme1n1          PHLJ912345671P0FGN   INTEL SSDPE2KX010T8                      1         1.00  TB /   1.00  TB      512   B +  0 B   VDV10131
+`
+
+const nvmeSmartLog = `NVME DEVICE: /dev/nvme0
+Smart Log for NVME device:nvme0 namespace-id:ffffffff
+critical_warning			: 0
+temperature				: 38 C (311 Kelvin)
+available_spare				: 100%
+available_spare_threshold		: 10%
+percentage_used				: 2%
+endurance group critical warning summary: 0
+data_units_read				: 12,345,678
+data_units_written			: 9,876,543
+host_read_commands			: 123,456,789
+NVME DEVICE: /dev/nvme1
+Smart Log for NVME device:nvme1 namespace-id:ffffffff
+critical_warning			: 0x5
+temperature				: 71 °C (344 K)
+available_spare				: 8%
+available_spare_threshold		: 10%
+percentage_used				: 95%
+data_units_read				: 1,234
+data_units_written			: 5,678
+`
+
+func TestGetNVMeModels(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		nvmeList string
+		models   map[string]string
+	}{
+		{"two controllers", nvmeList, map[string]string{"nvme0": "Samsung SSD 970 EVO Plus 1TB", "nvme1": "INTEL SSDPE2KX010T8"}},
+		{"nvme not installed", "", map[string]string{}},
+		{"no devices", strings.Join(strings.Split(nvmeList, "\n")[:2], "\n"), map[string]string{}},
+	} {
+		models := newTestSource(map[string]string{"nvme list": tc.nvmeList}).getNVMeModels()
+		if len(models) != len(tc.models) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.models, models)
+			continue
+		}
+		for device, model := range tc.models {
+			if models[device] != model {
+				t.Errorf("%s: %s: expected %q, got %q", tc.name, device, model, models[device])
+			}
+		}
+	}
+}
+
+func TestGetNVMeCriticalWarnings(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		warnings string
+	}{
+		{"0", "None"},
+		{"0x0", "None"},
+		{"0x1", "Available Spare Below Threshold"},
+		{"0x5", "Available Spare Below Threshold, Reliability Degraded"},
+		{"8", "Read Only"},
+		{"unknown", "unknown"},
+	} {
+		if warnings := getNVMeCriticalWarnings(tc.value); warnings != tc.warnings {
+			t.Errorf("%s: expected %q, got %q", tc.value, tc.warnings, warnings)
+		}
+	}
+}
+
+func TestGetNVMeHealth(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		outputs map[string]string
+		health  [][]string
+	}{
+		{
+			"healthy and failing devices",
+			map[string]string{"nvme list": nvmeList, "nvme smart-log": nvmeSmartLog},
+			[][]string{
+				{"nvme0", "Samsung SSD 970 EVO Plus 1TB", "None", "2%", "38 C", "12,345,678", "9,876,543", "OK"},
+				{"nvme1", "INTEL SSDPE2KX010T8", "Available Spare Below Threshold, Reliability Degraded", "95%", "71 °C", "1,234", "5,678", "Warning"},
+			},
+		},
+		{
+			"worn device without a model",
+			map[string]string{"nvme smart-log": "NVME DEVICE: /dev/nvme2\ncritical_warning\t\t\t: 0\npercentage_used\t\t\t\t: 91%\n"},
+			[][]string{{"nvme2", "", "None", "91%", "", "", "", "Warning"}},
+		},
+		{
+			"nvme not installed",
+			map[string]string{"nvme smart-log": ""},
+			nil,
+		},
+	} {
+		health := newTestSource(tc.outputs).getNVMeHealth()
+		if len(health) != len(tc.health) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.health, health)
+			continue
+		}
+		for i := range tc.health {
+			if strings.Join(health[i], "|") != strings.Join(tc.health[i], "|") {
+				t.Errorf("%s: expected %v, got %v", tc.name, tc.health[i], health[i])
+			}
+		}
+	}
+}
+
+const lshwNetwork = `Bus info          Device          Class          Description
+===================================================================
+pci@0000:00:00.0                  bridge         Sky Lake-E DMI3 Registers [8086:2020]
+pci@0000:18:00.0  ens785f0        network        Ethernet Controller E810-C for QSFP [8086:1592]
+pci@0000:3b:00.0  enp59s0f0np0    network        MT2892 Family [ConnectX-6 Dx] [15B3:101D]
+usb@1:1.3         enp0s20f0u1u3   network        Ethernet interface
+`
+
+const ethtoolStats = `INTERFACE: ens785f0
+NIC statistics:
+     rx_unicast: 123456
+     tx_unicast: 654321
+     rx_packets: 1000
+     tx_packets: 2000
+     rx_dropped: 0
+     tx_dropped: 0
+     rx_missed_errors: 0
+     tx_queue_0_packets: 1200
+     tx_queue_0_bytes: 120000
+     tx_queue_1_packets: 800
+     rx_queue_0_packets: 600
+     rx_queue_0_bytes: 60000
+     rx_queue_1_packets: 400
+INTERFACE: enp59s0f0np0
+NIC statistics:
+     rx_packets: 5000
+     tx_packets: 4000
+     rx_dropped: 3
+     tx_dropped: 1
+     rx_missed: 2
+     rx0_packets: 2500
+     rx0_drops: 3
+     rx1_packets: 2500
+     tx0_packets: 4000
+     tx0_dropped: 1
+INTERFACE: enp0s20f0u1u3
+no stats available
+`
+
+func TestGetNICQueueStats(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		outputs map[string]string
+		stats   [][]string
+	}{
+		{
+			"ice and mlx5",
+			map[string]string{"lshw": lshwNetwork, "ethtool -S": ethtoolStats},
+			[][]string{
+				{"ens785f0", "0", "600", "", "1200", "", ""},
+				{"ens785f0", "1", "400", "", "800", "", ""},
+				{"ens785f0", "Total", "1000", "0", "2000", "0", "OK"},
+				{"enp59s0f0np0", "0", "2500", "3", "4000", "1", ""},
+				{"enp59s0f0np0", "1", "2500", "", "", "", ""},
+				{"enp59s0f0np0", "Total", "5000", "5", "4000", "1", "Dropping"},
+				{"enp0s20f0u1u3", "Total", "0", "0", "0", "0", "OK"},
+			},
+		},
+		{
+			"interface not reported by lshw",
+			map[string]string{"lshw": "", "ethtool -S": ethtoolStats},
+			nil,
+		},
+		{
+			"ethtool not collected",
+			map[string]string{"lshw": lshwNetwork},
+			nil,
+		},
+	} {
+		stats := newTestSource(tc.outputs).getNICQueueStats()
+		if len(stats) != len(tc.stats) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.stats, stats)
+			continue
+		}
+		for i := range tc.stats {
+			if strings.Join(stats[i], "|") != strings.Join(tc.stats[i], "|") {
+				t.Errorf("%s: expected %v, got %v", tc.name, tc.stats[i], stats[i])
+			}
+		}
+	}
+}
+
+const dockerContainers = `RUNTIME: docker
+4c01db0b339cb3e1f2a8d07a5b1c8e5d7f6a9b0c1d2e3f4a5b6c7d8e9f0a1b2c|nginx:latest|running|web
+9f8e7d6c5b4a|redis:7.2|exited|cache,cache-alias
+`
+
+const crictlContainers = `RUNTIME: crictl
+{
+  "containers": [
+    {
+      "id": "1f73f2d81bf98ffb1a9d3e2c4b5a6978a1b2c3d4e5f60718293a4b5c6d7e8f90",
+      "podSandboxId": "0f5a3c1b2d4e",
+      "metadata": {
+        "name": "coredns",
+        "attempt": 0
+      },
+      "image": {
+        "image": "registry.k8s.io/coredns/coredns:v1.10.1",
+        "annotations": {}
+      },
+      "imageRef": "sha256:ead0a4a53df89fd173874b46093b6e62d8c72967bbf606d672c9e8c9b601a4fc",
+      "state": "CONTAINER_RUNNING",
+      "createdAt": "1700000000000000000",
+      "labels": {},
+      "annotations": {}
+    },
+    {
+      "id": "2a84c3e9",
+      "metadata": {
+        "name": "kube-proxy"
+      },
+      "image": {
+        "image": "registry.k8s.io/kube-proxy:v1.28.2"
+      },
+      "state": "CONTAINER_EXITED"
+    }
+  ]
+}
+`
+
+func TestGetContainers(t *testing.T) {
+	dockerRows := [][]string{
+		{"docker", "4c01db0b339c", "nginx:latest", "running", "web"},
+		{"docker", "9f8e7d6c5b4a", "redis:7.2", "exited", "cache,cache-alias"},
+	}
+	crictlRows := [][]string{
+		{"crictl", "1f73f2d81bf9", "registry.k8s.io/coredns/coredns:v1.10.1", "running", "coredns"},
+		{"crictl", "2a84c3e9", "registry.k8s.io/kube-proxy:v1.28.2", "exited", "kube-proxy"},
+	}
+	for _, tc := range []struct {
+		name       string
+		output     string
+		containers [][]string
+	}{
+		{"docker", dockerContainers, dockerRows},
+		{"crictl", crictlContainers, crictlRows},
+		{"docker and crictl", dockerContainers + crictlContainers, append(append([][]string{}, dockerRows...), crictlRows...)},
+		{"runtime without containers", "RUNTIME: docker\nRUNTIME: crictl\n", nil},
+		{"invalid crictl output", "RUNTIME: crictl\nFATA[0000] connect: connection refused\n", nil},
+		{"no runtime", "", nil},
+	} {
+		containers := newTestSource(map[string]string{"containers": tc.output}).getContainers()
+		if len(containers) != len(tc.containers) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.containers, containers)
+			continue
+		}
+		for i := range tc.containers {
+			if strings.Join(containers[i], "|") != strings.Join(tc.containers[i], "|") {
+				t.Errorf("%s: expected %v, got %v", tc.name, tc.containers[i], containers[i])
+			}
+		}
+	}
+}
+
+const numastat = `                           node0           node1
+numa_hit              1234567890       987654321
+numa_miss                   1000       250000000
+numa_foreign           250000000            1000
+interleave_hit             45678           45679
+local_node            1234500000       987600000
+other_node                 68890       250054321
+`
+
+const numastatMemory = `
+Per-node system memory usage (in MBs):
+                          Node 0          Node 1           Total
+                 --------------- --------------- ---------------
+MemTotal                95345.12        96761.37       192106.49
+MemFree                 80123.45        70000.01       150123.46
+MemUsed                 15221.67        26761.36        41983.03
+HugePages_Total             0.00            0.00            0.00
+`
+
+func TestGetNUMAStats(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		outputs map[string]string
+		stats   [][]string
+	}{
+		{
+			"two nodes",
+			map[string]string{"numastat": numastat, "numastat -m": numastatMemory},
+			[][]string{
+				{"0", "1234567890", "1000", "68890", "0.00", "95345 MB", "80123 MB", "15222 MB"},
+				{"1", "987654321", "250000000", "250054321", "20.20", "96761 MB", "70000 MB", "26761 MB"},
+			},
+		},
+		{
+			"no memory usage",
+			map[string]string{"numastat": numastat},
+			[][]string{
+				{"0", "1234567890", "1000", "68890", "0.00", "", "", ""},
+				{"1", "987654321", "250000000", "250054321", "20.20", "", "", ""},
+			},
+		},
+		{
+			"numastat not installed",
+			map[string]string{"numastat": "", "numastat -m": ""},
+			nil,
+		},
+	} {
+		stats := newTestSource(tc.outputs).getNUMAStats()
+		if len(stats) != len(tc.stats) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.stats, stats)
+			continue
+		}
+		for i := range tc.stats {
+			if strings.Join(stats[i], "|") != strings.Join(tc.stats[i], "|") {
+				t.Errorf("%s: expected %v, got %v", tc.name, tc.stats[i], stats[i])
+			}
+		}
+	}
+}
+
+const nvidiaSMI = `<?xml version="1.0" ?>
+<!DOCTYPE nvidia_smi_log SYSTEM "nvsmi_device_v12.dtd">
+<nvidia_smi_log>
+	<timestamp>Tue Oct 17 10:12:41 2023</timestamp>
+	<driver_version>535.104.05</driver_version>
+	<cuda_version>12.2</cuda_version>
+	<attached_gpus>2</attached_gpus>
+	<gpu id="00000000:3B:00.0">
+		<product_name>NVIDIA A100-PCIE-40GB</product_name>
+		<product_brand>NVIDIA</product_brand>
+		<vbios_version>92.00.25.00.08</vbios_version>
+		<fb_memory_usage>
+			<total>40960 MiB</total>
+			<reserved>635 MiB</reserved>
+			<used>4 MiB</used>
+			<free>40321 MiB</free>
+		</fb_memory_usage>
+	</gpu>
+	<gpu id="00000000:AF:00.0">
+		<product_name>NVIDIA L4</product_name>
+		<vbios_version>95.04.29.00.06</vbios_version>
+		<fb_memory_usage>
+			<total>23034 MiB</total>
+		</fb_memory_usage>
+	</gpu>
+</nvidia_smi_log>
+`
+
+const xpuSMI = `{
+    "device_id": 0,
+    "device_name": "Intel(R) Data Center GPU Flex 170",
+    "device_type": "GPU",
+    "driver_version": "I915_23.26.21_PSB_230730.16",
+    "gfx_firmware_version": "DG02_1.3267",
+    "memory_physical_size_byte": "14193524736",
+    "pci_bdf_address": "0000:4D:00.0",
+    "pci_device_id": "0x56c0"
+}
+{
+    "device_id": 1,
+    "device_name": "Intel(R) Data Center GPU Flex 170",
+    "driver_version": "I915_23.26.21_PSB_230730.16",
+    "gfx_firmware_version": "DG02_1.3267",
+    "memory_physical_size_byte": "14193524736",
+    "pci_bdf_address": "0000:9a:00.0"
+}
+`
+
+const clinfoRaw = `[INTEL/*]  CL_PLATFORM_NAME                          Intel(R) OpenCL Graphics
+[INTEL/*]  CL_PLATFORM_VENDOR                        Intel(R) Corporation
+[INTEL/*]  CL_PLATFORM_NUM_DEVICES                   2
+[INTEL/0]  CL_DEVICE_NAME                            Intel(R) Data Center GPU Flex 170
+[INTEL/0]  CL_DEVICE_VENDOR                          Intel(R) Corporation
+[INTEL/0]  CL_DRIVER_VERSION                         23.26.26690.36
+[INTEL/0]  CL_DEVICE_TYPE                            CL_DEVICE_TYPE_GPU
+[INTEL/0]  CL_DEVICE_PCI_BUS_INFO_KHR                PCI-E, 0000:4d:00.0
+[INTEL/0]  CL_DEVICE_GLOBAL_MEM_SIZE                 14193524736
+[INTEL/1]  CL_DEVICE_NAME                            Intel(R) Arc(TM) A770 Graphics
+[INTEL/1]  CL_DEVICE_VENDOR                          Intel(R) Corporation
+[INTEL/1]  CL_DRIVER_VERSION                         23.26.26690.36
+[INTEL/1]  CL_DEVICE_TYPE                            CL_DEVICE_TYPE_GPU
+[INTEL/1]  CL_DEVICE_PCI_BUS_INFO_KHR                PCI-E, 0000:03:00.0
+[INTEL/1]  CL_DEVICE_GLOBAL_MEM_SIZE                 16225243136
+[POCL/0]   CL_DEVICE_NAME                            cpu-sapphirerapids-Intel(R) Xeon(R) Platinum 8480+
+[POCL/0]   CL_DEVICE_TYPE                            CL_DEVICE_TYPE_CPU
+`
+
+func TestGetGPUDetails(t *testing.T) {
+	format := func(gpus []GPUDetails) (rows []string) {
+		for _, gpu := range gpus {
+			rows = append(rows, strings.Join([]string{gpu.Manufacturer, gpu.Model, gpu.PCIAddress, gpu.Driver, gpu.Firmware, gpu.Memory}, "|"))
+		}
+		return
+	}
+	for _, tc := range []struct {
+		name     string
+		outputs  map[string]string
+		getter   func(*Source) []GPUDetails
+		expected []string
+	}{
+		{
+			"nvidia-smi",
+			map[string]string{"nvidia-smi": nvidiaSMI},
+			(*Source).getNvidiaGPUDetails,
+			[]string{
+				"NVIDIA|NVIDIA A100-PCIE-40GB|0000:3b:00.0|535.104.05|92.00.25.00.08|40960 MiB",
+				"NVIDIA|NVIDIA L4|0000:af:00.0|535.104.05|95.04.29.00.06|23034 MiB",
+			},
+		},
+		{
+			"nvidia-smi not installed",
+			map[string]string{"nvidia-smi": ""},
+			(*Source).getNvidiaGPUDetails,
+			nil,
+		},
+		{
+			"nvidia-smi error",
+			map[string]string{"nvidia-smi": "NVIDIA-SMI has failed because it couldn't communicate with the NVIDIA driver."},
+			(*Source).getNvidiaGPUDetails,
+			nil,
+		},
+		{
+			"xpu-smi",
+			map[string]string{"xpu-smi": xpuSMI},
+			(*Source).getXPUGPUDetails,
+			[]string{
+				"Intel|Intel(R) Data Center GPU Flex 170|0000:4d:00.0|I915_23.26.21_PSB_230730.16|DG02_1.3267|13536 MiB",
+				"Intel|Intel(R) Data Center GPU Flex 170|0000:9a:00.0|I915_23.26.21_PSB_230730.16|DG02_1.3267|13536 MiB",
+			},
+		},
+		{
+			"xpu-smi not installed",
+			map[string]string{"xpu-smi": ""},
+			(*Source).getXPUGPUDetails,
+			nil,
+		},
+		{
+			"clinfo",
+			map[string]string{"clinfo": clinfoRaw},
+			(*Source).getOpenCLGPUDetails,
+			[]string{
+				"Intel|Intel(R) Data Center GPU Flex 170|0000:4d:00.0|23.26.26690.36||13536 MiB",
+				"Intel|Intel(R) Arc(TM) A770 Graphics|0000:03:00.0|23.26.26690.36||15473 MiB",
+			},
+		},
+		{
+			"clinfo without GPUs",
+			map[string]string{"clinfo": "Number of platforms                               0\n"},
+			(*Source).getOpenCLGPUDetails,
+			nil,
+		},
+		{
+			// clinfo's GPU at 0000:4d:00.0 is reported by xpu-smi
+			"all tools",
+			map[string]string{"nvidia-smi": nvidiaSMI, "xpu-smi": xpuSMI, "clinfo": clinfoRaw},
+			(*Source).getGPUDetails,
+			[]string{
+				"NVIDIA|NVIDIA A100-PCIE-40GB|0000:3b:00.0|535.104.05|92.00.25.00.08|40960 MiB",
+				"NVIDIA|NVIDIA L4|0000:af:00.0|535.104.05|95.04.29.00.06|23034 MiB",
+				"Intel|Intel(R) Data Center GPU Flex 170|0000:4d:00.0|I915_23.26.21_PSB_230730.16|DG02_1.3267|13536 MiB",
+				"Intel|Intel(R) Data Center GPU Flex 170|0000:9a:00.0|I915_23.26.21_PSB_230730.16|DG02_1.3267|13536 MiB",
+				"Intel|Intel(R) Arc(TM) A770 Graphics|0000:03:00.0|23.26.26690.36||15473 MiB",
+			},
+		},
+	} {
+		gpus := format(tc.getter(newTestSource(tc.outputs)))
+		if strings.Join(gpus, "\n") != strings.Join(tc.expected, "\n") {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, gpus)
+		}
+	}
+}
+
+func TestGetHWP(t *testing.T) {
+	for _, tc := range []struct {
+		output string
+		hwp    string
+	}{
+		// msrread -f 0:0 0x770
+		{"1", "Enabled"},
+		{"0", "Disabled"},
+		// the full register, e.g., msrread 0x770
+		{"0000000000000001", "Enabled"},
+		{"0000000000000000", "Disabled"},
+		// msrread failed or the command wasn't collected
+		{"", ""},
+	} {
+		if hwp := newTestSource(map[string]string{"rdmsr 0x770": tc.output}).getHWP(); hwp != tc.hwp {
+			t.Errorf("%q: expected %q, got %q", tc.output, tc.hwp, hwp)
+		}
+	}
+}
+
+func TestGetEnergyPerformancePreference(t *testing.T) {
+	for _, tc := range []struct {
+		output string
+		epp    string
+	}{
+		{"     96 balance_performance\n", "balance_performance"},
+		{"     94 balance_performance\n      2 power\n", "balance_performance (94), power (2)"},
+		// no cpufreq driver
+		{"", ""},
+	} {
+		if epp := newTestSource(map[string]string{"energy_performance_preference": tc.output}).getEnergyPerformancePreference(); epp != tc.epp {
+			t.Errorf("%q: expected %q, got %q", tc.output, tc.epp, epp)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+/* validation of input files against the raw data JSON schema, resources/raw.schema.json */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// jsonSchema is a JSON schema, or sub-schema, as decoded by encoding/json
+type jsonSchema map[string]interface{}
+
+func loadRawSchema() (schema jsonSchema, err error) {
+	schemaBytes, err := resources.ReadFile("resources/raw.schema.json")
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(schemaBytes, &schema)
+	return
+}
+
+// validateInputFile checks an input file, JSON or NDJSON and optionally gzip compressed, against
+// the raw data schema and returns a description of each structural problem found
+func validateInputFile(path string) (problems []string, err error) {
+	inputBytes, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	if strings.HasSuffix(path, ".gz") || bytes.HasPrefix(inputBytes, []byte{0x1f, 0x8b}) {
+		if inputBytes, err = gunzip(inputBytes); err != nil {
+			err = fmt.Errorf("failed to decompress %s: %v", path, err)
+			return
+		}
+	}
+	schema, err := loadRawSchema()
+	if err != nil {
+		return
+	}
+	return validateInput(inputBytes, schema), nil
+}
+
+// validateInput validates the JSON, or NDJSON, input against the schema
+func validateInput(inputBytes []byte, schema jsonSchema) (problems []string) {
+	// NDJSON input starts with a header line that includes the format version
+	firstLine, _, _ := bytes.Cut(inputBytes, []byte("\n"))
+	var header ndjsonHeader
+	if json.Unmarshal(firstLine, &header) == nil && header.Version > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(inputBytes))
+		decoder.UseNumber()
+		for lineNo := 1; decoder.More(); lineNo++ {
+			var value interface{}
+			if err := decoder.Decode(&value); err != nil {
+				problems = append(problems, fmt.Sprintf("line %d: %v", lineNo, err))
+				return
+			}
+			def := "#/$defs/command"
+			if lineNo == 1 {
+				def = "#/$defs/ndjson_header"
+			}
+			problems = append(problems, validateValue(value, jsonSchema{"$ref": def}, schema, fmt.Sprintf("line %d", lineNo))...)
+		}
+		return
+	}
+	decoder := json.NewDecoder(bytes.NewReader(inputBytes))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		problems = append(problems, fmt.Sprintf("$: %v", err))
+		return
+	}
+	return validateValue(value, schema, schema, "$")
+}
+
+// validateValue validates a decoded JSON value against the subset of JSON schema keywords used
+// by the raw data schema, root is the schema that "$ref"s are resolved in, path locates the value
+func validateValue(value interface{}, schema jsonSchema, root jsonSchema, path string) (problems []string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		def, ok := resolveSchemaRef(root, ref)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: unresolved schema reference %s", path, ref))
+			return
+		}
+		return validateValue(value, def, root, path)
+	}
+	if schemaType, ok := schema["type"].(string); ok && jsonType(value) != schemaType {
+		if !(schemaType == "number" && jsonType(value) == "integer") {
+			problems = append(problems, fmt.Sprintf("%s: expected %s, found %s", path, schemaType, jsonType(value)))
+			return
+		}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !slices.Contains(enum, value) {
+		problems = append(problems, fmt.Sprintf("%s: %v is not one of %v", path, value, enum))
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		problems = append(problems, validateObject(v, schema, root, path)...)
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				problems = append(problems, validateValue(item, items, root, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case string:
+		problems = append(problems, validateString(v, schema, path)...)
+	case json.Number:
+		if minimum, ok := schema["minimum"].(float64); ok {
+			if f, err := v.Float64(); err == nil && f < minimum {
+				problems = append(problems, fmt.Sprintf("%s: %s is less than %v", path, v, minimum))
+			}
+		}
+	}
+	return
+}
+
+func validateObject(object map[string]interface{}, schema jsonSchema, root jsonSchema, path string) (problems []string) {
+	if minProperties, ok := schema["minProperties"].(float64); ok && float64(len(object)) < minProperties {
+		problems = append(problems, fmt.Sprintf("%s: expected at least %v key(s), found %d", path, minProperties, len(object)))
+	}
+	if maxProperties, ok := schema["maxProperties"].(float64); ok && float64(len(object)) > maxProperties {
+		problems = append(problems, fmt.Sprintf("%s: expected at most %v key(s), found %d", path, maxProperties, len(object)))
+	}
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing required key \"%s\"", path, name))
+			}
+		}
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	additionalProperties, _ := schema["additionalProperties"].(map[string]interface{})
+	propertyNames, _ := schema["propertyNames"].(map[string]interface{})
+	// sort the keys so that the problems are reported in a consistent order
+	var keys []string
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		keyPath := fmt.Sprintf("%s[\"%s\"]", path, key)
+		if propertyNames != nil {
+			problems = append(problems, validateString(key, propertyNames, keyPath+" key")...)
+		}
+		if property, ok := properties[key].(map[string]interface{}); ok {
+			problems = append(problems, validateValue(object[key], property, root, keyPath)...)
+		} else if additionalProperties != nil {
+			problems = append(problems, validateValue(object[key], additionalProperties, root, keyPath)...)
+		}
+	}
+	return
+}
+
+func validateString(s string, schema jsonSchema, path string) (problems []string) {
+	if minLength, ok := schema["minLength"].(float64); ok && float64(utf8.RuneCountInString(s)) < minLength {
+		problems = append(problems, fmt.Sprintf("%s: expected at least %v character(s)", path, minLength))
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: invalid schema pattern %s", path, pattern))
+		} else if !re.MatchString(s) {
+			problems = append(problems, fmt.Sprintf("%s: \"%s\" does not match %s", path, s, pattern))
+		}
+	}
+	return
+}
+
+// resolveSchemaRef resolves a local reference, e.g., #/$defs/command
+func resolveSchemaRef(root jsonSchema, ref string) (schema jsonSchema, ok bool) {
+	if !strings.HasPrefix(ref, "#/") {
+		return
+	}
+	var node interface{} = map[string]interface{}(root)
+	for _, name := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		object, isObject := node.(map[string]interface{})
+		if !isObject {
+			return
+		}
+		if node, ok = object[name]; !ok {
+			return
+		}
+	}
+	object, ok := node.(map[string]interface{})
+	return jsonSchema(object), ok
+}
+
+// jsonType returns the JSON schema type name of a value decoded with json.Decoder.UseNumber
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
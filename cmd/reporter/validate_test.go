@@ -0,0 +1,36 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateInput(t *testing.T) {
+	schema, err := loadRawSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	command := `{"label":"date","command":"date","superuser":"false","stdout":"today","stderr":"","exitstatus":"0"}`
+	for in, expected := range map[string][]string{
+		`{"myhost":[` + command + `]}`:                                 nil,
+		`{"ndjson_version":1,"name":"myhost"}` + "\n" + command + "\n": nil,
+		`{}`:                            {`$: expected at least 1 key(s), found 0`},
+		`{"myhost":{}}`:                 {`$["myhost"]: expected array, found object`},
+		`{"myhost":[{"label":"date"}]}`: {`$["myhost"][0]: missing required key "command"`},
+		`{"myhost":[` + strings.Replace(command, `"0"`, `0`, 1) + `]}`: {`$["myhost"][0]["exitstatus"]: expected string, found integer`},
+		`{"ndjson_version":1,"name":""}` + "\n" + command + "\n":       {`line 1["name"]: expected at least 1 character(s)`},
+	} {
+		problems := validateInput([]byte(in), schema)
+		if len(expected) == 0 && len(problems) != 0 {
+			t.Errorf("%s: unexpected problems: %v", in, problems)
+			continue
+		}
+		if len(expected) > 0 && (len(problems) == 0 || problems[0] != expected[0]) {
+			t.Errorf("%s: expected %v, got %v", in, expected, problems)
+		}
+	}
+}
@@ -19,7 +19,9 @@ replace github.com/intel/svr-info/internal/commandfile => ./internal/commandfile
 replace github.com/intel/svr-info/internal/util => ./internal/util
 
 require (
+	filippo.io/age v1.0.0
 	github.com/Knetic/govaluate v3.0.0+incompatible
+	github.com/ProtonMail/go-crypto v1.0.0
 	github.com/deckarep/golang-set/v2 v2.6.0
 	github.com/google/go-cmp v0.6.0
 	github.com/hyperjumptech/grule-rule-engine v1.15.0
@@ -40,7 +42,6 @@ require (
 require (
 	dario.cat/mergo v1.0.1 // indirect
 	github.com/Microsoft/go-winio v0.6.2 // indirect
-	github.com/ProtonMail/go-crypto v1.0.0 // indirect
 	github.com/antlr/antlr4/runtime/Go/antlr v1.4.10 // indirect
 	github.com/bmatcuk/doublestar v1.3.4 // indirect
 	github.com/cloudflare/circl v1.4.0 // indirect
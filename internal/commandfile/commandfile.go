@@ -10,18 +10,25 @@ package commandfile
 import "github.com/creasty/defaults"
 
 type Command struct {
-	Label     string `yaml:"label"`
-	Command   string `yaml:"command"`
-	Modprobe  string `yaml:"modprobe"`
-	Superuser bool   `default:"false" yaml:"superuser"`
-	Run       bool   `default:"false" yaml:"run"`
-	Parallel  bool   `default:"false" yaml:"parallel"`
+	Label          string `yaml:"label"`
+	Command        string `yaml:"command"`
+	Modprobe       string `yaml:"modprobe"`
+	Superuser      bool   `default:"false" yaml:"superuser"`
+	Run            bool   `default:"false" yaml:"run"`
+	Parallel       bool   `default:"false" yaml:"parallel"`
+	RunAs          string `yaml:"run_as"`
+	MaxOutputBytes int    `yaml:"max_output_bytes"` // 0 for no limit
 }
 
 type Arguments struct {
-	Name    string `default:"test" yaml:"name"`
-	Binpath string `default:"." yaml:"bin_path"`
-	Timeout int    `default:"300" yaml:"command_timeout"`
+	Name            string `default:"test" yaml:"name"`
+	Binpath         string `default:"." yaml:"bin_path"`
+	Timeout         int    `default:"300" yaml:"command_timeout"`
+	RedfishHost     string `yaml:"redfish_host"`
+	RedfishUser     string `yaml:"redfish_user"`
+	RedfishPass     string `yaml:"redfish_pass"`
+	ProfileDuration int    `default:"60" yaml:"profile_duration"` // seconds, available to the commands as PROFILE_DURATION
+	ProfileSamples  int    `default:"30" yaml:"profile_samples"`  // available to the commands as PROFILE_SAMPLES
 }
 
 type CommandFile struct {
@@ -180,3 +180,41 @@ func TestGetCPU(t *testing.T) {
 		t.Fatal(fmt.Errorf("Found the wrong CPU: %s", cpu.Architecture))
 	}
 }
+
+func TestGetARMCPU(t *testing.T) {
+	cpudb := NewCPUDB()
+	if cpudb == nil {
+		t.Fatal(fmt.Errorf("failed to create CPU database"))
+	}
+	// should fail
+	_, err := cpudb.GetARMCPU("0x41", "0x000")
+	if err == nil {
+		t.Fatal(err)
+	}
+	_, err = cpudb.GetARMCPU("", "")
+	if err == nil {
+		t.Fatal(err)
+	}
+
+	cpu, err := cpudb.GetARMCPU("0x41", "0xd0c") // Neoverse N1
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cpu.Architecture != "Neoverse N1" {
+		t.Fatal(fmt.Errorf("Found the wrong CPU: %s", cpu.Architecture))
+	}
+
+	cpu, err = cpudb.GetARMCPU("0x41", "0xD4F") // Neoverse V2
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cpu.Architecture != "Neoverse V2" {
+		t.Fatal(fmt.Errorf("Found the wrong CPU: %s", cpu.Architecture))
+	}
+
+	// ARM CPUs without a model shouldn't match an empty model
+	_, err = cpudb.GetCPU("", "", "", "", "", "")
+	if err == nil {
+		t.Fatal(err)
+	}
+}
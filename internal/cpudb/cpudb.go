@@ -13,6 +13,7 @@ import (
 	"log"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 )
@@ -25,6 +26,8 @@ type CPU struct {
 	Family       string `yaml:"family"`
 	Model        string `yaml:"model"`
 	Stepping     string `yaml:"stepping"`
+	Implementer  string `yaml:"implementer"` // ARM only
+	Part         string `yaml:"part"`        // ARM only
 	Channels     int    `yaml:"channels"`
 	Threads      int    `yaml:"threads"`
 }
@@ -61,6 +64,10 @@ func NewCPUDB() (CPUdb *CPUDB) {
 //	devices: $ lspci -d 8086:3258 | wc -l
 func (c *CPUDB) GetCPU(family, model, stepping, capid4, sockets, devices string) (cpu CPU, err error) {
 	for _, info := range c.cpus {
+		// an empty model would match any model, e.g., ARM CPUs identified only by implementer and part
+		if info.Model == "" {
+			continue
+		}
 		// if family matches
 		if info.Family == family {
 			var reModel *regexp.Regexp
@@ -95,6 +102,21 @@ func (c *CPUDB) GetCPU(family, model, stepping, capid4, sockets, devices string)
 	return
 }
 
+// GetARMCPU retrieves the CPU structure that matches the provided ARM implementer and part,
+// as found in /proc/cpuinfo, e.g., "CPU implementer : 0x41" and "CPU part : 0xd0c"
+func (c *CPUDB) GetARMCPU(implementer, part string) (cpu CPU, err error) {
+	if implementer != "" && part != "" {
+		for _, info := range c.cpus {
+			if strings.EqualFold(info.Implementer, implementer) && strings.EqualFold(info.Part, part) {
+				cpu = info
+				return
+			}
+		}
+	}
+	err = fmt.Errorf("CPU match not found for implementer %s, part %s", implementer, part)
+	return
+}
+
 func (c *CPUDB) getSpecificCPU(family, model, capid4, sockets, devices string) (cpu CPU, err error) {
 	if family == "6" && model == "143" { // SPR
 		cpu, err = c.getSPRCPU(capid4)
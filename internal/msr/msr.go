@@ -12,6 +12,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 type MSR struct {
@@ -97,6 +100,29 @@ func (msr *MSR) getMSRFileNames(core int, packageScope bool) (fileNames []string
 	return
 }
 
+// GetCPUs returns the sorted numbers of the cores that have MSR files
+func (msr *MSR) GetCPUs() (cpus []int, err error) {
+	for _, fileName := range msr.fileNames {
+		var cpu int
+		if cpu, err = cpuFromFileName(fileName); err != nil {
+			return
+		}
+		cpus = append(cpus, cpu)
+	}
+	sort.Ints(cpus)
+	return
+}
+
+// cpuFromFileName parses the core number from an old or new style MSR file name,
+// e.g., /dev/cpu/cpu3/msr or /dev/cpu/3/msr
+func cpuFromFileName(fileName string) (cpu int, err error) {
+	dir := strings.TrimPrefix(filepath.Base(filepath.Dir(fileName)), "cpu")
+	if cpu, err = strconv.Atoi(dir); err != nil {
+		err = fmt.Errorf("failed to parse cpu from msr file name: %s", fileName)
+	}
+	return
+}
+
 func maskUint64(highBit int, lowBit int, val uint64) (v uint64) {
 	bits := highBit - lowBit + 1
 	if bits < 64 {
@@ -146,18 +172,19 @@ func (msr *MSR) write(reg uint64, fileName string, bytes int, val uint64) (err e
 	return
 }
 
-// SetBitRange filters bits for subsequent calls to Read* functions
+// SetBitRange filters bits for subsequent calls to Read* functions, set highBit equal to lowBit
+// to select a single bit
 func (msr *MSR) SetBitRange(highBit int, lowBit int) (err error) {
-	if lowBit >= highBit {
-		err = fmt.Errorf("lowBit must be less than highBit")
+	if lowBit > highBit {
+		err = fmt.Errorf("lowBit must be less than or equal to highBit")
 		return
 	}
-	if lowBit < 0 || lowBit > 62 {
-		err = fmt.Errorf("lowBit must be a value between 0 and 62 (inclusive)")
+	if lowBit < 0 || lowBit > 63 {
+		err = fmt.Errorf("lowBit must be a value between 0 and 63 (inclusive)")
 		return
 	}
-	if highBit < 1 || highBit > 63 {
-		err = fmt.Errorf("highBit must be a value between 1 and 63 (inclusive)")
+	if highBit < 0 || highBit > 63 {
+		err = fmt.Errorf("highBit must be a value between 0 and 63 (inclusive)")
 		return
 	}
 	msr.lowBit = lowBit
@@ -165,6 +192,28 @@ func (msr *MSR) SetBitRange(highBit int, lowBit int) (err error) {
 	return
 }
 
+// ParseBitRange parses a bit range argument, e.g., "15:8", or "22:22" for a single bit, into
+// its high and low bits
+func ParseBitRange(bitrange string) (highBit, lowBit int, err error) {
+	bitrangeOK := false
+	fields := strings.Split(bitrange, ":")
+	if len(fields) == 2 {
+		highBit, err = strconv.Atoi(fields[0])
+		if err == nil && highBit >= 0 && highBit <= 63 {
+			lowBit, err = strconv.Atoi(fields[1])
+			if err == nil && lowBit >= 0 && lowBit <= 63 {
+				if highBit >= lowBit {
+					bitrangeOK = true
+				}
+			}
+		}
+	}
+	if !bitrangeOK {
+		err = fmt.Errorf("failed to parse bit range: %s", bitrange)
+	}
+	return
+}
+
 // ReadAll returns the register value for all cores
 func (msr *MSR) ReadAll(reg uint64) (out []uint64, err error) {
 	fileNames := msr.getMSRFileNames(-1, false)
@@ -183,7 +232,6 @@ func (msr *MSR) ReadAll(reg uint64) (out []uint64, err error) {
 func (msr *MSR) WriteAll(reg uint64, val uint64) (err error) {
 	fileNames := msr.getMSRFileNames(-1, false)
 	for _, fileName := range fileNames {
-		var val uint64
 		err = msr.write(reg, fileName, 8, val)
 		if err != nil {
 			return
@@ -203,6 +251,21 @@ func (msr *MSR) ReadOne(reg uint64, core int) (out uint64, err error) {
 	return
 }
 
+// ReadCPUs returns the register value for each of the specified cores, in the order given
+func (msr *MSR) ReadCPUs(reg uint64, cpus []int) (out []uint64, err error) {
+	for _, cpu := range cpus {
+		var val uint64
+		val, err = msr.ReadOne(reg, cpu)
+		if err != nil {
+			err = fmt.Errorf("failed to read msr %#x on cpu %d: %v", reg, cpu, err)
+			out = nil
+			return
+		}
+		out = append(out, val)
+	}
+	return
+}
+
 // WriteOne writes the given value to the the specified core at the given register offset
 func (msr *MSR) WriteOne(reg uint64, core int, val uint64) (err error) {
 	fileNames := msr.getMSRFileNames(core, false)
@@ -41,6 +41,10 @@ func TestSetBitRange(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	err = msr.SetBitRange(22, 22)
+	if err != nil {
+		t.Fatal(err)
+	}
 }
 
 func TestReadOne(t *testing.T) {
@@ -101,6 +105,26 @@ func TestReadPackage(t *testing.T) {
 	}
 }
 
+func TestReadCPUs(t *testing.T) {
+	msr, err := NewMSR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// this one should work
+	vals, err := msr.ReadCPUs(0x1B0, []int{0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vals) != 1 {
+		t.Fatal("should have one value")
+	}
+	// this one should fail, no such cpu
+	_, err = msr.ReadCPUs(0x1B0, []int{0, 100000})
+	if err == nil {
+		t.Fatal("invalid cpu - should have failed")
+	}
+}
+
 func TestMaskUint64(t *testing.T) {
 	var inputVal uint64 = 0xffffffff
 	outputVal := maskUint64(63, 0, inputVal)
@@ -111,6 +135,10 @@ func TestMaskUint64(t *testing.T) {
 	if outputVal != 0xf {
 		t.Fatal("should match")
 	}
+	outputVal = maskUint64(4, 4, 0x10)
+	if outputVal != 1 {
+		t.Fatal("should match")
+	}
 
 	inputVal = 0x7857000158488
 	outputVal = maskUint64(14, 0, inputVal)
@@ -118,3 +146,55 @@ func TestMaskUint64(t *testing.T) {
 		t.Fatal("should match")
 	}
 }
+
+func TestCPUFromFileName(t *testing.T) {
+	for fileName, expected := range map[string]int{
+		"/dev/cpu/0/msr":     0,
+		"/dev/cpu/cpu3/msr":  3,
+		"/dev/cpu/127/msr":   127,
+		"/dev/cpu/cpu64/msr": 64,
+	} {
+		cpu, err := cpuFromFileName(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cpu != expected {
+			t.Fatalf("%s: expected %d, got %d", fileName, expected, cpu)
+		}
+	}
+	if _, err := cpuFromFileName("/dev/cpu/microcode/msr"); err == nil {
+		t.Fatal("invalid file name - should have failed")
+	}
+}
+
+func TestParseBitRange(t *testing.T) {
+	for _, tc := range []struct {
+		bitrange string
+		highBit  int
+		lowBit   int
+		ok       bool
+	}{
+		{"15:8", 15, 8, true},
+		{"63:0", 63, 0, true},
+		{"22:22", 22, 22, true},
+		{"0:0", 0, 0, true},
+		{"63:63", 63, 63, true},
+		{"8:15", 0, 0, false},
+		{"64:0", 0, 0, false},
+		{"-1:0", 0, 0, false},
+		{"8", 0, 0, false},
+		{"a:b", 0, 0, false},
+		{"", 0, 0, false},
+	} {
+		highBit, lowBit, err := ParseBitRange(tc.bitrange)
+		if !tc.ok {
+			if err == nil {
+				t.Errorf("%q: expected error", tc.bitrange)
+			}
+			continue
+		}
+		if err != nil || highBit != tc.highBit || lowBit != tc.lowBit {
+			t.Errorf("%q: expected %d:%d, got %d:%d, %v", tc.bitrange, tc.highBit, tc.lowBit, highBit, lowBit, err)
+		}
+	}
+}
@@ -2,6 +2,11 @@ module github.com/intel/svr-info/internal/target
 
 go 1.22.0
 
-require github.com/intel/svr-info/internal/util v0.0.0-00010101000000-000000000000
+require (
+	github.com/intel/svr-info/internal/util v0.0.0-00010101000000-000000000000
+	golang.org/x/crypto v0.31.0
+)
+
+require golang.org/x/sys v0.28.0 // indirect
 
 replace github.com/intel/svr-info/internal/util => ../util
@@ -0,0 +1,330 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package target
+
+// native ssh client used for password authentication, i.e., in place of sshpass
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	sshConnectTimeout      = 10 * time.Second // matches ConnectTimeout in getSSHFlags
+	sshServerAliveInterval = 30 * time.Second // matches ServerAliveInterval in getSSHFlags
+	sshServerAliveCountMax = 10               // matches ServerAliveCountMax in getSSHFlags
+)
+
+// usePassword is true when the target is reached with the native ssh client
+func (t *RemoteTarget) usePassword() bool {
+	return t.key == "" && t.pass != ""
+}
+
+// getSSHClient returns a connected client and a function that releases it. When multiplexing,
+// one client is shared by all of the commands and file transfers to the target.
+func (t *RemoteTarget) getSSHClient() (client *ssh.Client, release func(), err error) {
+	if !t.multiplex {
+		if client, err = t.dialSSH(); err != nil {
+			return
+		}
+		release = func() { client.Close() }
+		return
+	}
+	t.clientMutex.Lock()
+	defer t.clientMutex.Unlock()
+	if t.client == nil {
+		if t.client, err = t.dialSSH(); err != nil {
+			return
+		}
+	}
+	client = t.client
+	release = func() {}
+	return
+}
+
+// resetSSHClient closes the shared client, e.g., after the connection is lost, so that the next
+// command connects again
+func (t *RemoteTarget) resetSSHClient(client *ssh.Client) {
+	t.clientMutex.Lock()
+	defer t.clientMutex.Unlock()
+	if t.client == client {
+		t.client.Close()
+		t.client = nil
+	}
+}
+
+func (t *RemoteTarget) dialSSH() (client *ssh.Client, err error) {
+	config := &ssh.ClientConfig{
+		User: t.user,
+		Auth: []ssh.AuthMethod{
+			ssh.Password(t.pass),
+			// some servers only offer keyboard-interactive, answer each prompt with the password
+			ssh.KeyboardInteractive(func(user, instruction string, questions []string, echos []bool) (answers []string, err error) {
+				for range questions {
+					answers = append(answers, t.pass)
+				}
+				return
+			}),
+		},
+		// the system ssh client is also run with StrictHostKeyChecking=no and UserKnownHostsFile=/dev/null
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // #nosec G106
+		Timeout:         sshConnectTimeout,
+	}
+	port := t.port
+	if port == "" {
+		port = "22"
+	}
+	addr := net.JoinHostPort(t.host, port)
+	var conn net.Conn
+	if t.jump != "" {
+		conn, err = dialJumpHost(t.jump, addr)
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, sshConnectTimeout)
+	}
+	if err != nil {
+		return
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	client = ssh.NewClient(sshConn, chans, reqs)
+	go keepAlive(client)
+	return
+}
+
+// keepAlive sends a keepalive request at each interval until the connection is closed, the
+// connection is closed when the server doesn't respond to sshServerAliveCountMax requests in a row
+func keepAlive(client *ssh.Client) {
+	done := make(chan struct{})
+	go func() {
+		client.Wait()
+		close(done)
+	}()
+	ticker := time.NewTicker(sshServerAliveInterval)
+	defer ticker.Stop()
+	failures := 0
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if _, _, err := client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+				failures++
+				if failures >= sshServerAliveCountMax {
+					log.Printf("ssh connection to %s not responding, closing", client.RemoteAddr())
+					client.Close()
+					return
+				}
+			} else {
+				failures = 0
+			}
+		}
+	}
+}
+
+// getJumpHostArgs returns the system ssh arguments that reach the last jump host, through the
+// preceding jump host(s) if any, and the last jump host's destination. Like -jump for the system
+// ssh client, the jump hosts are authenticated by the local ssh configuration, e.g., ssh-agent.
+func getJumpHostArgs(jump string) (args []string, destination string) {
+	hops := strings.Split(jump, ",")
+	args = []string{
+		"-o",
+		"UserKnownHostsFile=/dev/null",
+		"-o",
+		"StrictHostKeyChecking=no",
+		"-o",
+		fmt.Sprintf("ConnectTimeout=%d", int(sshConnectTimeout.Seconds())),
+	}
+	if len(hops) > 1 {
+		args = append(args, "-J", strings.Join(hops[:len(hops)-1], ","))
+	}
+	// the URI form accepts the same [user@]host[:port] as ProxyJump
+	destination = "ssh://" + hops[len(hops)-1]
+	return
+}
+
+// getJumpCommand returns the system ssh command that forwards a connection to addr through the
+// jump host(s)
+func getJumpCommand(jump string, addr string) []string {
+	args, destination := getJumpHostArgs(jump)
+	cmd := []string{"ssh"}
+	cmd = append(cmd, args...)
+	cmd = append(cmd, "-W", addr, destination)
+	return cmd
+}
+
+// getJumpCheckCommand returns the system ssh command that logs in to the last jump host, through
+// the preceding jump host(s) if any, and exits
+func getJumpCheckCommand(jump string) []string {
+	args, destination := getJumpHostArgs(jump)
+	cmd := []string{"ssh"}
+	cmd = append(cmd, args...)
+	cmd = append(cmd, destination, "--", "true")
+	return cmd
+}
+
+func dialJumpHost(jump string, addr string) (conn net.Conn, err error) {
+	jumpCommand := getJumpCommand(jump, addr)
+	cmd := exec.Command(jumpCommand[0], jumpCommand[1:]...)
+	log.Printf("run: %s", strings.Join(cmd.Args, " "))
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return
+	}
+	cmd.Stderr = os.Stderr
+	if err = cmd.Start(); err != nil {
+		return
+	}
+	conn = &commandConn{cmd: cmd, stdin: stdin, stdout: stdout}
+	return
+}
+
+// commandConn is a net.Conn over the stdin and stdout of a command, e.g., ssh -W
+type commandConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+}
+
+type commandAddr struct{}
+
+func (commandAddr) Network() string { return "pipe" }
+func (commandAddr) String() string  { return "ssh -W" }
+
+func (c *commandConn) Read(b []byte) (int, error)  { return c.stdout.Read(b) }
+func (c *commandConn) Write(b []byte) (int, error) { return c.stdin.Write(b) }
+func (c *commandConn) Close() error {
+	c.stdin.Close()
+	c.stdout.Close()
+	if c.cmd.Process != nil {
+		c.cmd.Process.Kill()
+	}
+	c.cmd.Wait()
+	return nil
+}
+func (c *commandConn) LocalAddr() net.Addr                { return commandAddr{} }
+func (c *commandConn) RemoteAddr() net.Addr               { return commandAddr{} }
+func (c *commandConn) SetDeadline(t time.Time) error      { return nil }
+func (c *commandConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *commandConn) SetWriteDeadline(t time.Time) error { return nil }
+
+// runSession runs the command in a new session, the session is killed after timeout seconds
+// when timeout is greater than zero
+func (t *RemoteTarget) runSession(command string, stdin io.Reader, stdout io.Writer, stderr io.Writer, timeout int) (exitCode int, err error) {
+	client, release, err := t.getSSHClient()
+	if err != nil {
+		return
+	}
+	defer release()
+	session, err := client.NewSession()
+	if err != nil && t.multiplex {
+		// the shared connection may have been closed, try once more with a new connection
+		t.resetSSHClient(client)
+		if client, release, err = t.getSSHClient(); err != nil {
+			return
+		}
+		defer release()
+		session, err = client.NewSession()
+	}
+	if err != nil {
+		return
+	}
+	defer session.Close()
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+	var timedOut atomic.Bool
+	if timeout > 0 {
+		timer := time.AfterFunc(time.Duration(timeout)*time.Second, func() {
+			timedOut.Store(true)
+			session.Signal(ssh.SIGKILL)
+			session.Close()
+		})
+		defer timer.Stop()
+	}
+	err = session.Run(command)
+	exitError := &ssh.ExitError{}
+	if errors.As(err, &exitError) {
+		exitCode = exitError.ExitStatus()
+	}
+	if timedOut.Load() {
+		err = fmt.Errorf("command did not finish within %d seconds", timeout)
+		exitCode = -1
+	}
+	return
+}
+
+func (t *RemoteTarget) runNativeCommand(args []string, timeout int) (stdout string, stderr string, exitCode int, err error) {
+	// like the system ssh client, the arguments are joined and run by the remote user's shell
+	command := strings.Join(args, " ")
+	log.Printf("run (%s): %s", t.host, t.maskSudoPassword(command))
+	var outbuf, errbuf strings.Builder
+	exitCode, err = t.runSession(command, nil, &outbuf, &errbuf, timeout)
+	stdout = outbuf.String()
+	stderr = errbuf.String()
+	return
+}
+
+// shellQuote quotes the string for the remote shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// pushFileNative copies the local file to the remote directory, or file path, keeping its mode
+func (t *RemoteTarget) pushFileNative(srcPath string, dstDir string) (err error) {
+	srcFileStat, err := os.Stat(srcPath)
+	if err != nil {
+		return
+	}
+	srcFile, err := os.Open(srcPath)
+	if err != nil {
+		return
+	}
+	defer srcFile.Close()
+	command := fmt.Sprintf(`dst=%s; if [ -d "$dst" ]; then dst="$dst"/%s; fi; cat > "$dst" && chmod %o "$dst"`,
+		shellQuote(dstDir), shellQuote(filepath.Base(srcPath)), srcFileStat.Mode().Perm())
+	log.Printf("push (%s): %s to %s", t.host, srcPath, dstDir)
+	var errbuf strings.Builder
+	if _, err = t.runSession(command, srcFile, io.Discard, &errbuf, 0); err != nil {
+		err = fmt.Errorf("failed to copy %s to %s:%s: %v %s", srcPath, t.host, dstDir, err, errbuf.String())
+	}
+	return
+}
+
+// pullFileNative copies the remote file to the local directory, or file path
+func (t *RemoteTarget) pullFileNative(srcPath string, dstDir string) (err error) {
+	dstPath := dstDir
+	if dstFileStat, statErr := os.Stat(dstDir); statErr == nil && dstFileStat.IsDir() {
+		dstPath = filepath.Join(dstDir, filepath.Base(srcPath))
+	}
+	dstFile, err := os.Create(dstPath)
+	if err != nil {
+		return
+	}
+	defer dstFile.Close()
+	log.Printf("pull (%s): %s to %s", t.host, srcPath, dstPath)
+	var errbuf strings.Builder
+	if _, err = t.runSession("cat "+shellQuote(srcPath), nil, dstFile, &errbuf, 0); err != nil {
+		err = fmt.Errorf("failed to copy %s:%s to %s: %v %s", t.host, srcPath, dstPath, err, errbuf.String())
+	}
+	return
+}
@@ -18,9 +18,11 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/intel/svr-info/internal/util"
+	"golang.org/x/crypto/ssh"
 )
 
 type Target interface {
@@ -28,8 +30,8 @@ type Target interface {
 	RunCommandWithTimeout(*exec.Cmd, int) (string, string, int, error)
 	CreateTempDirectory(string) (string, error)
 	GetArchitecture() (string, error)
-	PushFile(string, string) error
-	PullFile(string, string) error
+	PushFile(string, string) error // copy a local file to the target, destination is a directory or file path
+	PullFile(string, string) error // copy a file from the target, destination is a local directory or file path
 	CreateDirectory(string, string) (string, error)
 	RemoveDirectory(string) error
 	GetName() string
@@ -50,13 +52,29 @@ type RemoteTarget struct {
 	user        string
 	key         string
 	pass        string
-	sshpassPath string
 	sudo        string
+	jump        string // optional ProxyJump destination, e.g., user@bastion:22
+	multiplex   bool   // reuse one ssh connection for all commands and file transfers
 	arch        string
-}
-
-func NewRemoteTarget(name string, host string, port string, user string, key string, pass string, sshpassPath string, sudo string) *RemoteTarget {
-	t := RemoteTarget{name, host, port, user, key, pass, sshpassPath, sudo, ""}
+	client      *ssh.Client // native ssh connection, password authentication only, see ssh.go
+	clientMutex sync.Mutex
+}
+
+// NewRemoteTarget returns a target that is reached by ssh. When a password is provided, and
+// no key, the target is reached by the native ssh client, otherwise by the system's ssh and
+// scp commands.
+func NewRemoteTarget(name string, host string, port string, user string, key string, pass string, sudo string, jump string, multiplex bool) *RemoteTarget {
+	t := RemoteTarget{
+		name:      name,
+		host:      host,
+		port:      port,
+		user:      user,
+		key:       key,
+		pass:      pass,
+		sudo:      sudo,
+		jump:      jump,
+		multiplex: multiplex,
+	}
 	return &t
 }
 
@@ -80,12 +98,19 @@ func (t *RemoteTarget) getSSHFlags(scp bool) (flags []string) {
 		"ServerAliveInterval=30",
 		"-o",
 		"ServerAliveCountMax=10", // 30 * 10 = maximum 300 seconds before disconnect on no data
-		"-o",
-		"ControlPath=" + filepath.Join(os.TempDir(), "%h"), // <<<<<<<<<<<<<
-		"-o",
-		"ControlMaster=auto",
-		"-o",
-		"ControlPersist=1m",
+	}
+	if t.multiplex {
+		// the first connection becomes the master, later connections to the same host, port,
+		// and user share it until it has been idle for ControlPersist
+		multiplexFlags := []string{
+			"-o",
+			"ControlPath=" + filepath.Join(os.TempDir(), "%C"), // %C - hash of local host, remote host, port, and user
+			"-o",
+			"ControlMaster=auto",
+			"-o",
+			"ControlPersist=1m",
+		}
+		flags = append(flags, multiplexFlags...)
 	}
 	if t.key != "" {
 		keyFlags := []string{
@@ -98,6 +123,10 @@ func (t *RemoteTarget) getSSHFlags(scp bool) (flags []string) {
 		}
 		flags = append(flags, keyFlags...)
 	}
+	if t.jump != "" {
+		// the jump host is authenticated by the local ssh configuration, e.g., ssh-agent
+		flags = append(flags, "-o", "ProxyJump="+t.jump)
+	}
 	if t.port != "" {
 		if scp {
 			flags = append(flags, "-P")
@@ -123,24 +152,24 @@ func (t *RemoteTarget) getSSHCommand(command []string) []string {
 	return cmd
 }
 
-func (t *RemoteTarget) getSCPCommand(src string, dstDir string, push bool) []string {
+func (t *RemoteTarget) getSCPCommand(src string, dst string, push bool) []string {
 	var cmd []string
 	cmd = append(cmd, "scp")
 	cmd = append(cmd, t.getSSHFlags(true)...)
 	if push {
 		cmd = append(cmd, src)
-		dst := t.host + ":" + dstDir
+		d := t.host + ":" + dst
 		if t.user != "" {
-			dst = t.user + "@" + dst
+			d = t.user + "@" + d
 		}
-		cmd = append(cmd, dst)
+		cmd = append(cmd, d)
 	} else { // pull
 		s := t.host + ":" + src
 		if t.user != "" {
 			s = t.user + "@" + s
 		}
 		cmd = append(cmd, s)
-		cmd = append(cmd, dstDir)
+		cmd = append(cmd, dst)
 	}
 	return cmd
 }
@@ -173,30 +202,23 @@ func (t *LocalTarget) RunCommand(cmd *exec.Cmd) (stdout string, stderr string, e
 }
 
 func (t *RemoteTarget) RunCommandWithTimeout(cmd *exec.Cmd, timeout int) (stdout string, stderr string, exitCode int, err error) {
-	sshCommand := t.getSSHCommand(cmd.Args)
-	var name string
-	var args []string
-	if t.key == "" && t.pass != "" {
-		name = t.sshpassPath
-		args = append(args, "-e")
-		args = append(args, "--")
-		args = append(args, sshCommand...)
-	} else {
-		name = sshCommand[0]
-		args = sshCommand[1:]
-	}
-	localCommand := exec.Command(name, args...)
-	if t.key == "" && t.pass != "" {
-		localCommand.Env = append(localCommand.Env, "SSHPASS="+t.pass)
-	}
-	logOut := strings.Join(localCommand.Args, " ")
-	if t.sudo != "" {
-		logOut = strings.Replace(logOut, "SUDO_PASSWORD="+t.sudo, "SUDO_PASSWORD=*************", -1)
+	if t.usePassword() {
+		return t.runNativeCommand(cmd.Args, timeout)
 	}
-	log.Printf("run: %s", logOut)
+	sshCommand := t.getSSHCommand(cmd.Args)
+	localCommand := exec.Command(sshCommand[0], sshCommand[1:]...)
+	log.Printf("run: %s", t.maskSudoPassword(strings.Join(localCommand.Args, " ")))
 	return RunLocalCommandWithTimeout(localCommand, timeout)
 }
 
+// maskSudoPassword hides the sudo password in commands that are logged
+func (t *RemoteTarget) maskSudoPassword(command string) string {
+	if t.sudo == "" {
+		return command
+	}
+	return strings.Replace(command, "SUDO_PASSWORD="+t.sudo, "SUDO_PASSWORD=*************", -1)
+}
+
 func (t *RemoteTarget) RunCommand(cmd *exec.Cmd) (stdout string, stderr string, exitCode int, err error) {
 	return t.RunCommandWithTimeout(cmd, 0)
 }
@@ -285,6 +307,7 @@ func (t *LocalTarget) PushFile(srcPath string, dstPath string) (err error) {
 	dstFile.Close()
 	if err != nil {
 		log.Printf("failed to copy %s to %s", srcPath, dstFilename)
+		return
 	}
 	err = os.Chmod(dstFilename, srcFileStat.Mode())
 	if err != nil {
@@ -293,50 +316,39 @@ func (t *LocalTarget) PushFile(srcPath string, dstPath string) (err error) {
 	return
 }
 
-func (t *RemoteTarget) PushFile(srcPath string, dstDir string) (err error) {
-	scpCommand := t.getSCPCommand(srcPath, dstDir, true)
-	var name string
-	var args []string
-	if t.key == "" && t.pass != "" {
-		name = t.sshpassPath
-		args = append(args, "-e")
-		args = append(args, "--")
-		args = append(args, scpCommand...)
-	} else {
-		name = scpCommand[0]
-		args = scpCommand[1:]
-	}
-	localCommand := exec.Command(name, args...)
-	if t.key == "" && t.pass != "" {
-		localCommand.Env = append(localCommand.Env, "SSHPASS="+t.pass)
+// PushFile copies the local file to the target by scp, or by the native ssh client when
+// authenticating with a password
+//
+//	srcPath: full path to local source file
+//	dstPath: remote destination directory or full path to remote destination file
+func (t *RemoteTarget) PushFile(srcPath string, dstPath string) (err error) {
+	if t.usePassword() {
+		return t.pushFileNative(srcPath, dstPath)
 	}
+	scpCommand := t.getSCPCommand(srcPath, dstPath, true)
+	localCommand := exec.Command(scpCommand[0], scpCommand[1:]...)
 	log.Printf("run: %s", strings.Join(localCommand.Args, " "))
 	_, _, _, err = RunLocalCommand(localCommand)
 	return
 }
 
-func (t *LocalTarget) PullFile(srcPath string, dstDir string) (err error) {
-	err = t.PushFile(srcPath, dstDir)
+// PullFile copies file from src to dst, same as PushFile for the local target
+func (t *LocalTarget) PullFile(srcPath string, dstPath string) (err error) {
+	err = t.PushFile(srcPath, dstPath)
 	return
 }
 
-func (t *RemoteTarget) PullFile(srcPath string, dstDir string) (err error) {
-	scpCommand := t.getSCPCommand(srcPath, dstDir, false)
-	var name string
-	var args []string
-	if t.key == "" && t.pass != "" {
-		name = t.sshpassPath
-		args = append(args, "-e")
-		args = append(args, "--")
-		args = append(args, scpCommand...)
-	} else {
-		name = scpCommand[0]
-		args = scpCommand[1:]
-	}
-	localCommand := exec.Command(name, args...)
-	if t.key == "" && t.pass != "" {
-		localCommand.Env = append(localCommand.Env, "SSHPASS="+t.pass)
+// PullFile copies the file from the target by scp, or by the native ssh client when
+// authenticating with a password
+//
+//	srcPath: full path to remote source file
+//	dstPath: local destination directory or full path to local destination file
+func (t *RemoteTarget) PullFile(srcPath string, dstPath string) (err error) {
+	if t.usePassword() {
+		return t.pullFileNative(srcPath, dstPath)
 	}
+	scpCommand := t.getSCPCommand(srcPath, dstPath, false)
+	localCommand := exec.Command(scpCommand[0], scpCommand[1:]...)
 	log.Printf("run: %s", strings.Join(localCommand.Args, " "))
 	_, _, _, err = RunLocalCommand(localCommand)
 	return
@@ -379,6 +391,22 @@ func (t *LocalTarget) CanConnect() bool {
 	return true
 }
 
+// CheckJumpHost confirms that the jump host(s), if any, can be logged in to with the system ssh
+// client, the same way the connections to the target are made through them, so that an
+// unreachable jump host can be distinguished from an unreachable target
+func (t *RemoteTarget) CheckJumpHost() (err error) {
+	if t.jump == "" {
+		return
+	}
+	jumpCommand := getJumpCheckCommand(t.jump)
+	cmd := exec.Command(jumpCommand[0], jumpCommand[1:]...)
+	_, stderr, _, err := RunLocalCommandWithTimeout(cmd, int(2*sshConnectTimeout.Seconds()))
+	if err != nil {
+		err = fmt.Errorf("jump host %s is unreachable: %v %s", t.jump, err, strings.TrimSpace(stderr))
+	}
+	return
+}
+
 func (t *RemoteTarget) CanConnect() bool {
 	cmd := exec.Command("exit", "0")
 	_, _, _, err := t.RunCommandWithTimeout(cmd, 5)
@@ -5,6 +5,9 @@
 package target
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -13,8 +16,110 @@ func TestNew(t *testing.T) {
 	if localTarget == nil {
 		t.Fatal("failed to create a local target")
 	}
-	remoteTarget := NewRemoteTarget("label", "hostname", "22", "user", "key", "pass", "sshpass", "sudo")
+	remoteTarget := NewRemoteTarget("label", "hostname", "22", "user", "key", "pass", "sudo", "", true)
 	if remoteTarget == nil {
 		t.Fatal("failed to create a remote target")
 	}
 }
+
+func TestJumpHost(t *testing.T) {
+	remoteTarget := NewRemoteTarget("label", "hostname", "22", "user", "", "", "", "admin@bastion:2222", true)
+	flags := strings.Join(remoteTarget.getSSHFlags(false), " ")
+	if !strings.Contains(flags, "ProxyJump=admin@bastion:2222") {
+		t.Fatalf("ProxyJump not found in ssh flags: %s", flags)
+	}
+	checkCommand := strings.Join(getJumpCheckCommand("admin@bastion1,admin@bastion2:2222"), " ")
+	if !strings.Contains(checkCommand, "-J admin@bastion1 ssh://admin@bastion2:2222 -- true") {
+		t.Fatalf("unexpected jump host check command: %s", checkCommand)
+	}
+	// nothing listens on port 1
+	remoteTarget = NewRemoteTarget("label", "hostname", "22", "user", "", "", "", "admin@127.0.0.1:1", true)
+	if err := remoteTarget.CheckJumpHost(); err == nil {
+		t.Fatal("expected unreachable jump host error")
+	}
+	remoteTarget = NewRemoteTarget("label", "hostname", "22", "user", "", "", "", "", true)
+	if err := remoteTarget.CheckJumpHost(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMultiplex(t *testing.T) {
+	remoteTarget := NewRemoteTarget("label", "hostname", "22", "user", "", "", "", "", true)
+	flags := strings.Join(remoteTarget.getSSHFlags(false), " ")
+	if !strings.Contains(flags, "ControlMaster=auto") || !strings.Contains(flags, "%C") {
+		t.Fatalf("multiplexing not found in ssh flags: %s", flags)
+	}
+	remoteTarget = NewRemoteTarget("label", "hostname", "22", "user", "", "", "", "", false)
+	flags = strings.Join(remoteTarget.getSSHFlags(false), " ")
+	if strings.Contains(flags, "ControlMaster") {
+		t.Fatalf("unexpected multiplexing in ssh flags: %s", flags)
+	}
+}
+
+func TestNativeSSH(t *testing.T) {
+	remoteTarget := NewRemoteTarget("label", "hostname", "22", "user", "", "pass", "", "", true)
+	if !remoteTarget.usePassword() {
+		t.Fatal("expected the native ssh client for password authentication")
+	}
+	remoteTarget = NewRemoteTarget("label", "hostname", "22", "user", "key", "pass", "", "", true)
+	if remoteTarget.usePassword() {
+		t.Fatal("expected the system ssh client for key authentication")
+	}
+	if quoted := shellQuote("it's"); quoted != `'it'\''s'` {
+		t.Fatalf("unexpected quoting: %s", quoted)
+	}
+	jumpCommand := strings.Join(getJumpCommand("admin@bastion1,admin@bastion2:2222", "hostname:22"), " ")
+	if !strings.Contains(jumpCommand, "-J admin@bastion1 -W hostname:22 ssh://admin@bastion2:2222") {
+		t.Fatalf("unexpected jump command: %s", jumpCommand)
+	}
+}
+
+func TestPushPullFile(t *testing.T) {
+	localTarget := NewLocalTarget("hostname", "")
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+	srcPath := filepath.Join(srcDir, "script.sh")
+	if err := os.WriteFile(srcPath, []byte("echo hello\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// to a directory, keeps the file name and mode
+	if err := localTarget.PushFile(srcPath, dstDir); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(dstDir, "script.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Fatalf("unexpected mode: %o", info.Mode().Perm())
+	}
+	// to a file path
+	dstPath := filepath.Join(dstDir, "renamed.sh")
+	if err := localTarget.PullFile(srcPath, dstPath); err != nil {
+		t.Fatal(err)
+	}
+	if content, err := os.ReadFile(dstPath); err != nil || string(content) != "echo hello\n" {
+		t.Fatalf("unexpected content: %s, %v", content, err)
+	}
+	if err := localTarget.PushFile(filepath.Join(srcDir, "missing"), dstDir); err == nil {
+		t.Fatal("expected error for missing source file")
+	}
+	if err := localTarget.PushFile(srcDir, dstDir); err == nil {
+		t.Fatal("expected error for directory source")
+	}
+}
+
+func TestSCPCommand(t *testing.T) {
+	remoteTarget := NewRemoteTarget("label", "hostname", "2222", "user", "key", "", "", "", false)
+	push := remoteTarget.getSCPCommand("/tmp/script.sh", "/tmp/dir", true)
+	if push[0] != "scp" || strings.Join(push[len(push)-2:], " ") != "/tmp/script.sh user@hostname:/tmp/dir" {
+		t.Fatalf("unexpected push command: %v", push)
+	}
+	if !strings.Contains(strings.Join(push, " "), "-P 2222") {
+		t.Fatalf("port not found in push command: %v", push)
+	}
+	pull := remoteTarget.getSCPCommand("/tmp/core", "/tmp/out/core", false)
+	if strings.Join(pull[len(pull)-2:], " ") != "user@hostname:/tmp/core /tmp/out/core" {
+		t.Fatalf("unexpected pull command: %v", pull)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -97,3 +98,36 @@ func StringInList(s string, l []string) bool {
 	}
 	return false
 }
+
+// ParseCPUList expands a list of CPUs in the kernel's cpulist format, e.g., "0-3,8,10-11".
+// An empty list, e.g., a memory-only NUMA node's, has no CPUs.
+func ParseCPUList(cpuList string) (cpus []int, err error) {
+	if cpuList == "" {
+		return
+	}
+	for _, token := range strings.Split(cpuList, ",") {
+		bounds := strings.Split(token, "-")
+		if len(bounds) > 2 {
+			err = fmt.Errorf("invalid CPU range: %s", token)
+			return
+		}
+		var first, last int
+		if first, err = strconv.Atoi(bounds[0]); err != nil {
+			return
+		}
+		last = first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				return
+			}
+		}
+		if last < first {
+			err = fmt.Errorf("invalid CPU range: %s", token)
+			return
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return
+}
@@ -0,0 +1,35 @@
+/*
+ * Copyright (C) 2023 Intel Corporation
+ * SPDX-License-Identifier: MIT
+ */
+package util
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseCPUList(t *testing.T) {
+	cpus, err := ParseCPUList("0-3,8,10-11")
+	if err != nil {
+		t.Error(err)
+	}
+	if !slices.Equal(cpus, []int{0, 1, 2, 3, 8, 10, 11}) {
+		t.Errorf("unexpected cpus: %v", cpus)
+	}
+	if cpus, err = ParseCPUList(""); err != nil || len(cpus) != 0 {
+		t.Errorf("expected no cpus, got %v, %v", cpus, err)
+	}
+	if _, err = ParseCPUList("0-3-5"); err == nil {
+		t.Error("didn't catch invalid range")
+	}
+	if _, err = ParseCPUList("a-b"); err == nil {
+		t.Error("didn't catch non-numeric range")
+	}
+	if _, err = ParseCPUList("5-3"); err == nil {
+		t.Error("didn't catch descending range")
+	}
+	if _, err = ParseCPUList("1,,2"); err == nil {
+		t.Error("didn't catch empty entry")
+	}
+}